@@ -13,16 +13,20 @@ import (
 	collectdiskcertificates "github.com/openshift/origin/pkg/cmd/openshift-tests/collect-disk-certificates"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/dev"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/disruption"
+	explain_selection "github.com/openshift/origin/pkg/cmd/openshift-tests/explain-selection"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/images"
+	merge_results "github.com/openshift/origin/pkg/cmd/openshift-tests/merge-results"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor"
 	run_monitor "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/run"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/timeline"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/quarantine"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/render"
 	risk_analysis "github.com/openshift/origin/pkg/cmd/openshift-tests/risk-analysis"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/run"
 	run_disruption "github.com/openshift/origin/pkg/cmd/openshift-tests/run-disruption"
 	run_test "github.com/openshift/origin/pkg/cmd/openshift-tests/run-test"
 	run_upgrade "github.com/openshift/origin/pkg/cmd/openshift-tests/run-upgrade"
+	snapshot_cluster "github.com/openshift/origin/pkg/cmd/openshift-tests/snapshot-cluster"
 	run_resourcewatch "github.com/openshift/origin/pkg/resourcewatch/cmd"
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 	exutil "github.com/openshift/origin/test/extended/util"
@@ -78,6 +82,7 @@ func main() {
 	root.AddCommand(
 		run.NewRunCommand(ioStreams),
 		run_upgrade.NewRunUpgradeCommand(ioStreams),
+		merge_results.NewMergeResultsCommand(ioStreams),
 		images.NewImagesCommand(),
 		run_test.NewRunTestCommand(ioStreams),
 		dev.NewDevCommand(),
@@ -90,6 +95,9 @@ func main() {
 		run_disruption.NewRunInClusterDisruptionMonitorCommand(ioStreams),
 		collectdiskcertificates.NewRunCollectDiskCertificatesCommand(ioStreams),
 		render.NewRenderCommand(ioStreams),
+		quarantine.NewQuarantineCommand(ioStreams),
+		explain_selection.NewExplainSelectionCommand(ioStreams),
+		snapshot_cluster.NewSnapshotClusterCommand(ioStreams),
 	)
 
 	f := flag.CommandLine.Lookup("v")
@@ -12,8 +12,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	utilnet "k8s.io/utils/net"
@@ -22,6 +25,7 @@ import (
 	operatorv1 "github.com/openshift/api/operator/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	exutil "github.com/openshift/origin/test/extended/util"
 	"github.com/openshift/origin/test/extended/util/azure"
 )
 
@@ -90,6 +94,36 @@ type ClusterState struct {
 	NetworkSpec          *operatorv1.NetworkSpec
 	ControlPlaneTopology *configv1.TopologyMode
 	OptionalCapabilities []configv1.ClusterVersionCapability
+
+	// The fields below are only set by SnapshotClusterState, to select the tests of the cluster without reaching it.
+	// ServerVersion is set for every snapshot.
+	ServerVersion  *version.Info            `json:",omitempty"`
+	APIGroups      []string                 `json:",omitempty"`
+	Infrastructure *configv1.Infrastructure `json:",omitempty"`
+	Network        *configv1.Network        `json:",omitempty"`
+	ClusterVersion *configv1.ClusterVersion `json:",omitempty"`
+	// FeatureGate is nil when the cluster has no FeatureGate.
+	FeatureGate *configv1.FeatureGate `json:",omitempty"`
+	// CSIDriverManifests are the storage capabilities of the cluster, from TEST_CSI_DRIVER_FILES.
+	CSIDriverManifests []CSIDriverManifest `json:",omitempty"`
+	// IsMicroShift is set for the snapshots of MicroShift clusters.
+	IsMicroShift bool `json:",omitempty"`
+	// ReleaseReferences are the image references of the release payload, as printed by
+	// "oc adm release info -ojsonpath={.references}", set when the cluster has no optional capabilities.
+	ReleaseReferences string `json:",omitempty"`
+	// ExternalTests are the tests of the external binaries of the release payload, set by snapshot-cluster.
+	ExternalTests []ExternalTest `json:",omitempty"`
+}
+
+// ExternalTest is a test of an external binary, recorded to select it without extracting the binary.
+type ExternalTest struct {
+	// Name is the name of the test, labels included.
+	Name string
+	// ImageTag and Path locate the binary of the test in the release payload.
+	ImageTag string
+	Path     string
+	// ReplacesBuiltInTests is the part of the names of the built-in tests the binary replaces, if any.
+	ReplacesBuiltInTests string `json:",omitempty"`
 }
 
 // DiscoverClusterState creates a ClusterState based on a live cluster
@@ -157,6 +191,118 @@ func DiscoverClusterState(clientConfig *rest.Config) (*ClusterState, error) {
 	return state, nil
 }
 
+// SnapshotClusterState creates a ClusterState of a live cluster with everything needed to select its tests once
+// the cluster is gone, see LoadClusterState.
+func SnapshotClusterState(clientConfig *rest.Config) (*ClusterState, error) {
+	state, err := DiscoverClusterState(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	configClient, err := configclient.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	state.ServerVersion, err = discoveryClient.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups.Groups {
+		state.APIGroups = append(state.APIGroups, group.Name)
+	}
+
+	state.Infrastructure, err = configClient.ConfigV1().Infrastructures().Get(context.Background(), "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	state.Network, err = configClient.ConfigV1().Networks().Get(context.Background(), "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	state.ClusterVersion, err = configClient.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	featureGate, err := configClient.ConfigV1().FeatureGates().Get(context.Background(), "cluster", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	default:
+		state.FeatureGate = featureGate
+	}
+	// the managed fields only make the snapshot harder to review
+	state.Infrastructure.ManagedFields = nil
+	state.Network.ManagedFields = nil
+	state.ClusterVersion.ManagedFields = nil
+	if state.FeatureGate != nil {
+		state.FeatureGate.ManagedFields = nil
+	}
+
+	state.CSIDriverManifests, err = readCSIDriverManifests()
+	if err != nil {
+		return nil, err
+	}
+
+	// the images of clusters without optional capabilities are resolved from the release payload, see
+	// InitializeTestFramework.
+	coreClient, err := clientset.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	state.IsMicroShift, err = exutil.IsMicroShiftCluster(coreClient)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(state)
+	if err != nil {
+		return nil, err
+	}
+	if config.HasNoOptionalCapabilities && !state.IsMicroShift {
+		state.ReleaseReferences, _, err = exutil.NewCLIWithoutNamespace("").AsAdmin().Run("adm", "release", "info", `-ojsonpath={.references}`).Outputs()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// LoadClusterState reads a ClusterState serialized as JSON, to select tests without a live cluster.
+func LoadClusterState(path string) (*ClusterState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	state := &ClusterState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to read cluster state %q: %w", path, err)
+	}
+	switch {
+	case state.APIURL == nil:
+		return nil, fmt.Errorf("cluster state %q is missing APIURL", path)
+	case state.PlatformStatus == nil:
+		return nil, fmt.Errorf("cluster state %q is missing PlatformStatus", path)
+	case state.ControlPlaneTopology == nil:
+		return nil, fmt.Errorf("cluster state %q is missing ControlPlaneTopology", path)
+	case state.Masters == nil || state.NonMasters == nil:
+		return nil, fmt.Errorf("cluster state %q is missing Masters or NonMasters", path)
+	case state.NetworkSpec == nil:
+		return nil, fmt.Errorf("cluster state %q is missing NetworkSpec", path)
+	case state.ServerVersion != nil && state.ClusterVersion == nil:
+		return nil, fmt.Errorf("cluster state %q is missing ClusterVersion", path)
+	}
+	return state, nil
+}
+
 // LoadConfig generates a ClusterConfiguration based on a detected or hard-coded ClusterState
 func LoadConfig(state *ClusterState) (*ClusterConfiguration, error) {
 	zones := sets.NewString()
@@ -275,6 +421,30 @@ func LoadConfig(state *ClusterState) (*ClusterConfiguration, error) {
 // MatchFn returns a function that tests if a named function should be run based on
 // the cluster configuration
 func (c *ClusterConfiguration) MatchFn() func(string) bool {
+	skips := c.skips()
+	matchFn := func(name string) bool {
+		for _, skip := range skips {
+			if strings.Contains(name, skip) {
+				return false
+			}
+		}
+		return true
+	}
+	return matchFn
+}
+
+// SkippedBy returns the label skipping the test on the cluster, or an empty string when the test runs.
+func (c *ClusterConfiguration) SkippedBy(name string) string {
+	for _, skip := range c.skips() {
+		if strings.Contains(name, skip) {
+			return skip
+		}
+	}
+	return ""
+}
+
+// skips are the labels of the tests that don't run on the cluster.
+func (c *ClusterConfiguration) skips() []string {
 	var skips []string
 	skips = append(skips, fmt.Sprintf("[Skipped:%s]", c.ProviderName))
 
@@ -319,13 +489,5 @@ func (c *ClusterConfiguration) MatchFn() func(string) bool {
 		skips = append(skips, "[Skipped:NoOptionalCapabilities]")
 	}
 
-	matchFn := func(name string) bool {
-		for _, skip := range skips {
-			if strings.Contains(name, skip) {
-				return false
-			}
-		}
-		return true
-	}
-	return matchFn
+	return skips
 }
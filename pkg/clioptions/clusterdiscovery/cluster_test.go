@@ -0,0 +1,87 @@
+package clusterdiscovery
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestLoadClusterState(t *testing.T) {
+	os.Unsetenv("KUBE_SSH_USER")
+	os.Unsetenv("LOCAL_SSH_KEY")
+
+	topology := configv1.HighlyAvailableTopologyMode
+	testURL, _ := url.Parse("https://example.com")
+	snapshot := &ClusterState{
+		APIURL:               testURL,
+		PlatformStatus:       awsPlatform,
+		Masters:              simpleMasters,
+		NonMasters:           nonMasters,
+		NetworkSpec:          ovnKubernetesConfig,
+		ControlPlaneTopology: &topology,
+		ServerVersion:        &version.Info{Major: "1", Minor: "29"},
+		APIGroups:            []string{"", "route.openshift.io"},
+		ClusterVersion:       &configv1.ClusterVersion{},
+	}
+	withoutClusterVersion := *snapshot
+	withoutClusterVersion.ClusterVersion = nil
+
+	dir := t.TempDir()
+	writeState := func(name string, state *ClusterState) string {
+		data, err := json.Marshal(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	state, err := LoadClusterState(writeState("snapshot.json", snapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ServerVersion.Minor != "29" || len(state.APIGroups) != 2 {
+		t.Errorf("expected the snapshot to be loaded, got %#v", state)
+	}
+	// a dry run selects the tests for the platform of the cluster state
+	config, err := DecodeProvider("", true, true, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ProviderName != "aws" {
+		t.Errorf("expected provider aws, got %s", config.ProviderName)
+	}
+
+	_, err = LoadClusterState(writeState("invalid.json", &withoutClusterVersion))
+	if err == nil || !strings.Contains(err.Error(), "is missing ClusterVersion") {
+		t.Errorf("expected a missing ClusterVersion error, got %v", err)
+	}
+}
+
+func TestRestoreCSIDriverManifests(t *testing.T) {
+	t.Setenv(CSIManifestEnvVar, "")
+
+	manifests := []CSIDriverManifest{{Path: "/manifests/csi/manifest.yaml", Content: "ShortName: ebs\n"}}
+	if err := restoreCSIDriverManifests(manifests); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := readCSIDriverManifests()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, manifest := range restored {
+		defer os.RemoveAll(filepath.Dir(manifest.Path))
+	}
+	if len(restored) != 1 || restored[0].Content != manifests[0].Content || filepath.Base(restored[0].Path) != "0-manifest.yaml" {
+		t.Errorf("expected the manifest to be restored, got %#v", restored)
+	}
+}
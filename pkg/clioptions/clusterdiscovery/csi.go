@@ -34,3 +34,47 @@ func initCSITests(dryRun bool) error {
 
 	return nil
 }
+
+// CSIDriverManifest is a manifest of TEST_CSI_DRIVER_FILES, defining the tests and the storage capabilities of a
+// CSI driver.
+type CSIDriverManifest struct {
+	Path    string
+	Content string
+}
+
+func readCSIDriverManifests() ([]CSIDriverManifest, error) {
+	manifestList := os.Getenv(CSIManifestEnvVar)
+	if manifestList == "" {
+		return nil, nil
+	}
+	var manifests []CSIDriverManifest
+	for _, manifest := range strings.Split(manifestList, ",") {
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest %q: %w", manifest, err)
+		}
+		manifests = append(manifests, CSIDriverManifest{Path: manifest, Content: string(content)})
+	}
+	return manifests, nil
+}
+
+// restoreCSIDriverManifests writes the manifests of a cluster state to a temporary directory and points
+// TEST_CSI_DRIVER_FILES at them, unless it is already set.
+func restoreCSIDriverManifests(manifests []CSIDriverManifest) error {
+	if len(manifests) == 0 || os.Getenv(CSIManifestEnvVar) != "" {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", "csi-manifests")
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for i, manifest := range manifests {
+		path := filepath.Join(dir, fmt.Sprintf("%d-%s", i, filepath.Base(manifest.Path)))
+		if err := os.WriteFile(path, []byte(manifest.Content), 0644); err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+	return os.Setenv(CSIManifestEnvVar, strings.Join(paths, ","))
+}
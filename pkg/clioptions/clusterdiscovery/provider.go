@@ -34,6 +34,56 @@ import (
 )
 
 func InitializeTestFramework(context *e2e.TestContextType, config *ClusterConfiguration, dryRun bool) error {
+	if err := initializeTestContext(context, config, dryRun); err != nil {
+		return err
+	}
+
+	coreClient, err := e2e.LoadClientset(true)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(coreClient)
+	if err != nil {
+		return err
+	}
+	// As an extra precaution for now, we do not run this check on all tests since some might fail to pull
+	// release payload information
+	if config.HasNoOptionalCapabilities && !isMicroShift {
+		imageStreamString, _, err := exutil.NewCLIWithoutNamespace("").AsAdmin().Run("adm", "release", "info", `-ojsonpath={.references}`).Outputs()
+		if err != nil {
+			return err
+		}
+
+		if err := image.InitializeReleasePullSpecString(imageStreamString, config.HasNoOptionalCapabilities); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InitializeTestFrameworkForClusterState initializes the test framework to list the tests of the cluster of the
+// state without reaching it, for dry runs.
+func InitializeTestFrameworkForClusterState(context *e2e.TestContextType, config *ClusterConfiguration, state *ClusterState) error {
+	if err := restoreCSIDriverManifests(state.CSIDriverManifests); err != nil {
+		return fmt.Errorf("failed to restore the CSI driver manifests of the cluster state: %w", err)
+	}
+	if err := initializeTestContext(context, config, true); err != nil {
+		return err
+	}
+
+	if config.HasNoOptionalCapabilities && !state.IsMicroShift {
+		if len(state.ReleaseReferences) == 0 {
+			return fmt.Errorf("the cluster has no optional capabilities but its state has no ReleaseReferences, snapshot the cluster again")
+		}
+		if err := image.InitializeReleasePullSpecString(state.ReleaseReferences, config.HasNoOptionalCapabilities); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func initializeTestContext(context *e2e.TestContextType, config *ClusterConfiguration, dryRun bool) error {
 	// update context with loaded config
 	context.Provider = config.ProviderName
 	context.CloudConfig = e2e.CloudConfig{
@@ -69,28 +119,6 @@ func InitializeTestFramework(context *e2e.TestContextType, config *ClusterConfig
 
 	// IPFamily constants are taken from kube e2e and used by tests
 	context.IPFamily = config.IPFamily
-
-	coreClient, err := e2e.LoadClientset(true)
-	if err != nil {
-		return err
-	}
-	isMicroShift, err := exutil.IsMicroShiftCluster(coreClient)
-	if err != nil {
-		return err
-	}
-	// As an extra precaution for now, we do not run this check on all tests since some might fail to pull
-	// release payload information
-	if config.HasNoOptionalCapabilities && !isMicroShift {
-		imageStreamString, _, err := exutil.NewCLIWithoutNamespace("").AsAdmin().Run("adm", "release", "info", `-ojsonpath={.references}`).Outputs()
-		if err != nil {
-			return err
-		}
-
-		if err := image.InitializeReleasePullSpecString(imageStreamString, config.HasNoOptionalCapabilities); err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -130,7 +158,7 @@ func DecodeProvider(providerTypeOrJSON string, dryRun, discover bool, clusterSta
 				return &ClusterConfiguration{ProviderName: "local"}, nil
 			}
 		}
-		if dryRun {
+		if dryRun && clusterState == nil {
 			return &ClusterConfiguration{ProviderName: "skeleton"}, nil
 		}
 		fallthrough
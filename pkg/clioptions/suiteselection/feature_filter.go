@@ -3,11 +3,14 @@ package suiteselection
 import (
 	"context"
 	"fmt"
-	"regexp"
+	"strings"
 
+	configv1 "github.com/openshift/api/config/v1"
 	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 )
 
 type featureGateFilter struct {
@@ -25,6 +28,11 @@ func newFeatureGateFilter(ctx context.Context, configClient clientconfigv1.Inter
 		return nil, err
 	}
 
+	return newFeatureGateFilterFor(featureGate, clusterVersion)
+}
+
+// newFeatureGateFilterFor returns the filter for the feature gates of the version the cluster is going to.
+func newFeatureGateFilterFor(featureGate *configv1.FeatureGate, clusterVersion *configv1.ClusterVersion) (*featureGateFilter, error) {
 	desiredVersion := clusterVersion.Status.Desired.Version
 	if len(desiredVersion) == 0 && len(clusterVersion.Status.History) > 0 {
 		desiredVersion = clusterVersion.Status.History[0].Version
@@ -55,17 +63,9 @@ func newFeatureGateFilter(ctx context.Context, configClient clientconfigv1.Inter
 	return ret, nil
 }
 
+// includeTest returns true if the cluster enables every feature gate the test is labeled with.
 func (f *featureGateFilter) includeTest(name string) bool {
-	featureGates := []string{}
-	matches := featureGateRegex.FindAllStringSubmatch(name, -1)
-	for _, match := range matches {
-		if len(match) < 2 {
-			panic(fmt.Errorf("regexp match %v is invalid: len(match) < 2 for %v", match, name))
-		}
-		featureGate := match[1]
-		featureGates = append(featureGates, featureGate)
-	}
-
+	featureGates := testginkgo.TestLabelsOf(name).Values(testginkgo.LabelFeatureGate)
 	if f.disabled.HasAny(featureGates...) {
 		return false
 	}
@@ -73,10 +73,23 @@ func (f *featureGateFilter) includeTest(name string) bool {
 	return f.enabled.HasAll(featureGates...)
 }
 
-func includeNonFeatureGateTest(name string) bool {
-	return featureGateRegex.FindAllStringSubmatch(name, -1) == nil
+// explain lists the feature gates of the test the cluster disables or doesn't know.
+func (f *featureGateFilter) explain(name string) string {
+	featureGates := sets.NewString(testginkgo.TestLabelsOf(name).Values(testginkgo.LabelFeatureGate)...)
+	var reasons []string
+	if disabled := featureGates.Intersection(f.disabled); disabled.Len() > 0 {
+		reasons = append(reasons, fmt.Sprintf("the cluster disables %s", strings.Join(disabled.List(), ", ")))
+	}
+	if unknown := featureGates.Difference(f.enabled).Difference(f.disabled); unknown.Len() > 0 {
+		reasons = append(reasons, fmt.Sprintf("the cluster doesn't know %s", strings.Join(unknown.List(), ", ")))
+	}
+	return strings.Join(reasons, ", ")
 }
 
-var (
-	featureGateRegex = regexp.MustCompile(`\[OCPFeatureGate:([^]]*)\]`)
-)
+func explainNonFeatureGateTest(name string) string {
+	return "the cluster has no FeatureGate"
+}
+
+func includeNonFeatureGateTest(name string) bool {
+	return !testginkgo.TestLabelsOf(name).Has(testginkgo.LabelFeatureGate)
+}
@@ -2,10 +2,12 @@ package suiteselection
 
 import (
 	"fmt"
-	"regexp"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
+
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 )
 
 type apiGroupFilter struct {
@@ -18,34 +20,32 @@ func newApiGroupFilter(discoveryClient discovery.AggregatedDiscoveryInterface) (
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve served resources: %v", err)
 	}
-	apiGroups := sets.NewString()
+	var groupNames []string
 	for _, apiGroup := range groups.Groups {
-		// ignore the empty group
-		if apiGroup.Name == "" {
-			continue
-		}
-		apiGroups.Insert(apiGroup.Name)
+		groupNames = append(groupNames, apiGroup.Name)
 	}
 
+	return newApiGroupFilterForGroups(groupNames), nil
+}
+
+// newApiGroupFilterForGroups returns the filter for a cluster serving the API groups.
+func newApiGroupFilterForGroups(groupNames []string) *apiGroupFilter {
+	apiGroups := sets.NewString(groupNames...)
+	// ignore the empty group
+	apiGroups.Delete("")
+
 	return &apiGroupFilter{
 		apiGroups: apiGroups,
-	}, nil
+	}
 }
 
-var (
-	apiGroupRegex = regexp.MustCompile(`\[apigroup:([^]]*)\]`)
-)
-
+// includeTest returns true if the cluster serves every API group the test is labeled with.
 func (agf *apiGroupFilter) includeTest(name string) bool {
-	apiGroups := []string{}
-	matches := apiGroupRegex.FindAllStringSubmatch(name, -1)
-	for _, match := range matches {
-		if len(match) < 2 {
-			panic(fmt.Errorf("regexp match %v is invalid: len(match) < 2 for %v", match, name))
-		}
-		apigroup := match[1]
-		apiGroups = append(apiGroups, apigroup)
-	}
+	return agf.apiGroups.HasAll(testginkgo.TestLabelsOf(name).Values(testginkgo.LabelAPIGroup)...)
+}
 
-	return agf.apiGroups.HasAll(apiGroups...)
+// explain lists the API groups of the test the cluster doesn't serve.
+func (agf *apiGroupFilter) explain(name string) string {
+	missing := sets.NewString(testginkgo.TestLabelsOf(name).Values(testginkgo.LabelAPIGroup)...).Difference(agf.apiGroups)
+	return fmt.Sprintf("the cluster doesn't serve %s", strings.Join(missing.List(), ", "))
 }
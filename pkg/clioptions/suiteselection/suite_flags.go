@@ -10,18 +10,26 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"k8s.io/client-go/discovery"
 
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 
 	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
+// The names of the filters SelectSuite adds for the cluster, which explain why the tests not matching are excluded.
+const (
+	APIGroupFilterName    = "missing API group"
+	FeatureGateFilterName = "disabled feature gate"
+)
+
 type DiscoveryClientGetter interface {
 	GetDiscoveryClient() (discovery.AggregatedDiscoveryInterface, error)
 }
@@ -37,6 +45,8 @@ type TestSuiteSelectionFlags struct {
 
 	// Regex allows a selection of a subset of tests
 	Regex string
+	// Selector selects the tests by their labels, see testginkgo.ParseLabelSelector
+	Selector string
 	// MatchFn if set is also used to filter the suite contents
 	MatchFn testginkgo.TestMatchFunc
 
@@ -52,9 +62,13 @@ func NewTestSuiteSelectionFlags(streams genericclioptions.IOStreams) *TestSuiteS
 func (f *TestSuiteSelectionFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&f.TestFile, "file", "f", f.TestFile, "Create a suite from the newline-delimited test names in this file.")
 	flags.StringVar(&f.Regex, "run", f.Regex, "Regular expression of tests to run.")
+	flags.StringVar(&f.Selector, "select", f.Selector, "Label selector of tests to run, for example 'sig in (sig-network,sig-node), !Slow, apigroup=route.openshift.io, feature-gate=AdminNetworkPolicy'. Selects from all the enabled tests unless a suite is given.")
 }
 
 func (f *TestSuiteSelectionFlags) Validate() error {
+	if _, err := testginkgo.ParseLabelSelector(f.Selector); err != nil {
+		return fmt.Errorf("invalid --select: %w", err)
+	}
 	return nil
 }
 
@@ -62,14 +76,16 @@ func (f *TestSuiteSelectionFlags) SetIOStreams(streams genericclioptions.IOStrea
 	f.IOStreams = streams
 }
 
-// SelectSuite returns the defined suite plus the requested modifications to the suite in order to select the specified tests
+// SelectSuite returns the defined suite plus the requested modifications to the suite in order to select the specified tests.
+// If clusterState is set, the tests are selected for the cluster of the state instead of the cluster of the clients.
 func (f *TestSuiteSelectionFlags) SelectSuite(
 	suites []*testginkgo.TestSuite,
 	args []string,
 	discoveryClientGetter DiscoveryClientGetter,
 	configClientGetter ConfigClientGetter,
+	clusterState *clusterdiscovery.ClusterState,
 	dryRun bool,
-	additionalMatchFn testginkgo.TestMatchFunc,
+	additionalFilter testginkgo.TestFilter,
 ) (*testginkgo.TestSuite, error) {
 	var suite *testginkgo.TestSuite
 
@@ -79,6 +95,15 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 			Name: "files",
 		}
 	}
+	// If a label selector was provided with no suite, select from every enabled test.
+	if len(f.Selector) > 0 && suite == nil && len(args) == 0 {
+		suite = &testginkgo.TestSuite{
+			Name: "select",
+			Matches: func(name string) bool {
+				return !testginkgo.TestLabelsOf(name).Disabled(time.Now())
+			},
+		}
+	}
 	if suite == nil && len(args) == 0 {
 		fmt.Fprintf(f.ErrOut, SuitesString(suites, "Select a test suite to run against the server:\n\n"))
 		return nil, fmt.Errorf("specify a test suite to run, for example: %s run %s", filepath.Base(os.Args[0]), suites[0].Name)
@@ -100,18 +125,33 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 	if err != nil {
 		return nil, err
 	}
-	suite.AddRequiredMatchFunc(testFileMatchFn)
+	suite.AddRequiredFilter(testginkgo.TestFilter{Name: "not in --file", Matches: testFileMatchFn})
 
 	if len(f.Regex) > 0 {
 		re, err := regexp.Compile(f.Regex)
 		if err != nil {
 			return nil, err
 		}
-		suite.AddRequiredMatchFunc(re.MatchString)
+		suite.AddRequiredFilter(testginkgo.TestFilter{Name: fmt.Sprintf("not matching --run=%s", f.Regex), Matches: re.MatchString})
+	}
+
+	if len(f.Selector) > 0 {
+		selector, err := testginkgo.ParseLabelSelector(f.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --select: %w", err)
+		}
+		suite.Selector = append(append(testginkgo.LabelSelector{}, suite.Selector...), selector...)
 	}
 
 	suite.AddRequiredMatchFunc(f.MatchFn)
-	suite.AddRequiredMatchFunc(additionalMatchFn)
+	suite.AddRequiredFilter(additionalFilter)
+
+	if clusterState != nil {
+		if err := f.addClusterStateFilters(suite, clusterState); err != nil {
+			return nil, err
+		}
+		return suite, nil
+	}
 
 	// Skip tests with [apigroup:GROUP] labels for apigroups which are not
 	// served by a cluster. E.g. MicroShift is not serving most of the openshift.io
@@ -135,7 +175,7 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 			if err != nil {
 				return nil, fmt.Errorf("unable to build api group filter: %w", err)
 			}
-			suite.AddRequiredMatchFunc(apiGroupFilter.includeTest)
+			suite.AddRequiredFilter(testginkgo.TestFilter{Name: APIGroupFilterName, Matches: apiGroupFilter.includeTest, Explain: apiGroupFilter.explain})
 		}
 	}
 
@@ -151,17 +191,40 @@ func (f *TestSuiteSelectionFlags) SelectSuite(
 		case apierrors.IsNotFound(err):
 			// In case we are unable to determine if there is support for feature gates, exclude all featuregated tests
 			// as the test target doesnt comply with preconditions.
-			suite.AddRequiredMatchFunc(includeNonFeatureGateTest)
+			suite.AddRequiredFilter(testginkgo.TestFilter{Name: FeatureGateFilterName, Matches: includeNonFeatureGateTest, Explain: explainNonFeatureGateTest})
 		case err != nil:
 			return nil, fmt.Errorf("unable to build FeatureGate filter: %w", err)
 		default:
-			suite.AddRequiredMatchFunc(featureGateFilter.includeTest)
+			suite.AddRequiredFilter(testginkgo.TestFilter{Name: FeatureGateFilterName, Matches: featureGateFilter.includeTest, Explain: featureGateFilter.explain})
 		}
 	}
 
 	return suite, nil
 }
 
+// addClusterStateFilters adds the API group and feature gate filters of SelectSuite for the snapshot of a cluster.
+func (f *TestSuiteSelectionFlags) addClusterStateFilters(suite *testginkgo.TestSuite, clusterState *clusterdiscovery.ClusterState) error {
+	if clusterState.ServerVersion == nil {
+		fmt.Fprintf(f.ErrOut, "The cluster state is not a snapshot of snapshot-cluster, skipping apigroup and FeatureGate checks\n")
+		return nil
+	}
+
+	apiGroupFilter := newApiGroupFilterForGroups(clusterState.APIGroups)
+	suite.AddRequiredFilter(testginkgo.TestFilter{Name: APIGroupFilterName, Matches: apiGroupFilter.includeTest, Explain: apiGroupFilter.explain})
+
+	if clusterState.FeatureGate == nil {
+		// like a live cluster without FeatureGate, exclude all featuregated tests
+		suite.AddRequiredFilter(testginkgo.TestFilter{Name: FeatureGateFilterName, Matches: includeNonFeatureGateTest, Explain: explainNonFeatureGateTest})
+		return nil
+	}
+	featureGateFilter, err := newFeatureGateFilterFor(clusterState.FeatureGate, clusterState.ClusterVersion)
+	if err != nil {
+		return fmt.Errorf("unable to build FeatureGate filter from the cluster state: %w", err)
+	}
+	suite.AddRequiredFilter(testginkgo.TestFilter{Name: FeatureGateFilterName, Matches: featureGateFilter.includeTest, Explain: featureGateFilter.explain})
+	return nil
+}
+
 // If a test file was provided, override the Matches function
 // to match the tests from both the suite and the file.
 func (f *TestSuiteSelectionFlags) testFileMatchFunc() (testginkgo.TestMatchFunc, error) {
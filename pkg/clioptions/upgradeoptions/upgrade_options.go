@@ -10,7 +10,8 @@ import (
 )
 
 type UpgradeOptions struct {
-	Suite       string
+	Suite string
+	// ToImage is a comma delimited list of the images of sequential upgrades.
 	ToImage     string
 	TestOptions []string
 }
@@ -61,6 +62,10 @@ func (o *UpgradeOptions) SetUpgradeGlobals() error {
 			if err := upgrade.SetUpgradeDisruptReboot(parts[1]); err != nil {
 				return err
 			}
+		case "pause-pool":
+			if err := upgrade.SetUpgradePausedPool(parts[1]); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unrecognized upgrade option: %s", parts[0])
 		}
@@ -1,6 +1,7 @@
 package disruption
 
 import (
+	poll_dns "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/poll-dns"
 	poll_service "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/poll-service"
 	watch_endpointslice "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/watch-endpointslice"
 	"github.com/spf13/cobra"
@@ -16,6 +17,7 @@ func NewDisruptionCommand(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(
 		watch_endpointslice.NewWatchEndpointSlice(streams),
 		poll_service.NewPollService(streams),
+		poll_dns.NewPollDNS(streams),
 	)
 	return cmd
 }
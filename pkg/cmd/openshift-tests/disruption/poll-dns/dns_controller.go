@@ -0,0 +1,209 @@
+package poll_dns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+type PollDNSController struct {
+	backendPrefix     string
+	nodeName          string
+	dnsServer         string
+	serviceName       string
+	externalName      string
+	namespaceName     string
+	stopConfigMapName string
+	recorder          monitorapi.RecorderWriter
+	outFile           io.Writer
+
+	configmapLister corelisters.ConfigMapLister
+
+	informersToSync []cache.InformerSynced
+
+	samplersLock sync.Mutex
+	samplers     []*backenddisruption.DNSSampler
+
+	syncHandler func(ctx context.Context, key string) error
+	queue       workqueue.RateLimitingInterface
+}
+
+func NewPollDNSWatcher(
+	backendPrefix string,
+	nodeName string,
+	namespaceName string,
+	dnsServer string,
+	serviceName string,
+	externalName string,
+	recorder monitorapi.RecorderWriter,
+	outFile io.Writer,
+	stopConfigMapName string,
+	configmapInformer coreinformers.ConfigMapInformer,
+) *PollDNSController {
+
+	c := &PollDNSController{
+		backendPrefix:     backendPrefix,
+		nodeName:          nodeName,
+		namespaceName:     namespaceName,
+		dnsServer:         dnsServer,
+		serviceName:       serviceName,
+		externalName:      externalName,
+		recorder:          recorder,
+		stopConfigMapName: stopConfigMapName,
+		outFile:           outFile,
+
+		configmapLister: configmapInformer.Lister(),
+		informersToSync: []cache.InformerSynced{
+			configmapInformer.Informer().HasSynced,
+		},
+
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "DNSPoller"),
+	}
+
+	c.syncHandler = c.syncDNSPoller
+
+	configmapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.queue.Add("check")
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.queue.Add("check")
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.queue.Add("check")
+		},
+	})
+
+	return c
+}
+
+// newSamplers returns a sampler for every combination of name, protocol and connection type.
+func (c *PollDNSController) newSamplers() []*backenddisruption.DNSSampler {
+	targets := []struct {
+		target string
+		name   string
+	}{
+		{target: "service", name: c.serviceName},
+		{target: "external", name: c.externalName},
+	}
+
+	ret := []*backenddisruption.DNSSampler{}
+	for _, target := range targets {
+		if len(target.name) == 0 {
+			continue
+		}
+		for _, protocol := range []backenddisruption.DNSProtocol{backenddisruption.DNSOverUDP, backenddisruption.DNSOverTCP} {
+			// the interval locator is unique for every tuple of poller to target, but the backend is per connection type
+			intervalLocator := fmt.Sprintf("%s-%s-%s-from-node-%v", c.backendPrefix, target.target, protocol, c.nodeName)
+			for _, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+				historicalBackendDisruptionDataName := fmt.Sprintf("%s-%s-%s-%v-connections", c.backendPrefix, target.target, protocol, connectionType)
+				ret = append(ret, backenddisruption.NewDNSBackendWithLocator(
+					monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataName, intervalLocator, connectionType),
+					c.dnsServer,
+					target.name,
+					protocol,
+					connectionType,
+				))
+			}
+		}
+	}
+	return ret
+}
+
+func (c *PollDNSController) syncDNSPoller(ctx context.Context, key string) error {
+	_, err := c.configmapLister.ConfigMaps(c.namespaceName).Get(c.stopConfigMapName)
+	switch {
+	case err == nil:
+		c.removeAllWatchers()
+		return nil
+	case apierrors.IsNotFound(err):
+		// did not find the stopConfigMap
+	case err != nil:
+		return err
+	}
+
+	c.samplersLock.Lock()
+	defer c.samplersLock.Unlock()
+
+	if c.samplers == nil {
+		fmt.Fprintf(c.outFile, "Adding and starting: resolving with %v on node/%v\n", c.dnsServer, c.nodeName)
+		c.samplers = c.newSamplers()
+		for _, sampler := range c.samplers {
+			sampler.StartEndpointMonitoring(ctx, c.recorder, nil)
+		}
+		fmt.Fprintf(c.outFile, "Successfully started %d samplers: resolving with %v on node/%v\n", len(c.samplers), c.dnsServer, c.nodeName)
+	}
+	return nil
+}
+
+func (c *PollDNSController) removeAllWatchers() {
+	c.samplersLock.Lock()
+	defer c.samplersLock.Unlock()
+
+	if c.samplers == nil {
+		fmt.Fprintf(c.outFile, "No watchers running, skipping removal\n")
+		return
+	}
+
+	fmt.Fprintf(c.outFile, "Stopping and removing: %v for node/%v\n", c.dnsServer, c.nodeName)
+	for _, sampler := range c.samplers {
+		sampler.Stop()
+	}
+	c.samplers = nil
+	fmt.Fprintf(c.outFile, "Stopped all watchers\n")
+}
+
+func (c *PollDNSController) Run(ctx context.Context, finishedCleanup chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+	defer close(finishedCleanup)
+
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting PollDNS controller")
+	defer logger.Info("Shutting down PollDNS controller")
+
+	if !cache.WaitForNamedCacheSync("DNSPoller", ctx.Done(), c.informersToSync...) {
+		return
+	}
+	go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+
+	<-ctx.Done()
+	c.removeAllWatchers()
+}
+
+func (c *PollDNSController) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *PollDNSController) processNextWorkItem(ctx context.Context) bool {
+	dsKey, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(dsKey)
+
+	err := c.syncHandler(ctx, dsKey.(string))
+	if err == nil {
+		c.queue.Forget(dsKey)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("%v failed with : %v", dsKey, err))
+	c.queue.AddRateLimited(dsKey)
+
+	return true
+}
@@ -0,0 +1,178 @@
+package poll_dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/openshift/origin/pkg/clioptions/iooptions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type PollDNSFlags struct {
+	ConfigFlags       *genericclioptions.ConfigFlags
+	OutputFlags       *iooptions.OutputFlags
+	BackendPrefix     string
+	MyNodeName        string
+	StopConfigMapName string
+	DNSServer         string
+	ServiceName       string
+	ExternalName      string
+
+	genericclioptions.IOStreams
+}
+
+func NewPollDNSFlags(streams genericclioptions.IOStreams) *PollDNSFlags {
+	return &PollDNSFlags{
+		ConfigFlags: genericclioptions.NewConfigFlags(false),
+		OutputFlags: iooptions.NewOutputOptions(),
+		IOStreams:   streams,
+	}
+}
+
+func NewPollDNS(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	f := NewPollDNSFlags(ioStreams)
+	cmd := &cobra.Command{
+		Use:   "poll-dns",
+		Short: "Continuously resolve names to check DNS availability",
+		Long: templates.LongDesc(`
+		Continuously resolve names with the cluster DNS server to check DNS availability.
+
+		The in-cluster service name and the external name are resolved over UDP and TCP, both with a new connection
+		for every query and with a connection reused between queries.  Every combination is recorded under its own
+		disruption backend, <disruption-backend-prefix>-<service|external>-<udp|tcp>-<new|reused>-connections.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			abortCh := make(chan os.Signal, 2)
+			go func() {
+				<-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted, terminating\n")
+				cancelFn()
+
+				sig := <-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+				switch sig {
+				case syscall.SIGINT:
+					os.Exit(130)
+				default:
+					os.Exit(0)
+				}
+			}()
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *PollDNSFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.MyNodeName, "my-node-name", f.MyNodeName, "the name of the node running this pod")
+	flags.StringVar(&f.StopConfigMapName, "stop-configmap", f.StopConfigMapName, "the name of the configmap that indicates that this pod should stop all watchers.")
+	flags.StringVar(&f.DNSServer, "dns-server", f.DNSServer, "the IP of the DNS server to query, optionally with a port which defaults to 53")
+	flags.StringVar(&f.ServiceName, "service-name", f.ServiceName, "the fully qualified in-cluster service name to resolve")
+	flags.StringVar(&f.ExternalName, "external-name", f.ExternalName, "the fully qualified name outside of the cluster domain to resolve")
+	flags.StringVar(&f.BackendPrefix, "disruption-backend-prefix", f.BackendPrefix, "classification of disruption for the disruption summery")
+	f.ConfigFlags.AddFlags(flags)
+	f.OutputFlags.BindFlags(flags)
+}
+
+func (f *PollDNSFlags) Validate() error {
+	if len(f.OutputFlags.OutFile) == 0 {
+		return fmt.Errorf("output-file must be specified")
+	}
+	if _, err := dnsServerAddress(f.DNSServer); err != nil {
+		return err
+	}
+	if len(f.ServiceName) == 0 && len(f.ExternalName) == 0 {
+		return fmt.Errorf("at least one of service-name or external-name must be specified")
+	}
+
+	if len(f.BackendPrefix) == 0 {
+		return fmt.Errorf("must specify disruption-backend-prefix")
+	}
+	return nil
+}
+
+// dnsServerAddress returns the host:port of the DNS server, defaulting the port to 53.
+func dnsServerAddress(dnsServer string) (string, error) {
+	if ip := net.ParseIP(dnsServer); ip != nil {
+		return net.JoinHostPort(dnsServer, "53"), nil
+	}
+	host, _, err := net.SplitHostPort(dnsServer)
+	if err != nil || net.ParseIP(host) == nil {
+		return "", fmt.Errorf("dns-server must be a valid IP address, optionally with a port")
+	}
+	return dnsServer, nil
+}
+
+func (f *PollDNSFlags) SetIOStreams(streams genericclioptions.IOStreams) {
+	f.IOStreams = streams
+}
+
+func (f *PollDNSFlags) ToOptions() (*PollDNSOptions, error) {
+	originalOutStream := f.IOStreams.Out
+	closeFn, err := f.OutputFlags.ConfigureIOStreams(f.IOStreams, f)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, _, err := f.ConfigFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 {
+		return nil, fmt.Errorf("namespace must be specified")
+	}
+
+	restConfig, err := f.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	dnsServer, err := dnsServerAddress(f.DNSServer)
+	if err != nil {
+		return nil, err
+	}
+	return &PollDNSOptions{
+		KubeClient:        kubeClient,
+		Namespace:         namespace,
+		OutputFile:        f.OutputFlags.OutFile,
+		BackendPrefix:     f.BackendPrefix,
+		DNSServer:         dnsServer,
+		ServiceName:       f.ServiceName,
+		ExternalName:      f.ExternalName,
+		StopConfigMapName: f.StopConfigMapName,
+		MyNodeName:        f.MyNodeName,
+		CloseFn:           closeFn,
+
+		OriginalOutFile: originalOutStream,
+		IOStreams:       f.IOStreams,
+	}, nil
+}
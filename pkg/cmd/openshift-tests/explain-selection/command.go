@@ -0,0 +1,174 @@
+package explain_selection
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	"github.com/openshift/origin/pkg/clioptions/kubeconfig"
+	"github.com/openshift/origin/pkg/clioptions/suiteselection"
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/testsuites"
+)
+
+type ExplainSelectionFlags struct {
+	TestName           string
+	ProviderTypeOrJSON string
+	ClusterStateFile   string
+	QuarantineFile     string
+
+	TestSuiteSelectionFlags *suiteselection.TestSuiteSelectionFlags
+	AvailableSuites         []*testginkgo.TestSuite
+
+	genericclioptions.IOStreams
+}
+
+func NewExplainSelectionFlags(streams genericclioptions.IOStreams, availableSuites []*testginkgo.TestSuite) *ExplainSelectionFlags {
+	return &ExplainSelectionFlags{
+		TestSuiteSelectionFlags: suiteselection.NewTestSuiteSelectionFlags(streams),
+		AvailableSuites:         availableSuites,
+		IOStreams:               streams,
+	}
+}
+
+func NewExplainSelectionCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewExplainSelectionFlags(streams, testsuites.StandardTestSuites())
+
+	cmd := &cobra.Command{
+		Use:   "explain-selection SUITE --test=NAME",
+		Short: "Explain why a test runs or not in a suite",
+		Long: templates.LongDesc(`
+		Explain why a test runs or not when running a suite against a cluster.
+
+		Prints the labels the annotate rules appended to the test name and the rules responsible, then
+		every stage selecting the tests of the suite: the suite definition, the --file, --run and --select
+		arguments, the platform skips, the API groups served by the cluster, the feature gates it enables,
+		the built-in tests replaced by the tests of external binaries and the exclusions of a rebase in
+		progress.
+
+		The cluster is the one of the current KUBECONFIG, or the cluster state given to --cluster-state,
+		as written by snapshot-cluster. The stages needing more than the cluster provides are reported as
+		skipped: the tests of the external binaries are only known from a cluster state, and a cluster
+		state not written by snapshot-cluster lacks the API groups, feature gates and server version.
+
+		A quarantine does not change the selection, the quarantines of the --quarantine-file matching the
+		test are listed after the stages.
+		`) + testsuites.SuitesString(testsuites.StandardTestSuites(), "\n\nAvailable test suites:\n\n"),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions(args)
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *ExplainSelectionFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.TestName, "test", f.TestName, "The name of the test to explain, with or without the labels appended by the annotate rules.")
+	flags.StringVar(&f.ProviderTypeOrJSON, "provider", f.ProviderTypeOrJSON, "The cluster infrastructure provider. Will automatically default to the correct value.")
+	flags.StringVar(&f.ClusterStateFile, "cluster-state", f.ClusterStateFile, "A cluster state written by snapshot-cluster to explain the selection for, instead of the current cluster.")
+	flags.StringVar(&f.QuarantineFile, "quarantine-file", f.QuarantineFile, "The quarantine registry of the run, to list the quarantines of the test.")
+	f.TestSuiteSelectionFlags.BindFlags(flags)
+}
+
+func (f *ExplainSelectionFlags) ToOptions(args []string) (*ExplainSelectionOptions, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("specify the suite to explain the selection of")
+	}
+	if len(f.TestName) == 0 {
+		return nil, fmt.Errorf("missing --test")
+	}
+	if err := f.TestSuiteSelectionFlags.Validate(); err != nil {
+		return nil, err
+	}
+
+	var definition *testginkgo.TestSuite
+	for _, suite := range f.AvailableSuites {
+		if suite.Name == args[0] {
+			// copied before the selection adds its filters to the suite
+			copied := *suite
+			definition = &copied
+			break
+		}
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("suite %q does not exist", args[0])
+	}
+
+	var discoveryClientGetter suiteselection.DiscoveryClientGetter
+	var configClientGetter suiteselection.ConfigClientGetter
+	var clusterState *clusterdiscovery.ClusterState
+	var serverVersion *version.Info
+	if len(f.ClusterStateFile) > 0 {
+		var err error
+		clusterState, err = clusterdiscovery.LoadClusterState(f.ClusterStateFile)
+		if err != nil {
+			return nil, err
+		}
+		serverVersion = clusterState.ServerVersion
+	} else {
+		adminRESTConfig, err := kubeconfig.GetStaticRESTConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get admin rest config, use --cluster-state without a cluster: %w", err)
+		}
+		liveDiscoveryClientGetter := kubeconfig.NewDiscoveryGetter(adminRESTConfig)
+		discoveryClient, err := liveDiscoveryClientGetter.GetDiscoveryClient()
+		if err != nil {
+			return nil, err
+		}
+		serverVersion, err = discoveryClient.ServerVersion()
+		if err != nil {
+			return nil, err
+		}
+		discoveryClientGetter, configClientGetter = liveDiscoveryClientGetter, kubeconfig.NewConfigClientGetter(adminRESTConfig)
+	}
+
+	var quarantines *testginkgo.QuarantineRegistry
+	if len(f.QuarantineFile) > 0 {
+		var err error
+		quarantines, err = testginkgo.LoadQuarantineRegistry(f.QuarantineFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --quarantine-file: %w", err)
+		}
+	}
+
+	providerConfig, err := clusterdiscovery.DecodeProvider(f.ProviderTypeOrJSON, false, true, clusterState)
+	if err != nil {
+		return nil, err
+	}
+	suite, err := f.TestSuiteSelectionFlags.SelectSuite(
+		f.AvailableSuites,
+		args,
+		discoveryClientGetter,
+		configClientGetter,
+		clusterState,
+		false,
+		testginkgo.TestFilter{Name: "platform skip", Matches: providerConfig.MatchFn(), Explain: providerConfig.SkippedBy},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExplainSelectionOptions{
+		TestName:      f.TestName,
+		Definition:    definition,
+		Suite:         suite,
+		ServerVersion: serverVersion,
+		ClusterState:  clusterState,
+		Quarantines:   quarantines,
+		IOStreams:     f.IOStreams,
+	}, nil
+}
@@ -0,0 +1,197 @@
+package explain_selection
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	"github.com/openshift/origin/pkg/clioptions/suiteselection"
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+)
+
+type ExplainSelectionOptions struct {
+	TestName string
+	// Definition is the suite as defined, without the filters of the selection.
+	Definition *testginkgo.TestSuite
+	// Suite is the suite with the filters of the selection.
+	Suite *testginkgo.TestSuite
+	// ServerVersion is the version of the cluster, unknown for a cluster state not written by snapshot-cluster.
+	ServerVersion *version.Info
+	// ClusterState records the tests of the external binaries, nil for the current cluster.
+	ClusterState *clusterdiscovery.ClusterState
+	// Quarantines is the registry of --quarantine-file, if any.
+	Quarantines *testginkgo.QuarantineRegistry
+
+	genericclioptions.IOStreams
+}
+
+func (o *ExplainSelectionOptions) Run() error {
+	name, known, err := o.resolveTestName()
+	if err != nil {
+		return err
+	}
+	if !known {
+		fmt.Fprintf(o.ErrOut, "warning: %q is not a test of openshift-tests, explaining the selection of the name as given\n", o.TestName)
+	}
+
+	runs := writeSelectionStages(o.Out, name, o.Definition, o.Suite, o.ServerVersion, o.ClusterState)
+	writeQuarantines(o.Out, name, o.Quarantines, time.Now())
+	if runs {
+		fmt.Fprintf(o.Out, "\n%q runs in suite %s\n", name, o.Suite.Name)
+	} else {
+		fmt.Fprintf(o.Out, "\n%q does not run in suite %s\n", name, o.Suite.Name)
+	}
+	return nil
+}
+
+// resolveTestName returns the name of the test as it is selected, with the labels of the annotate rules.
+func (o *ExplainSelectionOptions) resolveTestName() (string, bool, error) {
+	testNames, err := testginkgo.TestNames()
+	if err != nil {
+		return "", false, fmt.Errorf("failed reading the tests: %w", err)
+	}
+	// the annotate rules append each of their labels after a space
+	annotatedName, annotations, annotated := testginkgo.ExplainAnnotations(o.TestName)
+	for _, annotation := range annotations {
+		annotatedName += " " + annotation.Label
+	}
+	// the tests of the external binaries are only known from a cluster state
+	if o.ClusterState != nil {
+		for _, test := range o.ClusterState.ExternalTests {
+			testNames = append(testNames, test.Name)
+		}
+	}
+	for _, name := range testNames {
+		if name == o.TestName || (annotated && name == annotatedName) {
+			return name, true, nil
+		}
+	}
+	return o.TestName, false, nil
+}
+
+// writeSelectionStages prints the verdict of every stage of the selection of the test by the suite, and returns
+// true if the test runs.
+func writeSelectionStages(out io.Writer, name string, definition, suite *testginkgo.TestSuite, serverVersion *version.Info, clusterState *clusterdiscovery.ClusterState) bool {
+	fmt.Fprintf(out, "test: %q\n", name)
+	fmt.Fprintf(out, "labels: %s\n", testginkgo.TestLabelsOf(name))
+
+	_, annotations, _ := testginkgo.ExplainAnnotations(name)
+	if len(annotations) > 0 {
+		fmt.Fprintf(out, "\nannotate rules:\n")
+	}
+	for _, annotation := range annotations {
+		fmt.Fprintf(out, "  %s\n", annotation)
+	}
+
+	fmt.Fprintf(out, "\nselection stages:\n")
+	runs := true
+	stage := func(passed bool, description string) {
+		verdict := "PASS"
+		if !passed {
+			verdict = "FAIL"
+			runs = false
+		}
+		fmt.Fprintf(out, "  %s  %s\n", verdict, description)
+	}
+
+	if definition.Matches(name) {
+		stage(true, fmt.Sprintf("suite %s", definition.Name))
+	} else {
+		stage(false, definition.Exclusion(name))
+	}
+
+	checked := map[string]bool{}
+	for _, filter := range suite.RequiredFilters {
+		checked[filter.Name] = true
+		switch {
+		case filter.Matches(name):
+			stage(true, filter.Name)
+		case filter.Explain != nil:
+			stage(false, fmt.Sprintf("%s: %s", filter.Name, filter.Explain(name)))
+		default:
+			stage(false, filter.Name)
+		}
+	}
+
+	for _, filterName := range []string{suiteselection.APIGroupFilterName, suiteselection.FeatureGateFilterName} {
+		if !checked[filterName] {
+			fmt.Fprintf(out, "  SKIP  %s: the cluster state was not written by snapshot-cluster\n", filterName)
+		}
+	}
+
+	if len(suite.Selector) > 0 {
+		if unmet := suite.Selector.FirstUnmet(testginkgo.TestLabelsOf(name)); unmet != nil {
+			stage(false, fmt.Sprintf("label selector %q: the labels don't match %s", suite.Selector, unmet))
+		} else {
+			stage(true, fmt.Sprintf("label selector %q", suite.Selector))
+		}
+	}
+
+	switch {
+	case clusterState == nil:
+		fmt.Fprintf(out, "  SKIP  external binaries: their tests are only recorded in a cluster state written by snapshot-cluster\n")
+	case testginkgo.ReplacingExternalBinary(name, clusterState) != nil:
+		stage(false, fmt.Sprintf("external binaries: replaced by the tests of %s", testginkgo.ReplacingExternalBinary(name, clusterState)))
+	default:
+		stage(true, "external binaries")
+	}
+
+	switch {
+	case serverVersion == nil:
+		fmt.Fprintf(out, "  SKIP  rebase exclusions: the cluster state was not written by snapshot-cluster\n")
+	case len(testginkgo.RebaseExclusion(serverVersion, name)) > 0:
+		stage(false, fmt.Sprintf("rebase exclusions: %q is excluded on %s", testginkgo.RebaseExclusion(serverVersion, name), serverVersion))
+	default:
+		stage(true, "rebase exclusions")
+	}
+
+	return runs
+}
+
+// writeQuarantines prints the quarantines of the registry matching the test.  A quarantine does not change the
+// selection, the test runs but its failures do not fail the run on the clusters the quarantine applies to.
+func writeQuarantines(out io.Writer, name string, registry *testginkgo.QuarantineRegistry, now time.Time) {
+	if registry == nil {
+		fmt.Fprintf(out, "\nquarantines: not checked without --quarantine-file\n")
+		return
+	}
+	var matching []*testginkgo.Quarantine
+	for _, quarantine := range registry.Quarantines {
+		if !quarantine.Expired(now) && quarantine.MatchesName(name) {
+			matching = append(matching, quarantine)
+		}
+	}
+	if len(matching) == 0 {
+		fmt.Fprintf(out, "\nquarantines: none\n")
+		return
+	}
+	fmt.Fprintf(out, "\nquarantines, the failures do not fail the run on the matching clusters:\n")
+	for _, quarantine := range matching {
+		fmt.Fprintf(out, "  %s, on %s\n", quarantine, quarantineClusters(quarantine))
+	}
+}
+
+func quarantineClusters(quarantine *testginkgo.Quarantine) string {
+	clusters := []string{}
+	for _, restriction := range []struct {
+		kind   string
+		values []string
+	}{
+		{kind: "releases", values: quarantine.Releases},
+		{kind: "platforms", values: quarantine.Platforms},
+		{kind: "topologies", values: quarantine.Topologies},
+	} {
+		if len(restriction.values) > 0 {
+			clusters = append(clusters, fmt.Sprintf("%s %s", restriction.kind, strings.Join(restriction.values, ",")))
+		}
+	}
+	if len(clusters) == 0 {
+		return "every cluster"
+	}
+	return strings.Join(clusters, " and ")
+}
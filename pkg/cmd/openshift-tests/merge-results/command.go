@@ -0,0 +1,115 @@
+package merge_results
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+type MergeResultsFlags struct {
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func NewMergeResultsFlags(streams genericclioptions.IOStreams) *MergeResultsFlags {
+	return &MergeResultsFlags{
+		IOStreams: streams,
+	}
+}
+
+func NewMergeResultsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewMergeResultsFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "merge-results --output-dir=DIR SHARD_DIR...",
+		Short: "Merge the results of a suite run in several shards",
+		Long: templates.LongDesc(`
+		Merge the results written to the junit directories of openshift-tests run --shard=N/M into one result.
+
+		The junit_e2e_*.xml and e2e-monitor-tests_*.xml junits are merged by suite name, the risk analysis test
+		failure summaries are rewritten for the merged junits and the e2e-events_*.json intervals are merged into
+		one timeline. Every merged file is named after the earliest shard.
+
+		Every shard monitors the cluster, so the monitor tests, and the other tests every shard reports, are
+		reported once per outcome: a test failing in one shard and passing in another is a flake. A shard
+		without tests writes no junit and is skipped.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions(args)
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *MergeResultsFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "The directory where the merged results will be written.")
+}
+
+func (f *MergeResultsFlags) ToOptions(args []string) (*MergeResultsOptions, error) {
+	if len(f.OutputDir) == 0 {
+		return nil, fmt.Errorf("missing --output-dir")
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("at least one shard directory is required")
+	}
+	outputDir, err := filepath.Abs(f.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		inputDir, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, err
+		}
+		if inputDir == outputDir {
+			return nil, fmt.Errorf("--output-dir must not be one of the shard directories")
+		}
+	}
+
+	return &MergeResultsOptions{
+		InputDirs: args,
+		OutputDir: f.OutputDir,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type MergeResultsOptions struct {
+	InputDirs []string
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func (o *MergeResultsOptions) Run() error {
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, kind := range junitKinds {
+		if err := mergeJUnits(kind, o.InputDirs, o.OutputDir, o.Out); err != nil {
+			errs = append(errs, fmt.Errorf("failed merging %s junits: %w", kind.filePrefix, err))
+		}
+	}
+	if err := mergeEvents(o.InputDirs, o.OutputDir, o.Out); err != nil {
+		errs = append(errs, fmt.Errorf("failed merging intervals: %w", err))
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,259 @@
+package merge_results
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/riskanalysis"
+	"github.com/openshift/origin/pkg/test"
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// junitKind describes a family of junit files written by openshift-tests and the risk analysis summary written
+// next to them.
+type junitKind struct {
+	filePrefix        string
+	summaryFileSubStr string
+}
+
+var junitKinds = []junitKind{
+	{filePrefix: "junit_e2e", summaryFileSubStr: ""},
+	{filePrefix: "e2e-monitor-tests", summaryFileSubStr: "_monitor"},
+}
+
+// shardJUnit is a junit suite read from one of the input directories.
+type shardJUnit struct {
+	dir        string
+	timeSuffix string
+	suite      *junitapi.JUnitTestSuite
+}
+
+type suiteProperties struct {
+	Properties []*junitapi.TestSuiteProperty `xml:"property"`
+}
+
+// mergeJUnits merges the junits of one kind found in the input directories.  Suites are merged by name so that the
+// directories may contain several suites, for instance the upgrade and the conformance suites of the same job.
+func mergeJUnits(kind junitKind, inputDirs []string, outputDir string, out io.Writer) error {
+	suitesByName := map[string][]shardJUnit{}
+	for _, dir := range inputDirs {
+		files, err := filepath.Glob(filepath.Join(dir, kind.filePrefix+"_*.xml"))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			suite := &junitapi.JUnitTestSuite{}
+			if err := xml.Unmarshal(content, suite); err != nil {
+				return fmt.Errorf("failed to read junit from %q: %w", file, err)
+			}
+			// properties are written as property elements, which JUnitTestSuite does not read back.
+			properties := &suiteProperties{}
+			if err := xml.Unmarshal(content, properties); err != nil {
+				return fmt.Errorf("failed to read junit from %q: %w", file, err)
+			}
+			suite.Properties = properties.Properties
+			timeSuffix := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), kind.filePrefix+"_"), ".xml")
+			suitesByName[suite.Name] = append(suitesByName[suite.Name], shardJUnit{dir: dir, timeSuffix: timeSuffix, suite: suite})
+		}
+	}
+
+	names := []string{}
+	for name := range suitesByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		shards := suitesByName[name]
+		// the earliest shard names the merged result, time suffixes sort chronologically.
+		sort.SliceStable(shards, func(i, j int) bool {
+			return shards[i].timeSuffix < shards[j].timeSuffix
+		})
+		timeSuffix := shards[0].timeSuffix
+
+		suites := []*junitapi.JUnitTestSuite{}
+		for _, shard := range shards {
+			suites = append(suites, shard.suite)
+		}
+		merged := mergeJUnitSuites(suites)
+
+		content, err := xml.MarshalIndent(merged, "", "    ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(outputDir, fmt.Sprintf("%s_%s.xml", kind.filePrefix, timeSuffix))
+		if err := os.WriteFile(path, test.StripANSI(content), 0640); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Merged %d %q junits with %d tests, %d failures into %s\n", len(shards), name, merged.NumTests, merged.NumFailed, path)
+
+		if err := mergeTestFailureSummaries(kind, shards, merged, outputDir, timeSuffix, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testCaseResult is the name and outcome of a test case.
+type testCaseResult struct {
+	name string
+	// outcome is passed, failed or skipped.
+	outcome string
+}
+
+func resultOf(testCase *junitapi.JUnitTestCase) testCaseResult {
+	switch {
+	case testCase.FailureOutput != nil:
+		return testCaseResult{name: testCase.Name, outcome: "failed"}
+	case testCase.SkipMessage != nil:
+		return testCaseResult{name: testCase.Name, outcome: "skipped"}
+	}
+	return testCaseResult{name: testCase.Name, outcome: "passed"}
+}
+
+// mergeJUnitSuites concatenates the test cases of the suites.  The shards ran at the same time, so the duration of
+// the merged suite is the longest shard duration.  Every shard monitors the same cluster, so the monitor tests, and
+// the other tests every shard reports, are kept once per outcome: a test that failed in one shard and passed in
+// another is a flake.  A test reported several times by the same shard, like a retried test, is kept as is.
+func mergeJUnitSuites(suites []*junitapi.JUnitTestSuite) *junitapi.JUnitTestSuite {
+	merged := &junitapi.JUnitTestSuite{
+		Name: suites[0].Name,
+	}
+	seenProperties := map[[2]string]bool{}
+	// shardOfResult is the index of the suite that first reported a result.
+	shardOfResult := map[testCaseResult]int{}
+	for i, suite := range suites {
+		if suite.Duration > merged.Duration {
+			merged.Duration = suite.Duration
+		}
+		for _, property := range suite.Properties {
+			// properties like ExternalBinary have a value per binary, they are only duplicated across shards.
+			key := [2]string{property.Name, property.Value}
+			if property.Name == ginkgo.ShardProperty || seenProperties[key] {
+				continue
+			}
+			seenProperties[key] = true
+			merged.Properties = append(merged.Properties, property)
+		}
+		merged.Children = append(merged.Children, suite.Children...)
+
+		for _, testCase := range suite.TestCases {
+			result := resultOf(testCase)
+			if shard, ok := shardOfResult[result]; ok && shard != i {
+				continue
+			}
+			shardOfResult[result] = i
+			merged.NumTests++
+			switch {
+			case testCase.FailureOutput != nil:
+				merged.NumFailed++
+			case testCase.SkipMessage != nil:
+				merged.NumSkipped++
+			}
+			merged.TestCases = append(merged.TestCases, testCase)
+		}
+	}
+	return merged
+}
+
+// mergeTestFailureSummaries rewrites the risk analysis summary for the merged junit with the cluster data recorded
+// by the shards, the cluster is the same for every shard.
+func mergeTestFailureSummaries(kind junitKind, shards []shardJUnit, merged *junitapi.JUnitTestSuite, outputDir, timeSuffix string, out io.Writer) error {
+	for _, shard := range shards {
+		path := riskanalysis.JobRunTestFailureSummaryPath(shard.dir, shard.timeSuffix, kind.summaryFileSubStr)
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		jobRun := &riskanalysis.ProwJobRun{}
+		if err := json.Unmarshal(content, jobRun); err != nil {
+			return fmt.Errorf("failed to read test failure summary %q: %w", path, err)
+		}
+
+		if err := riskanalysis.WriteJobRunTestFailureSummaryForCluster(outputDir, timeSuffix, merged, jobRun.ClusterData, kind.summaryFileSubStr); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Wrote the test failure summary of %q to %s\n", merged.Name, riskanalysis.JobRunTestFailureSummaryPath(outputDir, timeSuffix, kind.summaryFileSubStr))
+		return nil
+	}
+	return nil
+}
+
+// mergeEvents merges the e2e-events files of the input directories.  Every shard monitors the same cluster, so the
+// intervals every shard observed identically are only kept once.
+func mergeEvents(inputDirs []string, outputDir string, out io.Writer) error {
+	files := []string{}
+	for _, dir := range inputDirs {
+		dirFiles, err := filepath.Glob(filepath.Join(dir, "e2e-events*.json"))
+		if err != nil {
+			return err
+		}
+		files = append(files, dirFiles...)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	timeSuffixes := []string{}
+	seen := map[string]bool{}
+	merged := monitorapi.Intervals{}
+	for _, file := range files {
+		timeSuffixes = append(timeSuffixes, strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "e2e-events"), ".json"))
+		intervals, err := monitorserialization.EventsFromFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read intervals from %q: %w", file, err)
+		}
+		for _, interval := range intervals {
+			key, err := monitorserialization.IntervalToOneLineJSON(interval)
+			if err != nil {
+				return err
+			}
+			if seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+			merged = append(merged, interval)
+		}
+	}
+	sort.Stable(merged)
+	sort.Strings(timeSuffixes)
+
+	path := filepath.Join(outputDir, fmt.Sprintf("e2e-events%s.json", timeSuffixes[0]))
+	if err := monitorserialization.EventsToFile(path, merged); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Merged %d interval files with %d intervals into %s\n", len(files), len(merged), path)
+
+	// the shards only wrote parquet when they were run with --write-parquet.
+	parquetFiles := []string{}
+	for _, dir := range inputDirs {
+		dirFiles, err := filepath.Glob(filepath.Join(dir, "e2e-events*.parquet"))
+		if err != nil {
+			return err
+		}
+		parquetFiles = append(parquetFiles, dirFiles...)
+	}
+	if len(parquetFiles) == 0 {
+		return nil
+	}
+	parquetPath := filepath.Join(outputDir, fmt.Sprintf("e2e-events%s.parquet", timeSuffixes[0]))
+	if err := monitorserialization.IntervalsToParquet(parquetPath, merged); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package merge_results
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/riskanalysis"
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func writeShard(t *testing.T, dir, timeSuffix string, suite *junitapi.JUnitTestSuite, intervals monitorapi.Intervals) {
+	content, err := xml.Marshal(suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "junit_e2e_"+timeSuffix+".xml"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := riskanalysis.WriteJobRunTestFailureSummaryForCluster(dir, timeSuffix, suite, platformidentification.ClusterData{MasterNodesUpdated: "N"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(dir, "e2e-events"+timeSuffix+".json"), intervals); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	from := time.Date(2023, 2, 14, 20, 0, 0, 0, time.UTC)
+	shared := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName("node")).
+		Message(monitorapi.NewMessage().HumanMessage("not ready")).
+		Build(from, from.Add(time.Minute))
+	other := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName("other")).
+		Message(monitorapi.NewMessage().HumanMessage("not ready")).
+		Build(from, from.Add(time.Minute))
+
+	first, second, output := t.TempDir(), t.TempDir(), t.TempDir()
+	writeShard(t, first, "_20230214-200000", &junitapi.JUnitTestSuite{
+		Name:     "openshift-tests",
+		Duration: 100,
+		Properties: []*junitapi.TestSuiteProperty{
+			{Name: "TestVersion", Value: "v1"}, {Name: ginkgo.ShardProperty, Value: "1/2"},
+			{Name: ginkgo.ExternalBinaryProperty, Value: "hyperkube:/usr/bin/k8s-tests"},
+		},
+		TestCases: []*junitapi.JUnitTestCase{
+			{Name: "passes"},
+			{Name: "fails", FailureOutput: &junitapi.FailureOutput{Output: "fail"}},
+			{Name: "[sig-arch] External binary usage"},
+		},
+	}, monitorapi.Intervals{shared})
+	writeShard(t, second, "_20230214-195959", &junitapi.JUnitTestSuite{
+		Name:     "openshift-tests",
+		Duration: 200,
+		Properties: []*junitapi.TestSuiteProperty{
+			{Name: "TestVersion", Value: "v1"}, {Name: ginkgo.ShardProperty, Value: "2/2"},
+			{Name: ginkgo.ExternalBinaryProperty, Value: "hyperkube:/usr/bin/k8s-tests"},
+			{Name: ginkgo.ExternalBinaryProperty, Value: "cluster-foo-operator:/usr/bin/foo-tests"},
+		},
+		TestCases: []*junitapi.JUnitTestCase{
+			{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "skip"}},
+			// reported by every shard
+			{Name: "[sig-arch] External binary usage"},
+		},
+	}, monitorapi.Intervals{shared, other})
+
+	f := NewMergeResultsFlags(genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	f.OutputDir = output
+	o, err := f.ToOptions([]string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the earliest shard names the merged files.
+	content, err := os.ReadFile(filepath.Join(output, "junit_e2e__20230214-195959.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := &junitapi.JUnitTestSuite{}
+	if err := xml.Unmarshal(content, merged); err != nil {
+		t.Fatal(err)
+	}
+	if merged.NumTests != 4 || merged.NumFailed != 1 || merged.NumSkipped != 1 || merged.Duration != 200 {
+		t.Errorf("unexpected merged suite %d tests, %d failed, %d skipped, %vs", merged.NumTests, merged.NumFailed, merged.NumSkipped, merged.Duration)
+	}
+	properties := &suiteProperties{}
+	if err := xml.Unmarshal(content, properties); err != nil {
+		t.Fatal(err)
+	}
+	if len(properties.Properties) != 3 || properties.Properties[0].Name != "TestVersion" {
+		t.Errorf("expected the shard property to be dropped and every external binary once, got %v", properties.Properties)
+	}
+
+	content, err = os.ReadFile(riskanalysis.JobRunTestFailureSummaryPath(output, "_20230214-195959", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := &riskanalysis.ProwJobRun{}
+	if err := json.Unmarshal(content, summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.TestCount != 4 || len(summary.Tests) != 1 || summary.Tests[0].Test.Name != "fails" || summary.ClusterData.MasterNodesUpdated != "N" {
+		t.Errorf("unexpected summary %#v", summary)
+	}
+
+	intervals, err := monitorserialization.EventsFromFile(filepath.Join(output, "e2e-events_20230214-195959.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 2 {
+		t.Errorf("expected the intervals of both shards once, got %d", len(intervals))
+	}
+
+	f.OutputDir = first
+	if _, err := f.ToOptions([]string{first, second}); err == nil {
+		t.Errorf("expected an error when writing into a shard directory")
+	}
+}
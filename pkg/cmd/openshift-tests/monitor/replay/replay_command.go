@@ -0,0 +1,159 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+type ReplayMonitorFlags struct {
+	ArtifactDir                string
+	TimeSuffix                 string
+	OutputDir                  string
+	JUnitSuiteName             string
+	ClusterStabilityDuringTest string
+	ExactMonitorTests          []string
+	DisableMonitorTests        []string
+	MonitorPhaseTimeouts       []string
+
+	genericclioptions.IOStreams
+}
+
+func NewReplayMonitorFlags(streams genericclioptions.IOStreams) *ReplayMonitorFlags {
+	return &ReplayMonitorFlags{
+		JUnitSuiteName:             "openshift-tests",
+		ClusterStabilityDuringTest: string(monitortestframework.Stable),
+		IOStreams:                  streams,
+	}
+}
+
+func NewReplayCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewReplayMonitorFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Run the monitor tests against artifacts from a previous run",
+		Long: templates.LongDesc(`
+		Run the monitor tests against the artifacts saved by a previous run without a live cluster.
+
+		The artifact directory must contain the e2e-events_<timestamp>.json file, the resource-*_<timestamp>.zip
+		tracked resources and the cluster-data_<timestamp>.json file written by the monitor. Intervals are
+		recomputed, tests are evaluated and new junits and artifacts are written to the output directory so they
+		can be compared with the originals.
+
+		Only the monitor tests that can run without cluster clients are replayed, they are given the saved
+		cluster data in place of collection. The other monitor tests are reported as skipped.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(context.Background())
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *ReplayMonitorFlags) BindFlags(flags *pflag.FlagSet) {
+	monitorNames := defaultmonitortests.ListAllMonitorTests()
+
+	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory containing the artifacts of the run to replay.")
+	flags.StringVar(&f.TimeSuffix, "time-suffix", f.TimeSuffix, "The suffix of the artifacts to replay, for instance _20230214-203340. Required if --artifact-dir contains more than one run.")
+	flags.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "The directory where the new junits and artifacts will be written.")
+	flags.StringVar(&f.JUnitSuiteName, "junit-suite-name", f.JUnitSuiteName, "The name of the junit suite written for the monitor tests.")
+	flags.StringVar(&f.ClusterStabilityDuringTest, "cluster-stability", f.ClusterStabilityDuringTest, "cluster stability during the replayed run: Stable or Disruptive.")
+	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
+		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
+	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&f.MonitorPhaseTimeouts, "monitor-phase-timeout", f.MonitorPhaseTimeouts, monitortestframework.PhaseTimeoutsFlagUsage)
+}
+
+func (f *ReplayMonitorFlags) ToOptions() (*ReplayMonitorOptions, error) {
+	if len(f.ArtifactDir) == 0 {
+		return nil, fmt.Errorf("missing --artifact-dir")
+	}
+	if len(f.OutputDir) == 0 {
+		return nil, fmt.Errorf("missing --output-dir")
+	}
+	switch monitortestframework.ClusterStabilityDuringTest(f.ClusterStabilityDuringTest) {
+	case monitortestframework.Stable, monitortestframework.Disruptive:
+	default:
+		return nil, fmt.Errorf("unknown --cluster-stability, %q, expected Stable or Disruptive", f.ClusterStabilityDuringTest)
+	}
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(f.MonitorPhaseTimeouts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --monitor-phase-timeout: %w", err)
+	}
+
+	artifacts, err := monitor.LoadReplayArtifacts(f.ArtifactDir, f.TimeSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(f.ClusterStabilityDuringTest),
+		ExactMonitorTests:          f.ExactMonitorTests,
+		DisableMonitorTests:        f.DisableMonitorTests,
+		PhaseTimeouts:              phaseTimeouts,
+	}
+	monitorTestRegistry, err := defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayMonitorOptions{
+		Artifacts:      artifacts,
+		OutputDir:      f.OutputDir,
+		JUnitSuiteName: f.JUnitSuiteName,
+		MonitorTests:   monitorTestRegistry,
+		IOStreams:      f.IOStreams,
+	}, nil
+}
+
+type ReplayMonitorOptions struct {
+	Artifacts      *monitor.ReplayArtifacts
+	OutputDir      string
+	JUnitSuiteName string
+	MonitorTests   monitortestframework.MonitorTestRegistry
+
+	genericclioptions.IOStreams
+}
+
+func (o *ReplayMonitorOptions) Run(ctx context.Context) error {
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return fmt.Errorf("could not create --output-dir: %w", err)
+	}
+
+	fmt.Fprintf(o.Out, "Replaying %d intervals from %s to %s\n", len(o.Artifacts.Intervals), o.Artifacts.Beginning, o.Artifacts.End)
+	m := monitor.NewReplayMonitor(o.Artifacts, o.OutputDir, o.MonitorTests)
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+	resultState, err := m.Stop(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.SerializeResults(ctx, o.JUnitSuiteName, o.Artifacts.TimeSuffix); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "Replay finished: %s\n", resultState)
+	return nil
+}
@@ -25,14 +25,19 @@ import (
 
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/streaming"
 )
 
 type RunMonitorFlags struct {
-	ArtifactDir         string
-	DisplayFromNow      bool
-	ExactMonitorTests   []string
-	DisableMonitorTests []string
-	FromRepository      string
+	ArtifactDir          string
+	DisplayFromNow       bool
+	ExactMonitorTests    []string
+	DisableMonitorTests  []string
+	MonitorPhaseTimeouts []string
+	FromRepository       string
+	IntervalStorageDir   string
+	ListenAddress        string
+	WriteParquet         bool
 
 	genericclioptions.IOStreams
 }
@@ -89,7 +94,11 @@ func (f *RunMonitorFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&f.MonitorPhaseTimeouts, "monitor-phase-timeout", f.MonitorPhaseTimeouts, monitortestframework.PhaseTimeoutsFlagUsage)
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
+	flags.StringVar(&f.IntervalStorageDir, "interval-storage-dir", f.IntervalStorageDir, "If set, monitor intervals are kept in an append-only segment store under this directory instead of in memory. Useful for long runs that record many intervals.")
+	flags.StringVar(&f.ListenAddress, "listen-address", f.ListenAddress, "If set, for instance to localhost:8080, serve the intervals and resources recorded so far over HTTP. /intervals/stream streams new intervals as server-sent events and /timeline renders the current timeline.")
+	flags.BoolVar(&f.WriteParquet, "write-parquet", f.WriteParquet, "Also write the monitor intervals, disruption samples, tracked resources and monitor test results as parquet files in --artifact-dir, for analysis tools.")
 }
 
 func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
@@ -110,28 +119,40 @@ func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
 	}
 
 	return &RunMonitorOptions{
-		ArtifactDir:     f.ArtifactDir,
-		DisplayFilterFn: displayFilterFn,
-		MonitorTests:    monitorTestRegistry,
-		IOStreams:       f.IOStreams,
-		FromRepository:  f.FromRepository,
+		ArtifactDir:        f.ArtifactDir,
+		DisplayFilterFn:    displayFilterFn,
+		MonitorTests:       monitorTestRegistry,
+		IOStreams:          f.IOStreams,
+		FromRepository:     f.FromRepository,
+		IntervalStorageDir: f.IntervalStorageDir,
+		ListenAddress:      f.ListenAddress,
+		WriteParquet:       f.WriteParquet,
 	}, nil
 }
 
 func (f *RunMonitorFlags) getMonitorTestRegistry() (monitortestframework.MonitorTestRegistry, error) {
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(f.MonitorPhaseTimeouts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --monitor-phase-timeout: %w", err)
+	}
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest: monitortestframework.Stable,
 		ExactMonitorTests:          f.ExactMonitorTests,
 		DisableMonitorTests:        f.DisableMonitorTests,
+		PhaseTimeouts:              phaseTimeouts,
+		WriteParquet:               f.WriteParquet,
 	}
 	return defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
 }
 
 type RunMonitorOptions struct {
-	ArtifactDir     string
-	DisplayFilterFn monitorapi.EventIntervalMatchesFunc
-	MonitorTests    monitortestframework.MonitorTestRegistry
-	FromRepository  string
+	ArtifactDir        string
+	DisplayFilterFn    monitorapi.EventIntervalMatchesFunc
+	MonitorTests       monitortestframework.MonitorTestRegistry
+	FromRepository     string
+	IntervalStorageDir string
+	ListenAddress      string
+	WriteParquet       bool
 
 	genericclioptions.IOStreams
 }
@@ -169,12 +190,31 @@ func (o *RunMonitorOptions) Run() error {
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
-	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.Out, o.DisplayFilterFn)
+	intervalRecorder, closeRecorderFn, err := monitor.NewRecorderForStorage(o.IntervalStorageDir)
+	if err != nil {
+		return err
+	}
+	defer closeRecorderFn()
+	recorder := monitor.WrapWithJSONLRecorder(intervalRecorder, o.Out, o.DisplayFilterFn)
+	if len(o.ListenAddress) > 0 {
+		streamingRecorder := streaming.WrapRecorder(recorder)
+		recorder = streamingRecorder
+
+		// keep serving while the monitor shuts down, that is when most intervals are computed.
+		serverCtx, serverCancel := context.WithCancel(context.Background())
+		defer serverCancel()
+		go func() {
+			if err := streaming.NewServer(streamingRecorder).ListenAndServe(serverCtx, o.ListenAddress); err != nil {
+				fmt.Fprintf(o.ErrOut, "error serving intervals on %s: %v\n", o.ListenAddress, err)
+			}
+		}()
+	}
 	m := monitor.NewMonitor(
 		recorder,
 		restConfig,
 		o.ArtifactDir,
 		o.MonitorTests,
+		monitor.Options{WriteParquet: o.WriteParquet},
 	)
 	if err := m.Start(ctx); err != nil {
 		return err
@@ -25,6 +25,7 @@ type TimelineOptions struct {
 
 	LocatorMatchers []string
 	Namespaces      []string
+	Query           string
 	OutputType      string
 	EndDate         string
 
@@ -66,6 +67,10 @@ func NewTimelineCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
 		Create a timeline html page based on the provided monitor events.
 
 		openshift-tests timeline --type=pod -f raw-monitor-events.json --namespace=openshift-kube-apiserver --namespace=openshift-kube-apiserver-operator -ojson 
+
+		Intervals can also be selected with --query, for instance
+
+		openshift-tests timeline -f raw-monitor-events.json --type=everything --query='source=Disruption AND duration>5s AND overlaps(reason=UpgradeStarted)'
 		`,
 
 		SilenceUsage:  true,
@@ -96,6 +101,7 @@ func (o *TimelineOptions) Bind(flagset *pflag.FlagSet) error {
 	flagset.StringVar(&o.TimelineType, "type", o.TimelineType, "type of timeline to produce: "+strings.Join(sets.StringKeySet(o.KnownTimelines).List(), ","))
 	flagset.StringVar(&o.PodResourceFilename, "known-pods", o.PodResourceFilename, "resource-pods_<timestamp>.zip filename from openshift-tests.")
 	flagset.StringSliceVarP(&o.LocatorMatchers, "locator", "l", o.LocatorMatchers, "key=value selector for monitor event locators (where value is a regex).  for instance -lpod=openshift-etcd-installer.  The same key listed multiple times means an OR.  Each separate key is logically ANDed.  Precede value with a dash for anti-match")
+	flagset.StringVarP(&o.Query, "query", "q", o.Query, "interval query, for instance 'source=Disruption AND locator.namespace=~\"openshift-.*\" AND within(30s, reason=UpgradeStarted)'.  Fields are source, level, reason, cause, message, display, locator, locator.type, locator.<key>, annotation.<key>, duration, from and to.  Comparisons are =, !=, =~, !~, >, >=, <, <= and are combined with AND, OR, NOT, overlaps(query), during(query) and within(duration, query).")
 	flagset.StringVarP(&o.EndDate, "end-date", "e", o.EndDate, fmt.Sprintf("Stop date (default is one hour after latest event) in RFC3399 format in UTC timezone: %s", time.RFC3339))
 
 	return nil
@@ -129,6 +135,10 @@ func (o *TimelineOptions) Validate() error {
 		}
 	}
 
+	if _, err := monitorapi.ParseIntervalQuery(o.Query); err != nil {
+		return fmt.Errorf("invalid --query: %w", err)
+	}
+
 	if len(o.EndDate) > 0 {
 		_, err := time.ParseInLocation(time.RFC3339, o.EndDate, time.UTC)
 		if err != nil {
@@ -163,6 +173,9 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 		endDateTime = nil
 	}
 
+	// already validated
+	query, _ := monitorapi.ParseIntervalQuery(o.Query)
+
 	return &Timeline{
 		MonitorEventFilename: o.MonitorEventFilename,
 		PodResourceFilename:  o.PodResourceFilename,
@@ -170,6 +183,7 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 		LocatorMatcher:        locatorMatcher,
 		RemovedLocatorMatcher: inverseLocatorMatcher,
 		Namespaces:            o.Namespaces,
+		Query:                 query,
 		EndDate:               endDateTime,
 
 		Renderer:       o.KnownRenderers[o.OutputType],
@@ -185,6 +199,7 @@ type Timeline struct {
 	LocatorMatcher        map[string][]*regexp.Regexp
 	RemovedLocatorMatcher map[string][]*regexp.Regexp
 	Namespaces            []string
+	Query                 *monitorapi.IntervalQuery
 	EndDate               *time.Time
 
 	Renderer       RenderFunc
@@ -210,6 +225,10 @@ func (o *Timeline) Run() error {
 	if len(o.RemovedLocatorMatcher) > 0 {
 		filteredEvents = filteredEvents.Filter(monitorapi.NotContainsAllParts(o.RemovedLocatorMatcher))
 	}
+	if o.Query != nil {
+		// temporal operators relate intervals to everything that was recorded, not only to what the other filters kept.
+		filteredEvents = filteredEvents.Filter(o.Query.Matcher(consumedEvents))
+	}
 	// compute intervals from raw
 	var to time.Time
 
@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/test/ginkgo"
+)
+
+type QuarantineLintFlags struct {
+	QuarantineFile string
+
+	genericclioptions.IOStreams
+}
+
+func NewQuarantineLintFlags(streams genericclioptions.IOStreams) *QuarantineLintFlags {
+	return &QuarantineLintFlags{
+		IOStreams: streams,
+	}
+}
+
+func NewQuarantineLintCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewQuarantineLintFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "lint --quarantine-file=FILE",
+		Short: "Check a quarantine registry for expired and stale entries",
+		Long: templates.LongDesc(`
+		Check the registry of known flakes given to run --quarantine-file.
+
+		Fails if an entry expired, so that the test gets fixed or the quarantine consciously extended, or if the
+		test name regex of an entry matches none of the tests of openshift-tests, usually because the test was
+		renamed or removed.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *QuarantineLintFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.QuarantineFile, "quarantine-file", f.QuarantineFile, "The quarantine registry to check.")
+}
+
+func (f *QuarantineLintFlags) ToOptions() (*QuarantineLintOptions, error) {
+	if len(f.QuarantineFile) == 0 {
+		return nil, fmt.Errorf("missing --quarantine-file")
+	}
+	registry, err := ginkgo.LoadQuarantineRegistry(f.QuarantineFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuarantineLintOptions{
+		Registry:  registry,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type QuarantineLintOptions struct {
+	Registry *ginkgo.QuarantineRegistry
+
+	genericclioptions.IOStreams
+}
+
+func (o *QuarantineLintOptions) Run() error {
+	testNames, err := ginkgo.TestNames()
+	if err != nil {
+		return fmt.Errorf("failed reading the tests: %w", err)
+	}
+
+	problems := ginkgo.LintQuarantineRegistry(o.Registry, testNames, time.Now())
+	for _, problem := range problems {
+		fmt.Fprintf(o.Out, "%s\n", problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d problems found in %d quarantines", len(problems), len(o.Registry.Quarantines))
+	}
+	fmt.Fprintf(o.Out, "%d quarantines are valid\n", len(o.Registry.Quarantines))
+	return nil
+}
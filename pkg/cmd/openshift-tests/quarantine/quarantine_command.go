@@ -0,0 +1,19 @@
+package quarantine
+
+import (
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/quarantine/lint"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func NewQuarantineCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "quarantine",
+		Short:         "Manage the registry of known flakes",
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		lint.NewQuarantineLintCommand(streams),
+	)
+	return cmd
+}
@@ -0,0 +1,125 @@
+package failure_clusters
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/test/ginkgo"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type RenderFailureClustersFlags struct {
+	JUnitDir  string
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func NewRenderFailureClustersFlags(streams genericclioptions.IOStreams) *RenderFailureClustersFlags {
+	return &RenderFailureClustersFlags{
+		IOStreams: streams,
+	}
+}
+
+func NewRenderFailureClustersCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewRenderFailureClustersFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "failure-clusters --junit-dir=DIR",
+		Short: "Group the failures of a run that likely share a root cause",
+		Long: templates.LongDesc(`
+		Group the failed tests of the junit_e2e_*.xml junits of a run by the similarity of their failure output.
+
+		Failure outputs are compared once UIDs, timestamps, IPs, e2e namespaces, pod name suffixes and numbers are
+		removed. Every cluster is correlated with the Error intervals of the e2e-events_*.json files overlapping its
+		tests. The clusters are written to failure-clusters.json and failure-clusters.html.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *RenderFailureClustersFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.JUnitDir, "junit-dir", f.JUnitDir, "The directory holding the junits and intervals of the run.")
+	flags.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "The directory where the clusters are written. Defaults to --junit-dir.")
+}
+
+func (f *RenderFailureClustersFlags) ToOptions() (*RenderFailureClustersOptions, error) {
+	if len(f.JUnitDir) == 0 {
+		return nil, fmt.Errorf("missing --junit-dir")
+	}
+	outputDir := f.OutputDir
+	if len(outputDir) == 0 {
+		outputDir = f.JUnitDir
+	}
+
+	return &RenderFailureClustersOptions{
+		JUnitDir:  f.JUnitDir,
+		OutputDir: outputDir,
+		IOStreams: f.IOStreams,
+	}, nil
+}
+
+type RenderFailureClustersOptions struct {
+	JUnitDir  string
+	OutputDir string
+
+	genericclioptions.IOStreams
+}
+
+func (o *RenderFailureClustersOptions) Run() error {
+	junitFiles, err := filepath.Glob(filepath.Join(o.JUnitDir, "junit_e2e_*.xml"))
+	if err != nil {
+		return err
+	}
+	if len(junitFiles) == 0 {
+		return fmt.Errorf("no junit_e2e_*.xml files found in %q", o.JUnitDir)
+	}
+	suites := []*junitapi.JUnitTestSuite{}
+	for _, junitFile := range junitFiles {
+		fileSuites, err := ginkgo.ReadJUnitFile(junitFile)
+		if err != nil {
+			return err
+		}
+		suites = append(suites, fileSuites...)
+	}
+
+	eventFiles, err := filepath.Glob(filepath.Join(o.JUnitDir, "e2e-events*.json"))
+	if err != nil {
+		return err
+	}
+	intervals := monitorapi.Intervals{}
+	for _, eventFile := range eventFiles {
+		fileIntervals, err := monitorserialization.EventsFromFile(eventFile)
+		if err != nil {
+			return fmt.Errorf("failed to read intervals from %q: %w", eventFile, err)
+		}
+		intervals = append(intervals, fileIntervals...)
+	}
+
+	clusters := ginkgo.ClusterFailures(suites, intervals)
+	if err := ginkgo.WriteFailureClusters(o.OutputDir, clusters); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Grouped %d failures into %d clusters in %s\n", clusters.Failures, len(clusters.Clusters), filepath.Join(o.OutputDir, ginkgo.FailureClustersFileName))
+	return nil
+}
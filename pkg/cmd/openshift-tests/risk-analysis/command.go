@@ -21,6 +21,10 @@ files after an invocation of openshift-tests. If multiple files are present
 Results are then submitted to sippy which will return an analysis of per-test
 and overall risk level given historical pass rates on the failed tests.
 The resulting analysis is then also written to the junit artifacts directory.
+
+Without access to sippy, --sippy-url=local://PATH computes the analysis locally
+from PATH, a JSON list of tests in the format of the sippy tests API with their
+historical pass rates.
 `),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -33,6 +37,6 @@ The resulting analysis is then also written to the junit artifacts directory.
 	cmd.MarkFlagRequired("junit-dir")
 	cmd.Flags().StringVar(&riskAnalysisOpts.SippyURL,
 		"sippy-url", sippyDefaultURL,
-		"Sippy URL API endpoint")
+		"Sippy URL API endpoint, or local://PATH to compute the analysis from the historical pass rates in PATH")
 	return cmd
 }
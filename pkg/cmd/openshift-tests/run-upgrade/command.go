@@ -26,6 +26,13 @@ func NewRunUpgradeCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		If you specify the --dry-run argument, the actions the suite will take will be printed to the
 		output.
 
+		Pass several images to --to-image to upgrade through each of them in order, for instance along an
+		EUS path. Every upgrade is recorded as an UpgradeHop interval and the upgrade tests recorded during
+		the intermediate upgrades are suffixed with [hop:N/COUNT], the last upgrade keeps the unsuffixed
+		names. The disruption of each upgrade and of all of them is written to upgrade-hops-disruption.json.
+		The monitor tests only get the last image as the upgrade target, they evaluate the whole run
+		against the final version; use the UpgradeHop intervals to attribute their results to an upgrade.
+
 		Supported options:
 
 		* abort-at=NUMBER - Set to a number between 0 and 100 to control the percent of operators
@@ -34,6 +41,9 @@ func NewRunUpgradeCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		* disrupt-reboot=POLICY - During upgrades, periodically reboot master nodes. If set to 'graceful'
 		the reboot will allow the node to shut down services in an orderly fashion. If set to 'force' the
 		machine will terminate immediately without clean shutdown.
+		* pause-pool=NAME - Pause the machine config pool before the first upgrade and unpause it once the
+		cluster version reached the last one, so its nodes update once to the final version. Repeat to pause
+		several pools, for instance pause-pool=worker for an EUS upgrade.
 
 		`) + testsuites.SuitesString(testsuites.UpgradeTestSuites(), "\n\nAvailable upgrade suites:\n\n"),
 
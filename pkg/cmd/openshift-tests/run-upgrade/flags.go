@@ -2,6 +2,8 @@ package run_upgrade
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
 	"github.com/openshift/origin/pkg/clioptions/iooptions"
 	"github.com/openshift/origin/pkg/clioptions/kubeconfig"
@@ -23,7 +25,7 @@ type RunUpgradeSuiteFlags struct {
 
 	// Passed to the test process if set
 	UpgradeSuite string
-	ToImage      string
+	ToImages     []string
 	TestOptions  []string
 
 	// Shared by initialization code
@@ -47,7 +49,7 @@ func NewRunUpgradeSuiteFlags(streams genericclioptions.IOStreams, fromRepository
 func (f *RunUpgradeSuiteFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
 	flags.StringVar(&f.ProviderTypeOrJSON, "provider", f.ProviderTypeOrJSON, "The cluster infrastructure provider. Will automatically default to the correct value.")
-	flags.StringVar(&f.ToImage, "to-image", f.ToImage, "Specify the image to test an upgrade to.")
+	flags.StringSliceVar(&f.ToImages, "to-image", f.ToImages, "Specify the image to test an upgrade to. Repeat or separate with commas to upgrade through each image in order.")
 	flags.StringSliceVar(&f.TestOptions, "options", f.TestOptions, "A set of KEY=VALUE options to control the test. See the help text.")
 	f.GinkgoRunSuiteOptions.BindFlags(flags)
 	f.TestSuiteSelectionFlags.BindFlags(flags)
@@ -76,17 +78,23 @@ func (f *RunUpgradeSuiteFlags) ToOptions(args []string) (*RunUpgradeSuiteOptions
 	// and when the CVO hangs.
 	ginkgoOptions.IncludeSuccessOutput = true
 
-	if len(f.ToImage) == 0 {
+	if len(f.ToImages) == 0 {
 		return nil, fmt.Errorf("--to-image must be specified to run an upgrade test")
 	}
+	for _, image := range f.ToImages {
+		if len(strings.TrimSpace(image)) == 0 {
+			return nil, fmt.Errorf("--to-image must not contain an empty image: %q", strings.Join(f.ToImages, ","))
+		}
+	}
 
 	suite, err := f.TestSuiteSelectionFlags.SelectSuite(
 		f.AvailableSuites,
 		args,
 		kubeconfig.NewDiscoveryGetter(adminRESTConfig),
 		kubeconfig.NewConfigClientGetter(adminRESTConfig),
-		f.GinkgoRunSuiteOptions.DryRun,
 		nil,
+		f.GinkgoRunSuiteOptions.DryRun,
+		testginkgo.TestFilter{},
 	)
 	if err != nil {
 		return nil, err
@@ -95,7 +103,7 @@ func (f *RunUpgradeSuiteFlags) ToOptions(args []string) (*RunUpgradeSuiteOptions
 	o := &RunUpgradeSuiteOptions{
 		GinkgoRunSuiteOptions: ginkgoOptions,
 		Suite:                 suite,
-		ToImage:               f.ToImage,
+		ToImages:              f.ToImages,
 		FromRepository:        f.FromRepository,
 		TestOptions:           f.TestOptions,
 		CloseFn:               closeFn,
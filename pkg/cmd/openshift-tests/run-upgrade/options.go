@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
 
@@ -26,7 +27,8 @@ type RunUpgradeSuiteOptions struct {
 	GinkgoRunSuiteOptions *testginkgo.GinkgoRunSuiteOptions
 	Suite                 *testginkgo.TestSuite
 
-	ToImage        string
+	// ToImages are upgraded to in order, the last one is the final version.
+	ToImages       []string
 	FromRepository string
 	// I don't see where this is initialized in this flow
 	// CloudProviderJSON string
@@ -53,7 +55,7 @@ func (o *RunUpgradeSuiteOptions) TestCommandEnvironment() []string {
 
 	upgradeOptions := upgradeoptions.UpgradeOptions{
 		Suite:       o.Suite.Name,
-		ToImage:     o.ToImage,
+		ToImage:     strings.Join(o.ToImages, ","),
 		TestOptions: o.TestOptions,
 	}
 	args = append(args, fmt.Sprintf("TEST_UPGRADE_OPTIONS=%s", upgradeOptions.ToEnv()))
@@ -106,11 +108,18 @@ func (o *RunUpgradeSuiteOptions) Run(ctx context.Context) error {
 	}
 
 	// TODO the gingkoRunSuiteOptions needs to have flags then calculated options to express specified versus computed values
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(o.GinkgoRunSuiteOptions.MonitorPhaseTimeouts)
+	if err != nil {
+		return fmt.Errorf("invalid --monitor-phase-timeout: %w", err)
+	}
+	// monitor tests evaluate the whole run against the final version, the hops are told apart by their intervals
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest:        monitortestframework.Stable,
-		UpgradeTargetPayloadImagePullSpec: o.ToImage,
+		UpgradeTargetPayloadImagePullSpec: o.ToImages[len(o.ToImages)-1],
 		ExactMonitorTests:                 o.GinkgoRunSuiteOptions.ExactMonitorTests,
 		DisableMonitorTests:               o.GinkgoRunSuiteOptions.DisableMonitorTests,
+		PhaseTimeouts:                     phaseTimeouts,
+		WriteParquet:                      o.GinkgoRunSuiteOptions.WriteParquet,
 	}
 
 	o.GinkgoRunSuiteOptions.CommandEnv = o.TestCommandEnvironment()
@@ -28,6 +28,15 @@ func NewRunCommand(streams genericclioptions.IOStreams) *cobra.Command {
 		command with the --file argument. You may also pipe a list of test names, one per line, on
 		standard input by passing "-f -".
 
+		The --select argument picks the tests of the suite, or of every enabled test when no suite is
+		given, by their labels, for instance --select='sig in (sig-network,sig-node), !Slow'. With
+		--dry-run, the number of selected tests and the reason for excluding the other tests matching
+		the selector are printed on standard error.
+
+		The --cluster-state argument takes a cluster state written by snapshot-cluster instead of the
+		current cluster. With --dry-run, it prints the tests that would run on that cluster without
+		reaching it, leaving out the tests of the external binaries.
+
 		`) + testsuites.SuitesString(testsuites.StandardTestSuites(), "\n\nAvailable test suites:\n\n"),
 
 		SilenceUsage:  true,
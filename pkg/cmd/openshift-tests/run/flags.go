@@ -22,6 +22,9 @@ type RunSuiteFlags struct {
 
 	FromRepository     string
 	ProviderTypeOrJSON string
+	// ClusterStateFile is a snapshot of snapshot-cluster to select the tests of a dry run for, instead of the
+	// current cluster.
+	ClusterStateFile string
 
 	// Passed to the test process if set
 	UpgradeSuite string
@@ -50,12 +53,18 @@ func NewRunSuiteFlags(streams genericclioptions.IOStreams, fromRepository string
 //  1. invokes the Kube suite in order to populate data from the environment for the CSI suite (originally, but now everything).
 //  2. ensures that the suite filters out tests from providers that aren't relevant (see exutilcluster.ClusterConfig.MatchFn) by
 //     loading the provider info from the cluster or flags.
-func (f *RunSuiteFlags) SuiteWithKubeTestInitializationPreSuite() (*clusterdiscovery.ClusterConfiguration, error) {
-	providerConfig, err := clusterdiscovery.DecodeProvider(f.ProviderTypeOrJSON, f.GinkgoRunSuiteOptions.DryRun, true, nil)
+func (f *RunSuiteFlags) SuiteWithKubeTestInitializationPreSuite(clusterState *clusterdiscovery.ClusterState) (*clusterdiscovery.ClusterConfiguration, error) {
+	providerConfig, err := clusterdiscovery.DecodeProvider(f.ProviderTypeOrJSON, f.GinkgoRunSuiteOptions.DryRun, true, clusterState)
 	if err != nil {
 		return nil, err
 	}
 
+	if clusterState != nil {
+		if err := clusterdiscovery.InitializeTestFrameworkForClusterState(exutil.TestContext, providerConfig, clusterState); err != nil {
+			return nil, err
+		}
+		return providerConfig, nil
+	}
 	if err := clusterdiscovery.InitializeTestFramework(exutil.TestContext, providerConfig, f.GinkgoRunSuiteOptions.DryRun); err != nil {
 		return nil, err
 	}
@@ -65,6 +74,7 @@ func (f *RunSuiteFlags) SuiteWithKubeTestInitializationPreSuite() (*clusterdisco
 func (f *RunSuiteFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
 	flags.StringVar(&f.ProviderTypeOrJSON, "provider", f.ProviderTypeOrJSON, "The cluster infrastructure provider. Will automatically default to the correct value.")
+	flags.StringVar(&f.ClusterStateFile, "cluster-state", f.ClusterStateFile, "A cluster state written by snapshot-cluster. With --dry-run, prints the tests that would run on that cluster without reaching it.")
 	f.GinkgoRunSuiteOptions.BindFlags(flags)
 	f.TestSuiteSelectionFlags.BindFlags(flags)
 	f.OutputFlags.BindFlags(flags)
@@ -76,8 +86,23 @@ func (f *RunSuiteFlags) SetIOStreams(streams genericclioptions.IOStreams) {
 }
 
 func (f *RunSuiteFlags) ToOptions(args []string) (*RunSuiteOptions, error) {
+	var clusterState *clusterdiscovery.ClusterState
+	if len(f.ClusterStateFile) > 0 {
+		if !f.GinkgoRunSuiteOptions.DryRun {
+			return nil, fmt.Errorf("--cluster-state requires --dry-run")
+		}
+		var err error
+		clusterState, err = clusterdiscovery.LoadClusterState(f.ClusterStateFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	adminRESTConfig, err := kubeconfig.GetStaticRESTConfig()
 	switch {
+	case clusterState != nil:
+		// the tests are selected for the cluster of the state, not the current one
+		adminRESTConfig = &rest.Config{}
 	case err != nil && f.GinkgoRunSuiteOptions.DryRun:
 		fmt.Fprintf(f.ErrOut, "Unable to get admin rest config, skipping apigroup check in the dry-run mode: %v\n", err)
 		adminRESTConfig = &rest.Config{}
@@ -92,8 +117,9 @@ func (f *RunSuiteFlags) ToOptions(args []string) (*RunSuiteOptions, error) {
 
 	// shallow copy to mutate
 	ginkgoOptions := f.GinkgoRunSuiteOptions
+	ginkgoOptions.ClusterState = clusterState
 
-	providerConfig, err := f.SuiteWithKubeTestInitializationPreSuite()
+	providerConfig, err := f.SuiteWithKubeTestInitializationPreSuite(clusterState)
 	if err != nil {
 		return nil, err
 	}
@@ -102,8 +128,9 @@ func (f *RunSuiteFlags) ToOptions(args []string) (*RunSuiteOptions, error) {
 		args,
 		kubeconfig.NewDiscoveryGetter(adminRESTConfig),
 		kubeconfig.NewConfigClientGetter(adminRESTConfig),
+		clusterState,
 		f.GinkgoRunSuiteOptions.DryRun,
-		providerConfig.MatchFn(),
+		testginkgo.TestFilter{Name: "platform skip", Matches: providerConfig.MatchFn(), Explain: providerConfig.SkippedBy},
 	)
 	if err != nil {
 		return nil, err
@@ -80,10 +80,16 @@ func (o *RunSuiteOptions) Run(ctx context.Context) error {
 		stabilitySetting = o.Suite.ClusterStabilityDuringTest
 	}
 
+	phaseTimeouts, err := monitortestframework.ParsePhaseTimeouts(o.GinkgoRunSuiteOptions.MonitorPhaseTimeouts)
+	if err != nil {
+		return fmt.Errorf("invalid --monitor-phase-timeout: %w", err)
+	}
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(stabilitySetting),
 		ExactMonitorTests:          o.GinkgoRunSuiteOptions.ExactMonitorTests,
 		DisableMonitorTests:        o.GinkgoRunSuiteOptions.DisableMonitorTests,
+		PhaseTimeouts:              phaseTimeouts,
+		WriteParquet:               o.GinkgoRunSuiteOptions.WriteParquet,
 	}
 
 	o.GinkgoRunSuiteOptions.CommandEnv = o.TestCommandEnvironment()
@@ -0,0 +1,119 @@
+package snapshot_cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	"github.com/openshift/origin/pkg/clioptions/kubeconfig"
+	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
+)
+
+type SnapshotClusterFlags struct {
+	OutputFile           string
+	ExternalBinariesFile string
+
+	genericclioptions.IOStreams
+}
+
+func NewSnapshotClusterFlags(streams genericclioptions.IOStreams) *SnapshotClusterFlags {
+	return &SnapshotClusterFlags{
+		IOStreams: streams,
+	}
+}
+
+func NewSnapshotClusterCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewSnapshotClusterFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "snapshot-cluster",
+		Short: "Record the state of a cluster to select its tests without it",
+		Long: templates.LongDesc(`
+		Record the state of the cluster of the current KUBECONFIG selecting the tests to run
+
+		The cluster state holds the platform, nodes and network of the cluster, the API groups it
+		serves, its Infrastructure, Network, ClusterVersion and FeatureGate, and the storage capabilities
+		of the CSI drivers defined by TEST_CSI_DRIVER_FILES, and the tests of the external binaries of its
+		release payload, unless OPENSHIFT_SKIP_EXTERNAL_TESTS is set. Pass it to --cluster-state of
+		"run --dry-run" to print the tests that would run on the cluster, or of explain-selection, once
+		the cluster is gone.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *SnapshotClusterFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&f.OutputFile, "output", "o", f.OutputFile, "The file to write the cluster state to. Defaults to standard output.")
+	flags.StringVar(&f.ExternalBinariesFile, "external-binaries", f.ExternalBinariesFile, "A YAML list of test binaries, as for run, whose tests are recorded in addition to those of the default external binaries.")
+}
+
+func (f *SnapshotClusterFlags) ToOptions() (*SnapshotClusterOptions, error) {
+	adminRESTConfig, err := kubeconfig.GetStaticRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get admin rest config, %w", err)
+	}
+
+	return &SnapshotClusterOptions{
+		AdminRESTConfig:      adminRESTConfig,
+		OutputFile:           f.OutputFile,
+		ExternalBinariesFile: f.ExternalBinariesFile,
+		IOStreams:            f.IOStreams,
+	}, nil
+}
+
+type SnapshotClusterOptions struct {
+	AdminRESTConfig      *rest.Config
+	OutputFile           string
+	ExternalBinariesFile string
+
+	genericclioptions.IOStreams
+}
+
+func (o *SnapshotClusterOptions) Run() error {
+	state, err := clusterdiscovery.SnapshotClusterState(o.AdminRESTConfig)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the cluster: %w", err)
+	}
+	if len(os.Getenv("OPENSHIFT_SKIP_EXTERNAL_TESTS")) == 0 {
+		state.ExternalTests, err = testginkgo.SnapshotExternalTests(context.Background(), o.ExternalBinariesFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the tests of the external binaries, set OPENSHIFT_SKIP_EXTERNAL_TESTS to snapshot the cluster without them: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if len(o.OutputFile) == 0 {
+		_, err := o.Out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(o.OutputFile, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.ErrOut, "Wrote the state of cluster %s to %s\n", o.AdminRESTConfig.Host, o.OutputFile)
+	return nil
+}
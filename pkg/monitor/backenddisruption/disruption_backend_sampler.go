@@ -18,7 +18,6 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	v1 "k8s.io/api/core/v1"
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apiserver/pkg/apis/audit"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/events"
@@ -73,12 +72,8 @@ type BackendSampler struct {
 	//	good enough for CI
 	httpClientErr error
 
-	// runningLock
-	runningLock sync.Mutex
-	// stopRunning is a context cancel for the localContext used to run
-	stopRunning context.CancelFunc
-	// consumptionFinished is closed when the consumer is done
-	consumptionFinished chan struct{}
+	// lifecycle starts and stops the sampling
+	lifecycle *samplerLifecycle
 }
 
 type routeCoordinates struct {
@@ -94,11 +89,11 @@ const OpenshiftTestsSource = "openshift-tests"
 func NewSimpleBackendFromOpenshiftTests(host, disruptionBackendName, path string, connectionType monitorapi.BackendConnectionType) *BackendSampler {
 
 	ret := &BackendSampler{
-		connectionType:      connectionType,
-		locator:             monitorapi.NewLocator().LocateDisruptionCheck(disruptionBackendName, OpenshiftTestsSource, connectionType),
-		path:                path,
-		hostGetter:          NewSimpleHostGetter(host),
-		consumptionFinished: make(chan struct{}),
+		connectionType: connectionType,
+		locator:        monitorapi.NewLocator().LocateDisruptionCheck(disruptionBackendName, OpenshiftTestsSource, connectionType),
+		path:           path,
+		hostGetter:     NewSimpleHostGetter(host),
+		lifecycle:      newSamplerLifecycle(),
 	}
 
 	// TODO return error?  This is programmer error
@@ -112,11 +107,11 @@ func NewSimpleBackendFromOpenshiftTests(host, disruptionBackendName, path string
 // NewRouteBackend constructs a BackendSampler suitable for use against a routes.route.openshift.io
 func NewSimpleBackendWithLocator(locator monitorapi.Locator, host, path string, connectionType monitorapi.BackendConnectionType) *BackendSampler {
 	ret := &BackendSampler{
-		connectionType:      connectionType,
-		locator:             locator,
-		path:                path,
-		hostGetter:          NewSimpleHostGetter(host),
-		consumptionFinished: make(chan struct{}),
+		connectionType: connectionType,
+		locator:        locator,
+		path:           path,
+		hostGetter:     NewSimpleHostGetter(host),
+		lifecycle:      newSamplerLifecycle(),
 	}
 
 	// TODO return error?  This is programmer error
@@ -141,14 +136,14 @@ func NewAPIServerBackend(clientConfig *rest.Config, disruptionBackendName, path
 	}
 
 	ret := &BackendSampler{
-		connectionType:      connectionType,
-		locator:             monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, connectionType),
-		path:                path,
-		hostGetter:          NewKubeAPIHostGetter(clientConfig),
-		tlsConfig:           tlsConfig,
-		bearerToken:         kubeTransportConfig.BearerToken,
-		bearerTokenFile:     kubeTransportConfig.BearerTokenFile,
-		consumptionFinished: make(chan struct{}),
+		connectionType:  connectionType,
+		locator:         monitorapi.NewLocator().LocateDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, connectionType),
+		path:            path,
+		hostGetter:      NewKubeAPIHostGetter(clientConfig),
+		tlsConfig:       tlsConfig,
+		bearerToken:     kubeTransportConfig.BearerToken,
+		bearerTokenFile: kubeTransportConfig.BearerTokenFile,
+		lifecycle:       newSamplerLifecycle(),
 	}
 
 	// TODO return error?  This is programmer error
@@ -164,11 +159,11 @@ func NewRouteBackend(clientConfig *rest.Config, namespace, name, disruptionBacke
 	historicalBackendDisruptionDataName := fmt.Sprintf("%s-%v-connections", disruptionBackendName, connectionType)
 
 	ret := &BackendSampler{
-		connectionType:      connectionType,
-		locator:             monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, namespace, name, connectionType),
-		path:                path,
-		hostGetter:          NewRouteHostGetter(clientConfig, namespace, name),
-		consumptionFinished: make(chan struct{}),
+		connectionType: connectionType,
+		locator:        monitorapi.NewLocator().LocateRouteForDisruptionCheck(historicalBackendDisruptionDataName, OpenshiftTestsSource, namespace, name, connectionType),
+		path:           path,
+		hostGetter:     NewRouteHostGetter(clientConfig, namespace, name),
+		lifecycle:      newSamplerLifecycle(),
 	}
 
 	// TODO return error?  This is programmer error
@@ -406,15 +401,12 @@ func (b *BackendSampler) CheckConnection(ctx context.Context) (string, error) {
 // RunEndpointMonitoring sets up a client for the given BackendSampler, starts checking the endpoint, and recording
 // success/failure edges into the monitorRecorder, and blocks until the context is closed or the sampler is closed.
 func (b *BackendSampler) RunEndpointMonitoring(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
-	if b.isRunning() {
-		return fmt.Errorf("cannot monitor twice at the same time")
-	}
-
-	// the producer is wired from the original context so that a base cancel stops everything
-	samplerContext, samplerCancel := context.WithCancel(ctx)
-	defer samplerCancel()
-	b.setCancelForRun(samplerCancel) // used from .Stop later to stop monitoring
+	return b.lifecycle.run(ctx, b, monitorRecorder, eventRecorder)
+}
 
+// runDisruptionSampler checks the connection once a second until the samplerContext is closed, recording the
+// success/failure edges into the monitorRecorder.  consumptionFinished is closed once every sample was recorded.
+func runDisruptionSampler(ctx, samplerContext context.Context, checker connectionChecker, consumptionFinished chan struct{}, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
 	if monitorRecorder == nil {
 		return fmt.Errorf("monitor is required")
 	}
@@ -434,12 +426,12 @@ func (b *BackendSampler) RunEndpointMonitoring(ctx context.Context, monitorRecor
 	}
 
 	interval := 1 * time.Second
-	disruptionSampler := newDisruptionSampler(b)
+	disruptionSampler := newDisruptionSampler(checker)
 	go disruptionSampler.produceSamples(samplerContext, interval)
-	go disruptionSampler.consumeSamples(samplerContext, b.consumptionFinished, interval, monitorRecorder, eventRecorder)
+	go disruptionSampler.consumeSamples(samplerContext, consumptionFinished, interval, monitorRecorder, eventRecorder)
 
 	<-samplerContext.Done()
-	<-b.consumptionFinished
+	<-consumptionFinished
 
 	if disruptionSampler.numberOfSamples(ctx) > 0 {
 		return fmt.Errorf("not finished writing all samples (%d remaining), but we're told to close", disruptionSampler.numberOfSamples(ctx))
@@ -448,66 +440,34 @@ func (b *BackendSampler) RunEndpointMonitoring(ctx context.Context, monitorRecor
 	return nil
 }
 
-func (b *BackendSampler) isRunning() bool {
-	b.runningLock.Lock()
-	defer b.runningLock.Unlock()
-	return b.stopRunning != nil
-}
-
-func (b *BackendSampler) setCancelForRun(cancelFunc context.CancelFunc) {
-	b.runningLock.Lock()
-	defer b.runningLock.Unlock()
-	b.stopRunning = cancelFunc
-}
-
 // Stop stops the produce and consumer and blocks until the consumer is finished consuming.
 func (b *BackendSampler) Stop() {
-	b.runningLock.Lock()
-	defer b.runningLock.Unlock()
-	if b.stopRunning == nil {
-		return
-	}
-	if b.stopRunning != nil {
-		b.stopRunning()
-	}
-	b.stopRunning = nil
-
-	for {
-		fmt.Printf("waiting for consumer to finish %v...\n", b.locator)
-		select {
-		case <-b.consumptionFinished:
-			fmt.Printf("consumer finished %v\n", b.locator)
-			return
-		case <-time.After(10 * time.Second):
-		}
-	}
+	b.lifecycle.stop(b.locator)
 }
 
 // StartEndpointMonitoring sets up a client for the given BackendSampler, starts checking the endpoint, and recording
 // success/failure edges into the monitorRecorder
 func (b *BackendSampler) StartEndpointMonitoring(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
-	if monitorRecorder == nil {
-		return fmt.Errorf("monitor is required")
-	}
-
-	go func() {
-		err := b.RunEndpointMonitoring(ctx, monitorRecorder, eventRecorder)
-		if err != nil {
-			utilruntime.HandleError(err)
-		}
-	}()
+	return b.lifecycle.start(ctx, b.RunEndpointMonitoring, monitorRecorder, eventRecorder)
+}
 
-	return nil
+// connectionChecker is a backend checked once a second by a disruptionSampler.
+type connectionChecker interface {
+	GetLocator() monitorapi.Locator
+	GetConnectionType() monitorapi.BackendConnectionType
+	GetDisruptionBackendName() string
+	// CheckConnection returns the audit request UID, if there is one, and an error if the backend is unavailable.
+	CheckConnection(ctx context.Context) (string, error)
 }
 
 type disruptionSampler struct {
-	backendSampler *BackendSampler
+	backendSampler connectionChecker
 
 	lock           sync.Mutex
 	activeSamplers list.List
 }
 
-func newDisruptionSampler(backendSampler *BackendSampler) *disruptionSampler {
+func newDisruptionSampler(backendSampler connectionChecker) *disruptionSampler {
 	return &disruptionSampler{
 		backendSampler: backendSampler,
 		lock:           sync.Mutex{},
@@ -537,9 +497,9 @@ func (b *disruptionSampler) produceSamples(ctx context.Context, interval time.Du
 				// For now we will just log clearly the requests that failed and use this to correlate with the
 				// audit log manually.
 				logrus.WithFields(logrus.Fields{
-					"this-instance": b.backendSampler.locator,
+					"this-instance": b.backendSampler.GetLocator(),
 					"backend":       b.backendSampler.GetDisruptionBackendName(),
-					"type":          b.backendSampler.connectionType,
+					"type":          b.backendSampler.GetConnectionType(),
 					"auditID":       uid,
 				}).Errorf("disruption sample failed: %v", sampleErr)
 			}
@@ -0,0 +1,203 @@
+package backenddisruption
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/client-go/tools/events"
+)
+
+// DNSProtocol is the transport DNS queries are sent over.
+type DNSProtocol string
+
+const (
+	DNSOverUDP DNSProtocol = "udp"
+	DNSOverTCP DNSProtocol = "tcp"
+)
+
+// DNSSampler is used to monitor a DNS server and ensure that it always resolves a name.  With new connections every
+// query is sent from a new socket, with reused connections the queries share one socket until a query fails.
+// It records results into the monitorRecorder that is passed to the StartEndpointMonitoring call.
+type DNSSampler struct {
+	// locator is the string used to identify this in the monitorRecorder later on.
+	locator monitorapi.Locator
+	// connectionType indicates what type of connection is being used.
+	connectionType monitorapi.BackendConnectionType
+	// server is the host:port of the DNS server.
+	server string
+	// name is resolved by every query, it is made absolute so that search domains do not apply.
+	name     string
+	protocol DNSProtocol
+	// timeout bounds a lookup, including its retries.
+	timeout *time.Duration
+
+	// connLock serializes the queries over the reused connection.
+	connLock sync.Mutex
+	// conn is the reused connection, nil until dialed and after a failed query.
+	conn net.Conn
+
+	lifecycle *samplerLifecycle
+}
+
+// NewDNSBackendWithLocator constructs a DNSSampler resolving name with the DNS server at server, a host:port.
+func NewDNSBackendWithLocator(locator monitorapi.Locator, server, name string, protocol DNSProtocol, connectionType monitorapi.BackendConnectionType) *DNSSampler {
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	ret := &DNSSampler{
+		locator:        locator,
+		connectionType: connectionType,
+		server:         server,
+		name:           name,
+		protocol:       protocol,
+		lifecycle:      newSamplerLifecycle(),
+	}
+
+	// TODO return error?  This is programmer error
+	if len(ret.GetDisruptionBackendName()) == 0 {
+		panic("missing disruption backend")
+	}
+
+	return ret
+}
+
+// WithTimeout sets how long a lookup may take.
+func (b *DNSSampler) WithTimeout(timeout time.Duration) *DNSSampler {
+	b.timeout = &timeout
+	return b
+}
+
+func (b *DNSSampler) GetDisruptionBackendName() string {
+	return monitorapi.BackendDisruptionNameFromLocator(b.locator)
+}
+
+func (b *DNSSampler) GetLocator() monitorapi.Locator {
+	return b.locator
+}
+
+func (b *DNSSampler) GetConnectionType() monitorapi.BackendConnectionType {
+	return b.connectionType
+}
+
+func (b *DNSSampler) getTimeout() time.Duration {
+	if b.timeout == nil {
+		return 10 * time.Second
+	}
+	return *b.timeout
+}
+
+// ipNetwork returns the address family to resolve, the one of the DNS server so that single stack clusters work.
+func (b *DNSSampler) ipNetwork() string {
+	host, _, err := net.SplitHostPort(b.server)
+	if err != nil {
+		return "ip4"
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return "ip6"
+	}
+	return "ip4"
+}
+
+// CheckConnection resolves the name.  There is no audit ID for DNS queries, so it is always empty.
+func (b *DNSSampler) CheckConnection(ctx context.Context) (string, error) {
+	resolver := &net.Resolver{
+		PreferGo:     true,
+		StrictErrors: true,
+		Dial:         b.dialNew,
+	}
+	if b.connectionType == monitorapi.ReusedConnectionType {
+		b.connLock.Lock()
+		defer b.connLock.Unlock()
+		resolver.Dial = b.dialReused
+	}
+
+	lookupContext, lookupCancel := context.WithTimeout(ctx, b.getTimeout())
+	defer lookupCancel()
+	ips, err := resolver.LookupIP(lookupContext, b.ipNetwork(), b.name)
+	if ctx.Err() == context.Canceled {
+		// this isn't an error, we were simply cancelled
+		return "", nil
+	}
+	if err != nil && b.conn != nil {
+		// the next query dials a new connection.
+		b.conn.Close()
+		b.conn = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("lookup %s on %s returned no addresses", b.name, b.server)
+	}
+	return "", nil
+}
+
+// dialNew ignores the servers of resolv.conf and always dials the DNS server over the protocol of the sampler.
+func (b *DNSSampler) dialNew(ctx context.Context, _, _ string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, string(b.protocol), b.server)
+}
+
+// dialReused must be called with the connLock held.
+func (b *DNSSampler) dialReused(ctx context.Context, network, address string) (net.Conn, error) {
+	if b.conn == nil {
+		conn, err := b.dialNew(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		b.conn = conn
+	}
+
+	// the resolver closes the connection after every query, keep it open instead.
+	if udpConn, ok := b.conn.(*net.UDPConn); ok {
+		// the resolver frames queries for UDP only when the connection is a net.PacketConn.
+		return &reusedUDPConn{UDPConn: udpConn}, nil
+	}
+	return &reusedConn{Conn: b.conn}, nil
+}
+
+type reusedConn struct {
+	net.Conn
+}
+
+func (c *reusedConn) Close() error {
+	return nil
+}
+
+type reusedUDPConn struct {
+	*net.UDPConn
+}
+
+func (c *reusedUDPConn) Close() error {
+	return nil
+}
+
+// RunEndpointMonitoring starts resolving the name, and recording success/failure edges into the monitorRecorder, and
+// blocks until the context is closed or the sampler is closed.
+func (b *DNSSampler) RunEndpointMonitoring(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	defer func() {
+		b.connLock.Lock()
+		defer b.connLock.Unlock()
+		if b.conn != nil {
+			b.conn.Close()
+			b.conn = nil
+		}
+	}()
+
+	return b.lifecycle.run(ctx, b, monitorRecorder, eventRecorder)
+}
+
+// Stop stops the produce and consumer and blocks until the consumer is finished consuming.
+func (b *DNSSampler) Stop() {
+	b.lifecycle.stop(b.locator)
+}
+
+// StartEndpointMonitoring starts resolving the name, and recording success/failure edges into the monitorRecorder
+func (b *DNSSampler) StartEndpointMonitoring(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	return b.lifecycle.start(ctx, b.RunEndpointMonitoring, monitorRecorder, eventRecorder)
+}
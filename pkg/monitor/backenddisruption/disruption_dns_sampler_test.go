@@ -0,0 +1,166 @@
+package backenddisruption
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// answerA answers a DNS query with the A record 10.0.0.1 for the name of its question.
+func answerA(query []byte) []byte {
+	// the question follows the 12 byte header, its name is a sequence of labels ending with an empty one and is
+	// followed by the type and the class.  The additional records of the query are dropped.
+	questionEnd := 12
+	for query[questionEnd] != 0 {
+		questionEnd += int(query[questionEnd]) + 1
+	}
+	questionEnd += 5
+	answer := append([]byte{}, query[:2]...)
+	answer = append(answer, 0x81, 0x80, 0, 1, 0, 1, 0, 0, 0, 0)
+	answer = append(answer, query[12:questionEnd]...)
+	// a pointer to the name of the question, type A, class IN, TTL and the address.
+	answer = append(answer, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 30, 0, 4, 10, 0, 0, 1)
+	return answer
+}
+
+// fakeDNSServer answers A queries over UDP and TCP and counts the TCP connections it accepted.
+type fakeDNSServer struct {
+	udpConn     net.PacketConn
+	tcpListener net.Listener
+
+	lock           sync.Mutex
+	tcpConnections int
+	tcpConns       []net.Conn
+}
+
+func newFakeDNSServer(t *testing.T) *fakeDNSServer {
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcpListener, err := net.Listen("tcp", udpConn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeDNSServer{udpConn: udpConn, tcpListener: tcpListener}
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := udpConn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			udpConn.WriteTo(answerA(buf[:n]), addr)
+		}
+	}()
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+			s.lock.Lock()
+			s.tcpConnections++
+			s.tcpConns = append(s.tcpConns, conn)
+			s.lock.Unlock()
+			go s.serveTCP(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeDNSServer) serveTCP(conn net.Conn) {
+	defer conn.Close()
+	for {
+		length := make([]byte, 2)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(length))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		answer := answerA(query)
+		conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(answer))), answer...))
+	}
+}
+
+func (s *fakeDNSServer) addr() string {
+	return s.udpConn.LocalAddr().String()
+}
+
+func (s *fakeDNSServer) connectionCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.tcpConnections
+}
+
+func (s *fakeDNSServer) closeTCPConnections() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, conn := range s.tcpConns {
+		conn.Close()
+	}
+	s.tcpConns = nil
+}
+
+func (s *fakeDNSServer) Close() {
+	s.udpConn.Close()
+	s.tcpListener.Close()
+	s.closeTCPConnections()
+}
+
+func TestDNSSampler_CheckConnection(t *testing.T) {
+	server := newFakeDNSServer(t)
+	defer server.Close()
+
+	for _, protocol := range []DNSProtocol{DNSOverUDP, DNSOverTCP} {
+		for _, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+			t.Run(string(protocol)+"-"+string(connectionType), func(t *testing.T) {
+				locator := monitorapi.NewLocator().LocateDisruptionCheck("dns-"+string(protocol), OpenshiftTestsSource, connectionType)
+				sampler := NewDNSBackendWithLocator(locator, server.addr(), "kubernetes.default.svc.cluster.local", protocol, connectionType).
+					WithTimeout(time.Second)
+				for i := 0; i < 3; i++ {
+					if _, err := sampler.CheckConnection(context.Background()); err != nil {
+						t.Fatalf("expected lookup %d to succeed, got %v", i, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDNSSampler_reusedTCPConnection(t *testing.T) {
+	server := newFakeDNSServer(t)
+	defer server.Close()
+
+	locator := monitorapi.NewLocator().LocateDisruptionCheck("dns-tcp", OpenshiftTestsSource, monitorapi.ReusedConnectionType)
+	sampler := NewDNSBackendWithLocator(locator, server.addr(), "kubernetes.default.svc.cluster.local.", DNSOverTCP, monitorapi.ReusedConnectionType).
+		WithTimeout(time.Second)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := sampler.CheckConnection(ctx); err != nil {
+			t.Fatalf("expected lookup %d to succeed, got %v", i, err)
+		}
+	}
+	if count := server.connectionCount(); count != 1 {
+		t.Errorf("expected the lookups to share one connection, got %d connections", count)
+	}
+
+	// a broken connection fails a lookup and is replaced by the next one.
+	server.closeTCPConnections()
+	sampler.CheckConnection(ctx)
+	if _, err := sampler.CheckConnection(ctx); err != nil {
+		t.Fatalf("expected the lookup over a new connection to succeed, got %v", err)
+	}
+	if count := server.connectionCount(); count != 2 {
+		t.Errorf("expected one new connection, got %d connections", count)
+	}
+}
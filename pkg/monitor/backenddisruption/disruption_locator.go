@@ -21,6 +21,10 @@ func DisruptionEndedMessage(locator string, connectionType monitorapi.BackendCon
 		return monitorapi.NewMessage().
 			Reason(monitorapi.DisruptionEndedEventReason).
 			HumanMessagef("%s started responding to GET requests over reused connections", locator)
+	case monitorapi.StreamConnectionType:
+		return monitorapi.NewMessage().
+			Reason(monitorapi.DisruptionEndedEventReason).
+			HumanMessagef("%s started responding to heartbeats over stream connections", locator)
 	default:
 		return monitorapi.NewMessage().
 			Reason(monitorapi.DisruptionEndedEventReason).
@@ -50,6 +54,12 @@ func DisruptionBegan(locator string, connectionType monitorapi.BackendConnection
 					WithAnnotation(monitorapi.AnnotationRequestAuditID, auditID).
 					HumanMessagef("DNS lookup timeouts began for %s GET requests over reused connections: %v (likely a problem in cluster running tests, not the cluster under test)", locator, err),
 				monitorapi.DisruptionSamplerOutageBeganEventReason, monitorapi.Warning
+		case monitorapi.StreamConnectionType:
+			return monitorapi.NewMessage().
+					Reason(monitorapi.DisruptionSamplerOutageBeganEventReason).
+					WithAnnotation(monitorapi.AnnotationRequestAuditID, auditID).
+					HumanMessagef("DNS lookup timeouts began for %s heartbeats over stream connections: %v (likely a problem in cluster running tests, not the cluster under test)", locator, err),
+				monitorapi.DisruptionSamplerOutageBeganEventReason, monitorapi.Warning
 		default:
 			return monitorapi.NewMessage().
 					Reason(monitorapi.DisruptionSamplerOutageBeganEventReason).
@@ -71,6 +81,12 @@ func DisruptionBegan(locator string, connectionType monitorapi.BackendConnection
 				WithAnnotation(monitorapi.AnnotationRequestAuditID, auditID).
 				HumanMessagef("%s stopped responding to GET requests over reused connections: %v", locator, err),
 			monitorapi.DisruptionBeganEventReason, monitorapi.Error
+	case monitorapi.StreamConnectionType:
+		return monitorapi.NewMessage().
+				Reason(monitorapi.DisruptionBeganEventReason).
+				WithAnnotation(monitorapi.AnnotationRequestAuditID, auditID).
+				HumanMessagef("%s stopped responding to heartbeats over stream connections: %v", locator, err),
+			monitorapi.DisruptionBeganEventReason, monitorapi.Error
 	default:
 		return monitorapi.NewMessage().
 				Reason(monitorapi.DisruptionBeganEventReason).
@@ -0,0 +1,94 @@
+package backenddisruption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/sirupsen/logrus"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// samplerLifecycle starts and stops the disruptionSampler of a connectionChecker, one run at a time.  Every sampler of
+// this package, BackendSampler and the stream and DNS samplers, uses it.
+type samplerLifecycle struct {
+	// runningLock
+	runningLock sync.Mutex
+	// stopRunning is a context cancel for the localContext used to run
+	stopRunning context.CancelFunc
+	// consumptionFinished is closed when the consumer is done
+	consumptionFinished chan struct{}
+}
+
+func newSamplerLifecycle() *samplerLifecycle {
+	return &samplerLifecycle{
+		consumptionFinished: make(chan struct{}),
+	}
+}
+
+// run checks the connection, recording success/failure edges into the monitorRecorder, and blocks until the context
+// is closed or the lifecycle is stopped.
+func (l *samplerLifecycle) run(ctx context.Context, checker connectionChecker, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	if l.isRunning() {
+		return fmt.Errorf("cannot monitor twice at the same time")
+	}
+
+	// the producer is wired from the original context so that a base cancel stops everything
+	samplerContext, samplerCancel := context.WithCancel(ctx)
+	defer samplerCancel()
+	l.setCancelForRun(samplerCancel) // used from .stop later to stop monitoring
+
+	return runDisruptionSampler(ctx, samplerContext, checker, l.consumptionFinished, monitorRecorder, eventRecorder)
+}
+
+// start calls runFn, the RunEndpointMonitoring of the sampler, in the background.
+func (l *samplerLifecycle) start(ctx context.Context, runFn func(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	if monitorRecorder == nil {
+		return fmt.Errorf("monitor is required")
+	}
+
+	go func() {
+		err := runFn(ctx, monitorRecorder, eventRecorder)
+		if err != nil {
+			utilruntime.HandleError(err)
+		}
+	}()
+
+	return nil
+}
+
+func (l *samplerLifecycle) isRunning() bool {
+	l.runningLock.Lock()
+	defer l.runningLock.Unlock()
+	return l.stopRunning != nil
+}
+
+func (l *samplerLifecycle) setCancelForRun(cancelFunc context.CancelFunc) {
+	l.runningLock.Lock()
+	defer l.runningLock.Unlock()
+	l.stopRunning = cancelFunc
+}
+
+// stop stops the produce and consumer and blocks until the consumer is finished consuming.
+func (l *samplerLifecycle) stop(locator monitorapi.Locator) {
+	l.runningLock.Lock()
+	defer l.runningLock.Unlock()
+	if l.stopRunning == nil {
+		return
+	}
+	l.stopRunning()
+	l.stopRunning = nil
+
+	for {
+		logrus.WithField("locator", locator.OldLocator()).Info("waiting for consumer to finish")
+		select {
+		case <-l.consumptionFinished:
+			logrus.WithField("locator", locator.OldLocator()).Info("consumer finished")
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package backenddisruption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/events"
+)
+
+// StreamDialer opens long-lived streams to a backend, such as a gRPC stream or a WebSocket.
+type StreamDialer interface {
+	// Dial opens a stream that lives until ctx is closed or the stream is closed.  Dialing must give up after the
+	// timeout.  The auditID identifies the stream to servers that log it.
+	Dial(ctx context.Context, auditID string, timeout time.Duration) (Stream, error)
+}
+
+// Stream is a long-lived stream to a backend.
+type Stream interface {
+	// Heartbeat returns an error if the stream broke or the backend did not answer a heartbeat before ctx closed.
+	Heartbeat(ctx context.Context) error
+	Close() error
+}
+
+// StreamSampler is used to monitor a long-lived stream and ensure that it is always accessible.  It holds one stream
+// open and sends a heartbeat over it every second.  When the stream breaks, it is dialed again on the next sample.
+// It records the same disruption intervals as the BackendSampler, with the stream connection type, into the
+// monitorRecorder that is passed to the StartEndpointMonitoring call.
+type StreamSampler struct {
+	// locator is the string used to identify this in the monitorRecorder later on.
+	locator monitorapi.Locator
+	dialer  StreamDialer
+	// timeout bounds both dialing a stream and waiting for the answer to a heartbeat.
+	timeout *time.Duration
+
+	// streamLock serializes the heartbeats, a stream only carries one at a time.
+	streamLock sync.Mutex
+	// stream is the open stream, nil until dialed and after it broke.
+	stream Stream
+	// streamAuditID is the audit ID sent when dialing the open stream.
+	streamAuditID string
+
+	lifecycle *samplerLifecycle
+}
+
+// NewStreamBackendFromOpenshiftTests constructs a StreamSampler for the named disruption backend.
+func NewStreamBackendFromOpenshiftTests(disruptionBackendName string, dialer StreamDialer) *StreamSampler {
+	return NewStreamBackendWithLocator(
+		monitorapi.NewLocator().LocateDisruptionCheck(disruptionBackendName, OpenshiftTestsSource, monitorapi.StreamConnectionType),
+		dialer,
+	)
+}
+
+// NewStreamBackendWithLocator constructs a StreamSampler reporting with the given locator.
+func NewStreamBackendWithLocator(locator monitorapi.Locator, dialer StreamDialer) *StreamSampler {
+	ret := &StreamSampler{
+		locator:   locator,
+		dialer:    dialer,
+		lifecycle: newSamplerLifecycle(),
+	}
+
+	// TODO return error?  This is programmer error
+	if len(ret.GetDisruptionBackendName()) == 0 {
+		panic("missing disruption backend")
+	}
+
+	return ret
+}
+
+// WithTimeout sets how long dialing a stream and answering a heartbeat may take.
+func (b *StreamSampler) WithTimeout(timeout time.Duration) *StreamSampler {
+	b.timeout = &timeout
+	return b
+}
+
+func (b *StreamSampler) GetDisruptionBackendName() string {
+	return monitorapi.BackendDisruptionNameFromLocator(b.locator)
+}
+
+func (b *StreamSampler) GetLocator() monitorapi.Locator {
+	return b.locator
+}
+
+func (b *StreamSampler) GetConnectionType() monitorapi.BackendConnectionType {
+	return monitorapi.StreamConnectionType
+}
+
+func (b *StreamSampler) getTimeout() time.Duration {
+	if b.timeout == nil {
+		return 20 * time.Second
+	}
+	return *b.timeout
+}
+
+// CheckConnection sends a heartbeat over the stream, dialing it first if needed.  It returns the audit ID the stream
+// was dialed with and an error if there was one.
+func (b *StreamSampler) CheckConnection(ctx context.Context) (string, error) {
+	b.streamLock.Lock()
+	defer b.streamLock.Unlock()
+
+	if b.stream == nil {
+		auditID := uuid.New().String()
+		stream, err := b.dialer.Dial(ctx, auditID, b.getTimeout())
+		if ctx.Err() == context.Canceled {
+			// this isn't an error, we were simply cancelled
+			return auditID, nil
+		}
+		if err != nil {
+			return auditID, err
+		}
+		b.stream = stream
+		b.streamAuditID = auditID
+	}
+
+	heartbeatContext, heartbeatCancel := context.WithTimeout(ctx, b.getTimeout())
+	defer heartbeatCancel()
+	auditID := b.streamAuditID
+	heartbeatErr := b.stream.Heartbeat(heartbeatContext)
+	if ctx.Err() == context.Canceled {
+		// this isn't an error, we were simply cancelled
+		return auditID, nil
+	}
+	if heartbeatErr != nil {
+		// the next sample dials a new stream.
+		b.closeStream()
+		return auditID, fmt.Errorf("stream broke: %w", heartbeatErr)
+	}
+	return auditID, nil
+}
+
+// closeStream must be called with the streamLock held.
+func (b *StreamSampler) closeStream() {
+	if b.stream == nil {
+		return
+	}
+	if err := b.stream.Close(); err != nil {
+		utilruntime.HandleError(fmt.Errorf("error closing stream: %v: %w", b.locator, err))
+	}
+	b.stream = nil
+	b.streamAuditID = ""
+}
+
+// RunEndpointMonitoring dials the stream, starts sending heartbeats, and recording success/failure edges into the
+// monitorRecorder, and blocks until the context is closed or the sampler is closed.
+func (b *StreamSampler) RunEndpointMonitoring(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	defer func() {
+		b.streamLock.Lock()
+		defer b.streamLock.Unlock()
+		b.closeStream()
+	}()
+
+	return b.lifecycle.run(ctx, b, monitorRecorder, eventRecorder)
+}
+
+// Stop stops the produce and consumer and blocks until the consumer is finished consuming.
+func (b *StreamSampler) Stop() {
+	b.lifecycle.stop(b.locator)
+}
+
+// StartEndpointMonitoring dials the stream, starts sending heartbeats, and recording success/failure edges into the
+// monitorRecorder
+func (b *StreamSampler) StartEndpointMonitoring(ctx context.Context, monitorRecorder monitorapi.RecorderWriter, eventRecorder events.EventRecorder) error {
+	return b.lifecycle.start(ctx, b.RunEndpointMonitoring, monitorRecorder, eventRecorder)
+}
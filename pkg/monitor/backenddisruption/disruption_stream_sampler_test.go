@@ -0,0 +1,218 @@
+package backenddisruption
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/client-go/rest"
+
+	monitor2 "github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestStreamSampler_webSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	// hijacked connections are not closed by the test server, so keep track of them.
+	connsLock := sync.Mutex{}
+	conns := []*websocket.Conn{}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connsLock.Lock()
+		conns = append(conns, conn)
+		connsLock.Unlock()
+		// the default ping handler answers with a pong while reading.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer testServer.Close()
+
+	sampler := NewStreamBackendFromOpenshiftTests("websocket", NewWebSocketDialer("ws"+strings.TrimPrefix(testServer.URL, "http"))).
+		WithTimeout(time.Second)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := sampler.CheckConnection(ctx); err != nil {
+			t.Fatalf("expected heartbeat %d to succeed, got %v", i, err)
+		}
+	}
+
+	connsLock.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	connsLock.Unlock()
+	if _, err := sampler.CheckConnection(ctx); err == nil || !strings.Contains(err.Error(), "stream broke") {
+		t.Fatalf("expected the stream to break, got %v", err)
+	}
+	if _, err := sampler.CheckConnection(ctx); err != nil {
+		t.Fatalf("expected a new stream to be dialed, got %v", err)
+	}
+}
+
+// The WebSocket of an API server is verified with the CA of its rest config, never skipped.
+func TestAPIServerWebSocketBackend_verifiesServer(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer testServer.Close()
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testServer.Certificate().Raw})
+
+	sampler, err := NewAPIServerWebSocketBackend(&rest.Config{Host: testServer.URL}, "websocket", "/watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sampler.WithTimeout(time.Second).CheckConnection(context.Background()); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("expected the server certificate to be rejected without the CA, got %v", err)
+	}
+
+	sampler, err = NewAPIServerWebSocketBackend(&rest.Config{Host: testServer.URL, TLSClientConfig: rest.TLSClientConfig{CAData: caData}}, "websocket", "/watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sampler.WithTimeout(time.Second).CheckConnection(context.Background()); err != nil {
+		t.Fatalf("expected the server to be trusted with the CA, got %v", err)
+	}
+}
+
+func TestStreamSampler_grpcHealth(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	sampler := NewStreamBackendFromOpenshiftTests("grpc", NewGRPCHealthDialer(listener.Addr().String(), "")).
+		WithTimeout(time.Second)
+	ctx := context.Background()
+	if _, err := sampler.CheckConnection(ctx); err != nil {
+		t.Fatalf("expected the heartbeat to succeed, got %v", err)
+	}
+
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if _, err := sampler.CheckConnection(ctx); err == nil || !strings.Contains(err.Error(), "NOT_SERVING") {
+		t.Fatalf("expected the heartbeat to fail, got %v", err)
+	}
+
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	if _, err := sampler.CheckConnection(ctx); err != nil {
+		t.Fatalf("expected the heartbeat to succeed, got %v", err)
+	}
+
+	server.Stop()
+	if _, err := sampler.CheckConnection(ctx); err == nil {
+		t.Fatalf("expected the stream to break")
+	}
+}
+
+// fakeStreamDialer dials streams whose heartbeats fail while broken is set.
+type fakeStreamDialer struct {
+	lock   sync.Mutex
+	broken bool
+}
+
+func (d *fakeStreamDialer) setBroken(broken bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.broken = broken
+}
+
+func (d *fakeStreamDialer) Dial(ctx context.Context, auditID string, timeout time.Duration) (Stream, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.broken {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return &fakeStream{dialer: d}, nil
+}
+
+type fakeStream struct {
+	dialer *fakeStreamDialer
+}
+
+func (s *fakeStream) Heartbeat(ctx context.Context) error {
+	s.dialer.lock.Lock()
+	defer s.dialer.lock.Unlock()
+	if s.dialer.broken {
+		return fmt.Errorf("connection reset")
+	}
+	return nil
+}
+
+func (s *fakeStream) Close() error {
+	return nil
+}
+
+func TestStreamSampler_RunEndpointMonitoring(t *testing.T) {
+	dialer := &fakeStreamDialer{}
+	sampler := NewStreamBackendFromOpenshiftTests("fake-stream-connections", dialer)
+	recorder := monitor2.NewRecorder()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- sampler.RunEndpointMonitoring(ctx, recorder, nil)
+	}()
+
+	time.Sleep(1500 * time.Millisecond)
+	dialer.setBroken(true)
+	time.Sleep(2500 * time.Millisecond)
+	dialer.setBroken(false)
+	time.Sleep(2500 * time.Millisecond)
+	sampler.Stop()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	var began, ended bool
+	for _, interval := range recorder.Intervals(time.Time{}, time.Time{}) {
+		if interval.Source != monitorapi.SourceDisruption {
+			continue
+		}
+		if interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey] != "fake-stream-connections" ||
+			interval.Locator.Keys[monitorapi.LocatorConnectionKey] != string(monitorapi.StreamConnectionType) {
+			t.Errorf("unexpected locator %v", interval.Locator)
+		}
+		switch interval.Message.Reason {
+		case monitorapi.DisruptionBeganEventReason:
+			began = strings.Contains(interval.Message.HumanMessage, "stopped responding to heartbeats over stream connections")
+		case monitorapi.DisruptionEndedEventReason:
+			ended = ended || began
+		}
+	}
+	if !began || !ended {
+		t.Errorf("expected the disruption to begin and end, got %v", recorder.Intervals(time.Time{}, time.Time{}))
+	}
+}
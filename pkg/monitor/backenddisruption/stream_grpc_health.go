@@ -0,0 +1,133 @@
+package backenddisruption
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// auditIDMetadataKey carries the audit ID of a gRPC stream, the gRPC equivalent of the Audit-ID header.
+const auditIDMetadataKey = "audit-id"
+
+// GRPCHealthDialer opens a Watch stream of the standard gRPC health service.  Heartbeats are health Checks sent over
+// the same connection, so a stream breaks when either the Watch stream or the connection breaks.
+type GRPCHealthDialer struct {
+	// target is the gRPC target, for instance host:port.
+	target string
+	// service is the name of the service whose health is watched, empty for the overall health of the server.
+	service string
+	// tlsConfig is used to secure the connection, if nil the connection is not secured.
+	tlsConfig *tls.Config
+}
+
+// NewGRPCHealthDialer constructs a StreamDialer for the health service of the gRPC server at target.
+func NewGRPCHealthDialer(target, service string) *GRPCHealthDialer {
+	return &GRPCHealthDialer{
+		target:  target,
+		service: service,
+	}
+}
+
+// WithTLSConfig secures the connection with both the CA bundle for trusting the server and the client cert/key pair
+// for identifying to the server
+func (d *GRPCHealthDialer) WithTLSConfig(tlsConfig *tls.Config) *GRPCHealthDialer {
+	d.tlsConfig = tlsConfig
+	return d
+}
+
+func (d *GRPCHealthDialer) Dial(ctx context.Context, auditID string, timeout time.Duration) (Stream, error) {
+	transportCredentials := insecure.NewCredentials()
+	if d.tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(d.tlsConfig)
+	}
+
+	dialContext, dialCancel := context.WithTimeout(ctx, timeout)
+	defer dialCancel()
+	conn, err := grpc.DialContext(dialContext, d.target,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithBlock(),
+		grpc.WithReturnConnectionError(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	streamContext, streamCancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, auditIDMetadataKey, auditID))
+	client := healthpb.NewHealthClient(conn)
+	watch, err := client.Watch(streamContext, &healthpb.HealthCheckRequest{Service: d.service})
+	if err != nil {
+		streamCancel()
+		conn.Close()
+		return nil, err
+	}
+
+	stream := &grpcHealthStream{
+		conn:    conn,
+		client:  client,
+		service: d.service,
+		cancel:  streamCancel,
+	}
+	go stream.watch(watch)
+	return stream, nil
+}
+
+type grpcHealthStream struct {
+	conn    *grpc.ClientConn
+	client  healthpb.HealthClient
+	service string
+	cancel  context.CancelFunc
+
+	lock sync.Mutex
+	// status is the last status sent over the Watch stream.
+	status healthpb.HealthCheckResponse_ServingStatus
+	// watchErr is set once the Watch stream broke.
+	watchErr error
+}
+
+func (s *grpcHealthStream) watch(watch healthpb.Health_WatchClient) {
+	for {
+		resp, err := watch.Recv()
+		s.lock.Lock()
+		if err != nil {
+			s.watchErr = err
+			s.lock.Unlock()
+			return
+		}
+		s.status = resp.Status
+		s.lock.Unlock()
+	}
+}
+
+func (s *grpcHealthStream) Heartbeat(ctx context.Context) error {
+	s.lock.Lock()
+	watchErr, status := s.watchErr, s.status
+	s.lock.Unlock()
+	if watchErr != nil {
+		return fmt.Errorf("watch stream closed: %w", watchErr)
+	}
+	if status != healthpb.HealthCheckResponse_UNKNOWN && status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("watch stream reported %v", status)
+	}
+
+	resp, err := s.client.Check(ctx, &healthpb.HealthCheckRequest{Service: s.service})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("health check reported %v", resp.Status)
+	}
+	return nil
+}
+
+func (s *grpcHealthStream) Close() error {
+	s.cancel()
+	return s.conn.Close()
+}
@@ -0,0 +1,220 @@
+package backenddisruption
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// WebSocketDialer opens a WebSocket.  Heartbeats are pings that the server must answer with a pong, which every
+// WebSocket server does on its own.  Messages sent by the server are read and discarded.
+type WebSocketDialer struct {
+	// url is the ws:// or wss:// URL of the WebSocket.
+	url string
+	// tlsConfig holds the CA bundle for verifying the server and client cert/key pair for identifying to the server.
+	// The server is verified with the system roots when nil.
+	tlsConfig *tls.Config
+	// bearerToken is the token to be used when contacting a server. Authorization : Bearer XXXXXX
+	bearerToken string
+	// bearerTokenFile is the file containing a token to be used when contacting a server. Authorization : Bearer XXXXXX
+	bearerTokenFile string
+	// subprotocols are offered to the server, for instance the channel protocols of exec and port-forward.
+	subprotocols []string
+}
+
+// NewWebSocketDialer constructs a StreamDialer for the WebSocket at the ws:// or wss:// url.
+func NewWebSocketDialer(url string) *WebSocketDialer {
+	return &WebSocketDialer{
+		url: url,
+	}
+}
+
+// NewAPIServerWebSocketBackend constructs a StreamSampler holding a WebSocket open to a kube-like API server, for
+// instance a watch.  The path must start with a slash.
+func NewAPIServerWebSocketBackend(clientConfig *rest.Config, disruptionBackendName, path string) (*StreamSampler, error) {
+	historicalBackendDisruptionDataName := fmt.Sprintf("%s-%v-connections", disruptionBackendName, monitorapi.StreamConnectionType)
+
+	kubeTransportConfig, err := clientConfig.TransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := transport.TLSConfigFor(kubeTransportConfig)
+	if err != nil {
+		return nil, err
+	}
+	host, err := url.Parse(clientConfig.Host)
+	if err != nil {
+		return nil, err
+	}
+	switch host.Scheme {
+	case "https":
+		host.Scheme = "wss"
+	default:
+		host.Scheme = "ws"
+	}
+
+	dialer := NewWebSocketDialer(strings.TrimSuffix(host.String(), "/")+path).
+		WithTLSConfig(tlsConfig).
+		WithBearerTokenAuth(kubeTransportConfig.BearerToken, kubeTransportConfig.BearerTokenFile)
+	return NewStreamBackendFromOpenshiftTests(historicalBackendDisruptionDataName, dialer), nil
+}
+
+// WithTLSConfig sets both the CA bundle for trusting the server and the client cert/key pair for identifying to the server
+func (d *WebSocketDialer) WithTLSConfig(tlsConfig *tls.Config) *WebSocketDialer {
+	d.tlsConfig = tlsConfig
+	return d
+}
+
+// WithBearerTokenAuth sets bearer tokens to use
+func (d *WebSocketDialer) WithBearerTokenAuth(token, tokenFile string) *WebSocketDialer {
+	d.bearerToken = token
+	d.bearerTokenFile = tokenFile
+	return d
+}
+
+// WithSubprotocols sets the subprotocols offered to the server.
+func (d *WebSocketDialer) WithSubprotocols(subprotocols ...string) *WebSocketDialer {
+	d.subprotocols = subprotocols
+	return d
+}
+
+func (d *WebSocketDialer) getBearerToken() (string, error) {
+	if len(d.bearerToken) > 0 || len(d.bearerTokenFile) == 0 {
+		return d.bearerToken, nil
+	}
+	token, err := os.ReadFile(d.bearerTokenFile)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+func (d *WebSocketDialer) Dial(ctx context.Context, auditID string, timeout time.Duration) (Stream, error) {
+	header := http.Header{}
+	header.Set(audit.HeaderAuditID, auditID)
+	token, err := d.getBearerToken()
+	if err != nil {
+		return nil, err
+	}
+	if len(token) > 0 {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: timeout,
+		TLSClientConfig:  d.tlsConfig,
+		Subprotocols:     d.subprotocols,
+	}
+	dialContext, dialCancel := context.WithTimeout(ctx, timeout)
+	defer dialCancel()
+	conn, resp, err := dialer.DialContext(dialContext, d.url, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("error opening websocket: %v: %w", resp.Status, err)
+		}
+		return nil, err
+	}
+
+	stream := &webSocketStream{
+		conn:     conn,
+		pongs:    make(chan string, 1),
+		readDone: make(chan struct{}),
+	}
+	conn.SetPongHandler(stream.handlePong)
+	go stream.read()
+	return stream, nil
+}
+
+type webSocketStream struct {
+	conn *websocket.Conn
+
+	// pongs receives the payload of every pong, pongs nobody waits for are dropped.
+	pongs chan string
+	// heartbeats numbers the pings so that a late pong is not mistaken for the answer to the current ping.
+	heartbeats int
+
+	lock sync.Mutex
+	// readErr is set once reading from the WebSocket failed, readDone is closed at the same time.
+	readErr  error
+	readDone chan struct{}
+}
+
+func (s *webSocketStream) handlePong(payload string) error {
+	select {
+	case s.pongs <- payload:
+	default:
+	}
+	return nil
+}
+
+// read consumes the messages of the server, pongs are only handled while reading.
+func (s *webSocketStream) read() {
+	for {
+		_, reader, err := s.conn.NextReader()
+		if err == nil {
+			_, err = io.Copy(io.Discard, reader)
+		}
+		if err != nil {
+			s.lock.Lock()
+			s.readErr = err
+			s.lock.Unlock()
+			close(s.readDone)
+			return
+		}
+	}
+}
+
+func (s *webSocketStream) getReadErr() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.readErr
+}
+
+func (s *webSocketStream) Heartbeat(ctx context.Context) error {
+	if err := s.getReadErr(); err != nil {
+		return fmt.Errorf("websocket closed: %w", err)
+	}
+
+	s.heartbeats++
+	payload := strconv.Itoa(s.heartbeats)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(20 * time.Second)
+	}
+	if err := s.conn.WriteControl(websocket.PingMessage, []byte(payload), deadline); err != nil {
+		return err
+	}
+	for {
+		select {
+		case pong := <-s.pongs:
+			if pong == payload {
+				return nil
+			}
+		case <-s.readDone:
+			return fmt.Errorf("websocket closed: %w", s.getReadErr())
+		case <-ctx.Done():
+			return fmt.Errorf("no pong received: %w", ctx.Err())
+		}
+	}
+}
+
+func (s *webSocketStream) Close() error {
+	return s.conn.Close()
+}
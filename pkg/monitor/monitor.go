@@ -32,23 +32,37 @@ type Monitor struct {
 	recorder monitorapi.Recorder
 	junits   []*junitapi.JUnitTestCase
 
+	// replay is set when the monitor tests are run against saved artifacts instead of a cluster.
+	replay *ReplayArtifacts
+	// resumedIntervals are the intervals of the interrupted run this monitor continues, if any.
+	resumedIntervals monitorapi.Intervals
+	options          Options
+
 	lock      sync.Mutex
 	stopFn    context.CancelFunc
 	startTime time.Time
 	stopTime  time.Time
 }
 
+// Options holds the optional behavior of a Monitor, the zero value is the default.
+type Options struct {
+	// WriteParquet writes the monitor test junits as parquet in addition to junit xml.
+	WriteParquet bool
+}
+
 // NewMonitor creates a monitor with the default sampling interval.
 func NewMonitor(
 	recorder monitorapi.Recorder,
 	adminKubeConfig *rest.Config,
 	storageDir string,
-	monitorTestRegistry monitortestframework.MonitorTestRegistry) Interface {
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	options Options) Interface {
 	return &Monitor{
 		adminKubeConfig:     adminKubeConfig,
 		recorder:            recorder,
 		monitorTestRegistry: monitorTestRegistry,
 		storageDir:          storageDir,
+		options:             options,
 	}
 }
 
@@ -62,7 +76,26 @@ func (m *Monitor) Start(ctx context.Context) error {
 		return fmt.Errorf("monitor already started")
 	}
 	ctx, m.stopFn = context.WithCancel(ctx)
+
+	if m.replay != nil {
+		m.startTime = m.replay.Beginning
+		m.stopTime = m.replay.End
+		localJunits, err := m.monitorTestRegistry.PrepareForReplay(ctx, m.replay.ClusterData, m.startTime, m.stopTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error preparing replay, continuing, junit will reflect this. %v\n", err)
+		}
+		m.junits = append(m.junits, localJunits...)
+		fmt.Printf("All monitor tests prepared for replay.\n")
+		return nil
+	}
+
 	m.startTime = time.Now()
+	// the monitor tests evaluate the intervals of the interrupted run too.
+	for _, interval := range m.resumedIntervals {
+		if !interval.From.IsZero() && interval.From.Before(m.startTime) {
+			m.startTime = interval.From
+		}
+	}
 
 	localJunits, err := m.monitorTestRegistry.StartCollection(ctx, m.adminKubeConfig, m.recorder)
 	if err != nil {
@@ -85,19 +118,22 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	m.stopFn()
 	m.stopFn = nil
 
-	preStopTime := time.Now()
+	// when replaying, there is no cluster to collect from and the stop time comes from the saved intervals.
+	if m.replay == nil {
+		preStopTime := time.Now()
 
-	fmt.Fprintf(os.Stderr, "Collecting data.\n")
-	collectedIntervals, collectionJunits, err := m.monitorTestRegistry.CollectData(ctx, m.storageDir, m.startTime, preStopTime)
-	if err != nil {
-		// these errors are represented as junit, always continue to the next step
-		fmt.Fprintf(os.Stderr, "Error collecting data, continuing, junit will reflect this. %v\n", err)
-	}
-	m.recorder.AddIntervals(collectedIntervals...)
-	m.junits = append(m.junits, collectionJunits...)
+		fmt.Fprintf(os.Stderr, "Collecting data.\n")
+		collectedIntervals, collectionJunits, err := m.monitorTestRegistry.CollectData(ctx, m.storageDir, m.startTime, preStopTime)
+		if err != nil {
+			// these errors are represented as junit, always continue to the next step
+			fmt.Fprintf(os.Stderr, "Error collecting data, continuing, junit will reflect this. %v\n", err)
+		}
+		m.recorder.AddIntervals(collectedIntervals...)
+		m.junits = append(m.junits, collectionJunits...)
 
-	// set the stop time for after we finished.
-	m.stopTime = time.Now()
+		// set the stop time for after we finished.
+		m.stopTime = time.Now()
+	}
 
 	fmt.Fprintf(os.Stderr, "Computing intervals.\n")
 	computedIntervals, computedJunit, err := m.monitorTestRegistry.ConstructComputedIntervals(
@@ -110,6 +146,12 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 		// these errors are represented as junit, always continue to the next step
 		fmt.Fprintf(os.Stderr, "Error computing intervals, continuing, junit will reflect this. %v\n", err)
 	}
+	if m.replay != nil {
+		computedIntervals = withoutReplayedIntervals(computedIntervals, m.replay.Intervals)
+	}
+	if len(m.resumedIntervals) > 0 {
+		computedIntervals = withoutReplayedIntervals(computedIntervals, m.resumedIntervals)
+	}
 	m.recorder.AddIntervals(computedIntervals...)
 	m.junits = append(m.junits, computedJunit...)
 
@@ -239,5 +281,15 @@ func (m *Monitor) serializeJunit(ctx context.Context, storageDir, junitSuiteName
 	filePrefix := "e2e-monitor-tests"
 	path := filepath.Join(storageDir, fmt.Sprintf("%s_%s.xml", filePrefix, fileSuffix))
 	fmt.Fprintf(os.Stderr, "Writing JUnit report to %s\n", path)
-	return &junitSuite, os.WriteFile(path, test.StripANSI(out), 0640)
+	if err := os.WriteFile(path, test.StripANSI(out), 0640); err != nil {
+		return nil, err
+	}
+
+	if m.options.WriteParquet {
+		parquetPath := filepath.Join(storageDir, fmt.Sprintf("%s_%s.parquet", filePrefix, fileSuffix))
+		if err := monitorserialization.JUnitsToParquet(parquetPath, junitSuite.TestCases); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write junit parquet, err: %v\n", err)
+		}
+	}
+	return &junitSuite, nil
 }
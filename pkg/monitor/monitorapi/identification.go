@@ -10,6 +10,8 @@ type BackendConnectionType string
 const (
 	NewConnectionType    BackendConnectionType = "new"
 	ReusedConnectionType BackendConnectionType = "reused"
+	// StreamConnectionType is a long-lived stream, such as a gRPC stream or a WebSocket, checked with heartbeats.
+	StreamConnectionType BackendConnectionType = "stream"
 )
 
 func IsE2ETest(l Locator) bool {
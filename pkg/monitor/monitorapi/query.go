@@ -0,0 +1,511 @@
+package monitorapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// IntervalQuery is a parsed interval query.  Queries are small boolean expressions over interval fields, for instance
+//
+//	source=Disruption AND locator.namespace=~"openshift-.*" AND duration>5s AND overlaps(reason=UpgradeStarted)
+//
+// Comparisons have the form field op value where op is one of =, !=, =~, !~, >, >=, <, <=.  Supported fields are
+// source, level, reason, cause, message, display, locator, locator.type, locator.<key>, annotation.<key>, duration,
+// from and to.  duration accepts Go durations or a number of seconds, from and to accept RFC3339 times.  Values
+// containing spaces or operator characters must be quoted.
+//
+// Comparisons are combined with AND, OR, NOT and parentheses.  Temporal operators match intervals by their
+// relationship to the intervals matched by the nested query:
+//
+//	overlaps(query)      the interval overlaps an interval matching query
+//	during(query)        the interval is contained by an interval matching query
+//	within(N, query)     the interval is at most N away from an interval matching query, N is a duration or seconds
+//
+// An interval can satisfy a temporal operator through itself if it matches the nested query.
+type IntervalQuery struct {
+	query string
+	root  queryNode
+}
+
+// ParseIntervalQuery parses query.  An empty query matches every interval.
+func ParseIntervalQuery(query string) (*IntervalQuery, error) {
+	tokens, err := lexIntervalQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == queryTokenEOF {
+		return &IntervalQuery{query: query, root: matchAllNode{}}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != queryTokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d in %q", next.text, next.pos, query)
+	}
+	return &IntervalQuery{query: query, root: root}, nil
+}
+
+// CompileIntervalQuery parses query and binds it to intervals.  See IntervalQuery.Matcher.
+func CompileIntervalQuery(query string, intervals Intervals) (EventIntervalMatchesFunc, error) {
+	q, err := ParseIntervalQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return q.Matcher(intervals), nil
+}
+
+// Matcher returns a function matching the query.  Temporal operators are evaluated against intervals, which should
+// be the full set being filtered, not the subset matching the rest of the query.
+func (q *IntervalQuery) Matcher(intervals Intervals) EventIntervalMatchesFunc {
+	return q.root.matcher(intervals)
+}
+
+func (q *IntervalQuery) String() string {
+	return q.query
+}
+
+type queryNode interface {
+	matcher(intervals Intervals) EventIntervalMatchesFunc
+}
+
+type matchAllNode struct{}
+
+func (matchAllNode) matcher(Intervals) EventIntervalMatchesFunc {
+	return func(Interval) bool { return true }
+}
+
+type andNode []queryNode
+
+func (n andNode) matcher(intervals Intervals) EventIntervalMatchesFunc {
+	filters := []EventIntervalMatchesFunc{}
+	for _, child := range n {
+		filters = append(filters, child.matcher(intervals))
+	}
+	return And(filters...)
+}
+
+type orNode []queryNode
+
+func (n orNode) matcher(intervals Intervals) EventIntervalMatchesFunc {
+	filters := []EventIntervalMatchesFunc{}
+	for _, child := range n {
+		filters = append(filters, child.matcher(intervals))
+	}
+	return Or(filters...)
+}
+
+type notNode struct {
+	child queryNode
+}
+
+func (n notNode) matcher(intervals Intervals) EventIntervalMatchesFunc {
+	return Not(n.child.matcher(intervals))
+}
+
+type comparisonNode struct {
+	match EventIntervalMatchesFunc
+}
+
+func (n comparisonNode) matcher(Intervals) EventIntervalMatchesFunc {
+	return n.match
+}
+
+type temporalOperator string
+
+const (
+	temporalOverlaps temporalOperator = "overlaps"
+	temporalDuring   temporalOperator = "during"
+	temporalWithin   temporalOperator = "within"
+)
+
+type temporalNode struct {
+	operator temporalOperator
+	window   time.Duration
+	child    queryNode
+}
+
+func (n temporalNode) matcher(intervals Intervals) EventIntervalMatchesFunc {
+	related := intervals.Filter(n.child.matcher(intervals))
+	sort.Slice(related, func(i, j int) bool {
+		return related[i].From.Before(related[j].From)
+	})
+	// latestEnd[i] is the latest end of related[0:i+1], which lets us answer "does any interval starting before
+	// bound end after threshold" with a single binary search.
+	latestEnd := make([]time.Time, len(related))
+	for i := range related {
+		latestEnd[i] = intervalEnd(related[i])
+		if i > 0 && latestEnd[i-1].After(latestEnd[i]) {
+			latestEnd[i] = latestEnd[i-1]
+		}
+	}
+
+	return func(eventInterval Interval) bool {
+		var bound, threshold time.Time
+		switch n.operator {
+		case temporalOverlaps:
+			bound, threshold = intervalEnd(eventInterval), eventInterval.From
+		case temporalWithin:
+			bound, threshold = intervalEnd(eventInterval).Add(n.window), eventInterval.From.Add(-n.window)
+		case temporalDuring:
+			bound, threshold = eventInterval.From, intervalEnd(eventInterval)
+		}
+		last := sort.Search(len(related), func(i int) bool {
+			return related[i].From.After(bound)
+		}) - 1
+		return last >= 0 && !latestEnd[last].Before(threshold)
+	}
+}
+
+// intervalEnd treats events without a To as instants.
+func intervalEnd(interval Interval) time.Time {
+	if interval.To.IsZero() {
+		return interval.From
+	}
+	return interval.To
+}
+
+type queryTokenKind int
+
+const (
+	queryTokenEOF queryTokenKind = iota
+	queryTokenWord
+	queryTokenString
+	queryTokenOperator
+	queryTokenLeftParen
+	queryTokenRightParen
+	queryTokenComma
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	pos  int
+}
+
+func isQueryOperatorChar(r rune) bool {
+	return strings.ContainsRune("=!~<>", r)
+}
+
+func lexIntervalQuery(query string) ([]queryToken, error) {
+	tokens := []queryToken{}
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: queryTokenLeftParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: queryTokenRightParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, queryToken{kind: queryTokenComma, text: ",", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			// only the quote and the backslash can be escaped so that regular expressions can be written as is.
+			value := strings.Builder{}
+			start := i
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string starting at position %d in %q", start, query)
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == r || runes[i+1] == '\\') {
+					i++
+				} else if runes[i] == r {
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenString, text: value.String(), pos: start})
+		case isQueryOperatorChar(r):
+			start := i
+			for i < len(runes) && isQueryOperatorChar(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenOperator, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !isQueryOperatorChar(runes[i]) && !strings.ContainsRune("()\",'", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+	return append(tokens, queryToken{kind: queryTokenEOF, pos: len(runes)}), nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	next   int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) consume() queryToken {
+	token := p.tokens[p.next]
+	if token.kind != queryTokenEOF {
+		p.next++
+	}
+	return token
+}
+
+func (p *queryParser) isKeyword(keyword string) bool {
+	token := p.peek()
+	return token.kind == queryTokenWord && strings.EqualFold(token.text, keyword)
+}
+
+func (p *queryParser) expect(kind queryTokenKind, description string) error {
+	token := p.consume()
+	if token.kind != kind {
+		return fmt.Errorf("expected %s at position %d, got %q", description, token.pos, token.text)
+	}
+	return nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	ret := orNode{}
+	for {
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, child)
+		if !p.isKeyword("OR") {
+			break
+		}
+		p.consume()
+	}
+	if len(ret) == 1 {
+		return ret[0], nil
+	}
+	return ret, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	ret := andNode{}
+	for {
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, child)
+		if !p.isKeyword("AND") {
+			break
+		}
+		p.consume()
+	}
+	if len(ret) == 1 {
+		return ret[0], nil
+	}
+	return ret, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if p.isKeyword("NOT") {
+		p.consume()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	token := p.consume()
+	switch token.kind {
+	case queryTokenLeftParen:
+		child, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(queryTokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+		return child, nil
+
+	case queryTokenWord:
+		if p.peek().kind == queryTokenLeftParen {
+			return p.parseTemporal(token)
+		}
+		operator := p.consume()
+		if operator.kind != queryTokenOperator {
+			return nil, fmt.Errorf("expected an operator after %q at position %d, got %q", token.text, operator.pos, operator.text)
+		}
+		value := p.consume()
+		if value.kind != queryTokenWord && value.kind != queryTokenString {
+			return nil, fmt.Errorf("expected a value after %q at position %d, got %q", token.text+operator.text, value.pos, value.text)
+		}
+		match, err := newComparison(token.text, operator.text, value.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comparison at position %d: %w", token.pos, err)
+		}
+		return comparisonNode{match: match}, nil
+
+	default:
+		return nil, fmt.Errorf("expected a comparison at position %d, got %q", token.pos, token.text)
+	}
+}
+
+func (p *queryParser) parseTemporal(name queryToken) (queryNode, error) {
+	ret := temporalNode{operator: temporalOperator(strings.ToLower(name.text))}
+	switch ret.operator {
+	case temporalOverlaps, temporalDuring, temporalWithin:
+	default:
+		return nil, fmt.Errorf("unknown function %q at position %d, expected overlaps, during or within", name.text, name.pos)
+	}
+	p.consume() // the left paren
+
+	if ret.operator == temporalWithin {
+		window := p.consume()
+		if window.kind != queryTokenWord && window.kind != queryTokenString {
+			return nil, fmt.Errorf("expected a duration at position %d, got %q", window.pos, window.text)
+		}
+		duration, err := parseQueryDuration(window.text)
+		if err != nil {
+			return nil, err
+		}
+		ret.window = duration
+		if err := p.expect(queryTokenComma, "','"); err != nil {
+			return nil, err
+		}
+	}
+
+	child, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	ret.child = child
+	if err := p.expect(queryTokenRightParen, "')'"); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// parseQueryDuration accepts Go durations and plain numbers of seconds.
+func parseQueryDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return duration, nil
+}
+
+func newComparison(field, operator, value string) (EventIntervalMatchesFunc, error) {
+	switch field {
+	case "duration":
+		expected, err := parseQueryDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		compare, err := orderedComparison(operator)
+		if err != nil {
+			return nil, err
+		}
+		return func(eventInterval Interval) bool {
+			return compare(int64(intervalEnd(eventInterval).Sub(eventInterval.From)), int64(expected))
+		}, nil
+
+	case "from", "to":
+		expected, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q, expected RFC3339", value)
+		}
+		compare, err := orderedComparison(operator)
+		if err != nil {
+			return nil, err
+		}
+		return func(eventInterval Interval) bool {
+			actual := eventInterval.From
+			if field == "to" {
+				actual = intervalEnd(eventInterval)
+			}
+			return compare(actual.UnixNano(), expected.UnixNano())
+		}, nil
+	}
+
+	getter, err := stringField(field)
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "=":
+		return func(eventInterval Interval) bool { return getter(eventInterval) == value }, nil
+	case "!=":
+		return func(eventInterval Interval) bool { return getter(eventInterval) != value }, nil
+	case "=~", "!~":
+		// like --locator, expressions are not anchored.
+		expected, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", value, err)
+		}
+		negate := operator == "!~"
+		return func(eventInterval Interval) bool { return expected.MatchString(getter(eventInterval)) != negate }, nil
+	default:
+		return nil, fmt.Errorf("operator %q is not supported for %q, expected =, !=, =~ or !~", operator, field)
+	}
+}
+
+func orderedComparison(operator string) (func(actual, expected int64) bool, error) {
+	switch operator {
+	case "=":
+		return func(actual, expected int64) bool { return actual == expected }, nil
+	case "!=":
+		return func(actual, expected int64) bool { return actual != expected }, nil
+	case ">":
+		return func(actual, expected int64) bool { return actual > expected }, nil
+	case ">=":
+		return func(actual, expected int64) bool { return actual >= expected }, nil
+	case "<":
+		return func(actual, expected int64) bool { return actual < expected }, nil
+	case "<=":
+		return func(actual, expected int64) bool { return actual <= expected }, nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q", operator)
+	}
+}
+
+func stringField(field string) (func(Interval) string, error) {
+	switch {
+	case field == "source":
+		return func(eventInterval Interval) string { return string(eventInterval.Source) }, nil
+	case field == "level":
+		return func(eventInterval Interval) string { return eventInterval.Level.String() }, nil
+	case field == "reason":
+		return func(eventInterval Interval) string { return string(eventInterval.Message.Reason) }, nil
+	case field == "cause":
+		return func(eventInterval Interval) string { return eventInterval.Message.Cause }, nil
+	case field == "message":
+		return func(eventInterval Interval) string { return eventInterval.Message.HumanMessage }, nil
+	case field == "display":
+		return func(eventInterval Interval) string { return strconv.FormatBool(eventInterval.Display) }, nil
+	case field == "locator":
+		return func(eventInterval Interval) string { return eventInterval.Locator.OldLocator() }, nil
+	case field == "locator.type":
+		return func(eventInterval Interval) string { return string(eventInterval.Locator.Type) }, nil
+	case strings.HasPrefix(field, "locator.") && len(field) > len("locator."):
+		key := LocatorKey(strings.TrimPrefix(field, "locator."))
+		return func(eventInterval Interval) string { return eventInterval.Locator.Keys[key] }, nil
+	case strings.HasPrefix(field, "annotation.") && len(field) > len("annotation."):
+		key := AnnotationKey(strings.TrimPrefix(field, "annotation."))
+		return func(eventInterval Interval) string { return eventInterval.Message.Annotations[key] }, nil
+	default:
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+}
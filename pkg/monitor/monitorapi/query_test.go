@@ -0,0 +1,145 @@
+package monitorapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIntervalQuery(t *testing.T) {
+	start := time.Date(2023, 2, 14, 20, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+	interval := func(name string, source IntervalSource, namespace string, reason IntervalReason, from, to int) Interval {
+		ret := NewInterval(source, Info).
+			Locator(NewLocator().NodeFromName(name)).
+			Message(NewMessage().Reason(reason).HumanMessage(name)).
+			Build(at(from), at(to))
+		if to == 0 {
+			ret.To = time.Time{}
+		}
+		if len(namespace) > 0 {
+			ret.Locator.Keys[LocatorNamespaceKey] = namespace
+		}
+		return ret
+	}
+
+	intervals := Intervals{
+		interval("upgrade", SourceTestData, "", UpgradeStartedReason, 100, 200),
+		interval("short-disruption", SourceDisruption, "openshift-apiserver", "", 150, 152),
+		interval("long-disruption", SourceDisruption, "openshift-apiserver", "", 150, 160),
+		interval("early-disruption", SourceDisruption, "openshift-etcd", "", 10, 20),
+		interval("late-disruption", SourceDisruption, "e2e-test", "", 205, 230),
+		interval("instant", SourceKubeEvent, "openshift-etcd", "", 90, 0),
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			query:    "",
+			expected: []string{"upgrade", "short-disruption", "long-disruption", "early-disruption", "late-disruption", "instant"},
+		},
+		{
+			name:     "equality",
+			query:    "source=Disruption",
+			expected: []string{"short-disruption", "long-disruption", "early-disruption", "late-disruption"},
+		},
+		{
+			name:     "the example",
+			query:    `source=Disruption AND locator.namespace=~"openshift-.*" AND duration>5s AND overlaps(reason=UpgradeStarted)`,
+			expected: []string{"long-disruption"},
+		},
+		{
+			name:     "or and not with precedence",
+			query:    "NOT source=Disruption OR locator.namespace=e2e-test and message!=nothing",
+			expected: []string{"upgrade", "late-disruption", "instant"},
+		},
+		{
+			name:     "parentheses",
+			query:    "(source=KubeEvent OR source=TestData) AND locator.namespace!=openshift-etcd",
+			expected: []string{"upgrade"},
+		},
+		{
+			name:     "duration in seconds",
+			query:    "duration<=2 AND source=Disruption",
+			expected: []string{"short-disruption"},
+		},
+		{
+			name:     "time comparison",
+			query:    "from>=2023-02-14T20:02:30Z",
+			expected: []string{"short-disruption", "long-disruption", "late-disruption"},
+		},
+		{
+			name:     "during",
+			query:    "source=Disruption AND during(reason=UpgradeStarted)",
+			expected: []string{"short-disruption", "long-disruption"},
+		},
+		{
+			name:     "within",
+			query:    "source=Disruption AND within(10s, reason=UpgradeStarted) AND NOT during(reason=UpgradeStarted)",
+			expected: []string{"late-disruption"},
+		},
+		{
+			name:     "instants can overlap",
+			query:    `within(15, message="instant") AND NOT message=instant`,
+			expected: []string{"upgrade"},
+		},
+		{
+			name:     "nested temporal",
+			query:    "overlaps(duration>5 AND overlaps(message=short-disruption))",
+			expected: []string{"upgrade", "short-disruption", "long-disruption"},
+		},
+		{
+			name:    "unknown field",
+			query:   "namespace=foo",
+			wantErr: true,
+		},
+		{
+			name:    "ordering a string",
+			query:   "source>foo",
+			wantErr: true,
+		},
+		{
+			name:    "missing paren",
+			query:   "overlaps(source=foo",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			query:   `message="foo`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing tokens",
+			query:   "source=foo bar",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := CompileIntervalQuery(tt.query, intervals)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for %q", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := []string{}
+			for _, curr := range intervals.Filter(matcher) {
+				actual = append(actual, curr.Message.HumanMessage)
+			}
+			if !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -211,6 +211,10 @@ const (
 	UpgradeFailedReason   IntervalReason = "UpgradeFailed"
 	UpgradeCompleteReason IntervalReason = "UpgradeComplete"
 
+	UpgradePoolsPausedReason   IntervalReason = "UpgradePoolsPaused"
+	UpgradePoolsUnpausedReason IntervalReason = "UpgradePoolsUnpaused"
+	UpgradeHopReason           IntervalReason = "UpgradeHop"
+
 	NodeInstallerReason IntervalReason = "NodeInstaller"
 )
 
@@ -243,6 +247,8 @@ const (
 	AnnotationRoles          AnnotationKey = "roles"
 	AnnotationStatus         AnnotationKey = "status"
 	AnnotationCondition      AnnotationKey = "condition"
+	AnnotationUpgradeHop     AnnotationKey = "hop"
+	AnnotationUpdatedNodes   AnnotationKey = "updated-nodes"
 )
 
 // ConstructionOwner was originally meant to signify that an interval was derived from other intervals.
@@ -297,6 +303,7 @@ const (
 	SourcePathologicalEventMarker IntervalSource = "PathologicalEventMarker" // not sure if this is really helpful since the events all have a different origin
 	SourceClusterOperatorMonitor  IntervalSource = "ClusterOperatorMonitor"
 	SourceOperatorState           IntervalSource = "OperatorState"
+	SourceUpgradeHop              IntervalSource = "UpgradeHop"
 	SourceNodeState                              = "NodeState"
 	SourcePodState                               = "PodState"
 	SourceCloudMetrics                           = "CloudMetrics"
@@ -177,10 +177,12 @@ func (m *recorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
 	m.AddIntervals(intervals...)
 }
 
+// snapshot copies the events so that sorting them cannot move the intervals StartInterval returned the index of
+// while the monitor is still running.
 func (m *recorder) snapshot() monitorapi.Intervals {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	return m.events
+	return append(monitorapi.Intervals(nil), m.events...)
 }
 
 // Intervals returns all events that occur between from and to, including
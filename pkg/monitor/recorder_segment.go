@@ -0,0 +1,580 @@
+package monitor
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// defaultSegmentMaxIntervals is the number of closed intervals held in memory before they are flushed
+	// to a new segment on disk.
+	defaultSegmentMaxIntervals = 50000
+	// defaultSegmentCompactionFanIn is the number of segments of the same level that are merged into a
+	// single segment of the next level.
+	defaultSegmentCompactionFanIn = 8
+)
+
+// SegmentRecorder is a monitorapi.Recorder that keeps intervals in an append-only store of sorted segment
+// files on local disk. Only open intervals (StartInterval without EndInterval) and the most recently
+// recorded intervals are held in memory, so memory use is bounded regardless of how long the monitor runs.
+// Segments are merged in the background to keep the number of files read by Intervals small.
+// Tracked resources are small compared to intervals and are kept in memory.
+type SegmentRecorder struct {
+	dir               string
+	maxIntervals      int
+	compactionFanIn   int
+	resourcesRecorder monitorapi.Recorder
+
+	// lock protects the in memory intervals and the list of segments.
+	lock           sync.Mutex
+	buffer         monitorapi.Intervals
+	open           map[int]monitorapi.Interval
+	nextIntervalID int
+	segments       []*segment
+	nextSegmentID  int
+	compacting     bool
+	closed         bool
+
+	// filesLock is held for reading while segments are read and for writing when segment files are removed.
+	filesLock  sync.RWMutex
+	compactors sync.WaitGroup
+}
+
+var _ monitorapi.Recorder = &SegmentRecorder{}
+
+// segment describes one immutable, sorted file of intervals.
+type segment struct {
+	path  string
+	level int
+	count int
+	// earliestFrom and latestFrom bound the From of every interval in the segment, this is the time index
+	// that allows reads to skip segments that cannot contribute to the requested range.
+	earliestFrom time.Time
+	latestFrom   time.Time
+}
+
+// segmentRecord is the on-disk form of an interval. It keeps full time precision, unlike the metav1.Time
+// based serialization used for artifacts.
+type segmentRecord struct {
+	Level   monitorapi.IntervalLevel  `json:"level"`
+	Source  monitorapi.IntervalSource `json:"source,omitempty"`
+	Display bool                      `json:"display,omitempty"`
+	Locator monitorapi.Locator        `json:"locator"`
+	Message monitorapi.Message        `json:"message"`
+	From    time.Time                 `json:"from"`
+	To      time.Time                 `json:"to"`
+}
+
+// NewSegmentRecorder creates a recorder that stores intervals in a new directory created under parentDir.
+// If parentDir is empty, the default directory for temporary files is used. Close must be called to remove
+// the segment files once the intervals are no longer needed.
+func NewSegmentRecorder(parentDir string) (*SegmentRecorder, error) {
+	return newSegmentRecorder(parentDir, defaultSegmentMaxIntervals, defaultSegmentCompactionFanIn)
+}
+
+func newSegmentRecorder(parentDir string, maxIntervals, compactionFanIn int) (*SegmentRecorder, error) {
+	if len(parentDir) > 0 {
+		if err := os.MkdirAll(parentDir, 0755); err != nil {
+			return nil, fmt.Errorf("could not create interval storage directory: %w", err)
+		}
+	}
+	dir, err := os.MkdirTemp(parentDir, "monitor-intervals-")
+	if err != nil {
+		return nil, fmt.Errorf("could not create interval storage directory: %w", err)
+	}
+	return &SegmentRecorder{
+		dir:               dir,
+		maxIntervals:      maxIntervals,
+		compactionFanIn:   compactionFanIn,
+		resourcesRecorder: NewRecorder(),
+		open:              map[int]monitorapi.Interval{},
+	}, nil
+}
+
+// NewRecorderForStorage returns an in-memory recorder if storageDir is empty and a SegmentRecorder rooted
+// in storageDir otherwise. The returned function releases any storage held by the recorder.
+func NewRecorderForStorage(storageDir string) (monitorapi.Recorder, func(), error) {
+	if len(storageDir) == 0 {
+		return NewRecorder(), func() {}, nil
+	}
+	recorder, err := NewSegmentRecorder(storageDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return recorder, func() {
+		if err := recorder.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "error removing interval storage: %v\n", err)
+		}
+	}, nil
+}
+
+func (m *SegmentRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.resourcesRecorder.CurrentResourceState()
+}
+
+func (m *SegmentRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.resourcesRecorder.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *SegmentRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *SegmentRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *SegmentRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.buffer = append(m.buffer, eventIntervals...)
+	m.flushIfFullLocked()
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+// Open intervals are held in memory until they are ended.
+func (m *SegmentRecorder) StartInterval(interval monitorapi.Interval) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	id := m.nextIntervalID
+	m.nextIntervalID++
+	m.open[id] = interval
+	return id
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from. Once ended, the interval becomes eligible to be written to disk.
+func (m *SegmentRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	interval, ok := m.open[startedInterval]
+	if !ok {
+		return nil
+	}
+	delete(m.open, startedInterval)
+	if interval.From.Before(t) {
+		interval.To = t
+	}
+	m.buffer = append(m.buffer, interval)
+	m.flushIfFullLocked()
+	return &interval
+}
+
+// Intervals returns all events that occur between from and to, including
+// any sampled conditions that were encountered during that period.
+// Intervals are returned in order of their occurrence and with the same bounds as
+// monitorapi.Intervals.Slice. The returned slice is safe to update.
+func (m *SegmentRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	// hold the files for reading before listing segments so that compaction cannot remove them underneath us.
+	m.filesLock.RLock()
+	defer m.filesLock.RUnlock()
+
+	m.lock.Lock()
+	inMemory := make(monitorapi.Intervals, 0, len(m.buffer)+len(m.open))
+	inMemory = append(inMemory, m.buffer...)
+	for _, interval := range m.open {
+		inMemory = append(inMemory, interval)
+	}
+	segments := make([]*segment, 0, len(m.segments))
+	for _, curr := range m.segments {
+		// intervals starting after `to` are never returned, so segments that only hold those are skipped.
+		if !to.IsZero() && curr.earliestFrom.After(to) {
+			continue
+		}
+		segments = append(segments, curr)
+	}
+	m.lock.Unlock()
+	sort.Sort(inMemory)
+
+	iterators := []intervalIterator{&sliceIterator{intervals: inMemory}}
+	for _, curr := range segments {
+		segmentIterator, err := openSegment(curr.path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading interval segment %q: %v\n", curr.path, err)
+			continue
+		}
+		defer segmentIterator.Close()
+		iterators = append(iterators, segmentIterator)
+	}
+	merged, err := newMergeIterator(iterators)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading interval segments: %v\n", err)
+	}
+
+	ret := monitorapi.Intervals{}
+	started := from.IsZero()
+	for {
+		curr, err := merged.next()
+		if err != nil {
+			// the iterator drops the failing segment and continues with the rest.
+			fmt.Fprintf(os.Stderr, "error reading interval segments: %v\n", err)
+		}
+		if curr == nil {
+			break
+		}
+		if !to.IsZero() && curr.From.After(to) {
+			break
+		}
+		if !started {
+			if !startsSlice(*curr, from) {
+				continue
+			}
+			started = true
+		}
+		ret = append(ret, *curr)
+	}
+	return ret
+}
+
+// startsSlice mirrors the check monitorapi.Intervals.Slice uses to find the first interval in range.
+func startsSlice(interval monitorapi.Interval, from time.Time) bool {
+	if interval.To.IsZero() && !interval.From.Before(from) {
+		return true
+	}
+	return !interval.To.Before(from)
+}
+
+// Close waits for any running compaction and removes all segment files. The recorder must not be used afterwards.
+func (m *SegmentRecorder) Close() error {
+	m.lock.Lock()
+	m.closed = true
+	m.lock.Unlock()
+	m.compactors.Wait()
+
+	m.filesLock.Lock()
+	defer m.filesLock.Unlock()
+	return os.RemoveAll(m.dir)
+}
+
+// flushIfFullLocked writes the buffered intervals to a new segment once the buffer is full. Must be called with
+// m.lock held.
+func (m *SegmentRecorder) flushIfFullLocked() {
+	if len(m.buffer) < m.maxIntervals || m.closed {
+		return
+	}
+	sort.Sort(m.buffer)
+	m.nextSegmentID++
+	newSegment, err := writeSegmentFile(m.segmentPath(m.nextSegmentID), 0, &sliceIterator{intervals: m.buffer})
+	if err != nil {
+		// keep the intervals in memory, we will try again on the next write.
+		fmt.Fprintf(os.Stderr, "error writing interval segment, keeping intervals in memory: %v\n", err)
+		return
+	}
+	m.segments = append(m.segments, newSegment)
+	m.buffer = nil
+
+	if !m.compacting && m.compactionCandidatesLocked() != nil {
+		m.compacting = true
+		m.compactors.Add(1)
+		go m.compact()
+	}
+}
+
+func (m *SegmentRecorder) segmentPath(id int) string {
+	return filepath.Join(m.dir, fmt.Sprintf("segment-%08d.jsonl", id))
+}
+
+// writeSegmentFile writes every interval produced by intervals, which must be sorted, to a new segment file.
+// The file only appears at path once it is complete.
+func writeSegmentFile(path string, level int, intervals intervalIterator) (*segment, error) {
+	tmpPath := path + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpPath)
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+
+	ret := &segment{path: path, level: level}
+	for {
+		curr, err := intervals.next()
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if curr == nil {
+			break
+		}
+		if ret.count == 0 || curr.From.Before(ret.earliestFrom) {
+			ret.earliestFrom = curr.From
+		}
+		if ret.count == 0 || curr.From.After(ret.latestFrom) {
+			ret.latestFrom = curr.From
+		}
+		ret.count++
+		if err := encoder.Encode(segmentRecord{
+			Level:   curr.Level,
+			Source:  curr.Source,
+			Display: curr.Display,
+			Locator: curr.Locator,
+			Message: curr.Message,
+			From:    curr.From,
+			To:      curr.To,
+		}); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// compactionCandidatesLocked returns the oldest segments of the lowest level that has enough segments to merge.
+// Must be called with m.lock held.
+func (m *SegmentRecorder) compactionCandidatesLocked() []*segment {
+	byLevel := map[int][]*segment{}
+	levels := []int{}
+	for _, curr := range m.segments {
+		if _, ok := byLevel[curr.level]; !ok {
+			levels = append(levels, curr.level)
+		}
+		byLevel[curr.level] = append(byLevel[curr.level], curr)
+	}
+	sort.Ints(levels)
+	for _, level := range levels {
+		if len(byLevel[level]) >= m.compactionFanIn {
+			return byLevel[level][:m.compactionFanIn]
+		}
+	}
+	return nil
+}
+
+// compact merges segments until no level has enough segments to merge. Only one compaction runs at a time.
+func (m *SegmentRecorder) compact() {
+	defer m.compactors.Done()
+	for {
+		m.lock.Lock()
+		candidates := m.compactionCandidatesLocked()
+		if candidates == nil || m.closed {
+			m.compacting = false
+			m.lock.Unlock()
+			return
+		}
+		m.lock.Unlock()
+
+		if err := m.mergeSegments(candidates); err != nil {
+			fmt.Fprintf(os.Stderr, "error compacting interval segments: %v\n", err)
+			m.lock.Lock()
+			m.compacting = false
+			m.lock.Unlock()
+			return
+		}
+	}
+}
+
+// mergeSegments replaces the candidates with a single segment of the next level.
+func (m *SegmentRecorder) mergeSegments(candidates []*segment) error {
+	iterators := []intervalIterator{}
+	for _, curr := range candidates {
+		segmentIterator, err := openSegment(curr.path)
+		if err != nil {
+			return err
+		}
+		defer segmentIterator.Close()
+		iterators = append(iterators, segmentIterator)
+	}
+	merged, err := newMergeIterator(iterators)
+	if err != nil {
+		return err
+	}
+
+	// the segment ID is shared with flushes, so the file is named while holding the lock.
+	m.lock.Lock()
+	m.nextSegmentID++
+	mergedID := m.nextSegmentID
+	m.lock.Unlock()
+
+	mergedSegment, err := writeSegmentFile(m.segmentPath(mergedID), candidates[0].level+1, merged)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	replaced := map[*segment]bool{}
+	for _, curr := range candidates {
+		replaced[curr] = true
+	}
+	remaining := []*segment{}
+	for _, curr := range m.segments {
+		if !replaced[curr] {
+			remaining = append(remaining, curr)
+		}
+	}
+	m.segments = append(remaining, mergedSegment)
+	m.lock.Unlock()
+
+	// readers that listed the old segments hold filesLock for reading until they are done with them.
+	m.filesLock.Lock()
+	defer m.filesLock.Unlock()
+	var errs []error
+	for _, curr := range candidates {
+		if err := os.Remove(curr.path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// intervalIterator produces intervals in sorted order. next returns nil when there are no more intervals.
+type intervalIterator interface {
+	next() (*monitorapi.Interval, error)
+}
+
+type sliceIterator struct {
+	intervals monitorapi.Intervals
+	index     int
+}
+
+func (s *sliceIterator) next() (*monitorapi.Interval, error) {
+	if s.index >= len(s.intervals) {
+		return nil, nil
+	}
+	ret := s.intervals[s.index]
+	s.index++
+	return &ret, nil
+}
+
+type segmentIterator struct {
+	file    *os.File
+	decoder *json.Decoder
+}
+
+func openSegment(path string) (*segmentIterator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &segmentIterator{
+		file:    file,
+		decoder: json.NewDecoder(bufio.NewReader(file)),
+	}, nil
+}
+
+func (s *segmentIterator) next() (*monitorapi.Interval, error) {
+	record := segmentRecord{}
+	if err := s.decoder.Decode(&record); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level:   record.Level,
+			Locator: record.Locator,
+			Message: record.Message,
+		},
+		Source:  record.Source,
+		Display: record.Display,
+		From:    record.From,
+		To:      record.To,
+	}, nil
+}
+
+func (s *segmentIterator) Close() error {
+	return s.file.Close()
+}
+
+// mergeIterator performs a k-way merge of sorted iterators using monitorapi.Intervals ordering.
+type mergeIterator struct {
+	heads mergeHeap
+}
+
+type mergeHead struct {
+	interval monitorapi.Interval
+	source   intervalIterator
+}
+
+type mergeHeap []mergeHead
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	return monitorapi.Intervals{h[i].interval, h[j].interval}.Less(0, 1)
+}
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x interface{}) {
+	*h = append(*h, x.(mergeHead))
+}
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	ret := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return ret
+}
+
+func newMergeIterator(sources []intervalIterator) (*mergeIterator, error) {
+	ret := &mergeIterator{}
+	var errs []error
+	for _, source := range sources {
+		curr, err := source.next()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if curr == nil {
+			continue
+		}
+		ret.heads = append(ret.heads, mergeHead{interval: *curr, source: source})
+	}
+	heap.Init(&ret.heads)
+	return ret, errors.Join(errs...)
+}
+
+func (m *mergeIterator) next() (*monitorapi.Interval, error) {
+	if len(m.heads) == 0 {
+		return nil, nil
+	}
+	ret := m.heads[0].interval
+	curr, err := m.heads[0].source.next()
+	switch {
+	case err != nil:
+		heap.Pop(&m.heads)
+		return &ret, err
+	case curr == nil:
+		heap.Pop(&m.heads)
+	default:
+		m.heads[0].interval = *curr
+		heap.Fix(&m.heads, 0)
+	}
+	return &ret, nil
+}
@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestSegmentRecorder_MatchesInMemoryRecorder(t *testing.T) {
+	segmentRecorder, err := newSegmentRecorder(t.TempDir(), 7, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer segmentRecorder.Close()
+	memoryRecorder := NewRecorder()
+
+	base := time.Unix(1000, 0).UTC()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		from := base.Add(time.Duration(r.Intn(600)) * time.Second)
+		interval := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.IntervalLevel(r.Intn(3))).
+			Locator(monitorapi.NewLocator().NodeFromName(fmt.Sprintf("node-%d", r.Intn(5)))).
+			Message(monitorapi.NewMessage().HumanMessage(fmt.Sprintf("message %d", i))).
+			Build(from, from.Add(time.Duration(r.Intn(30))*time.Second))
+
+		switch i % 3 {
+		case 0:
+			segmentRecorder.AddIntervals(interval)
+			memoryRecorder.AddIntervals(interval)
+		case 1:
+			segmentRecorder.RecordAt(from, interval.Condition)
+			memoryRecorder.RecordAt(from, interval.Condition)
+		default:
+			segmentID := segmentRecorder.StartInterval(interval)
+			memoryID := memoryRecorder.StartInterval(interval)
+			// leave a few intervals open to make sure they are visible before they end.
+			if i%10 != 2 {
+				segmentEnded := segmentRecorder.EndInterval(segmentID, interval.To.Add(time.Second))
+				memoryEnded := memoryRecorder.EndInterval(memoryID, interval.To.Add(time.Second))
+				if !reflect.DeepEqual(segmentEnded, memoryEnded) {
+					t.Fatalf("unexpected ended interval: %s", diff.ObjectReflectDiff(memoryEnded, segmentEnded))
+				}
+			}
+		}
+	}
+
+	ranges := []struct {
+		from time.Time
+		to   time.Time
+	}{
+		{},
+		{from: base.Add(100 * time.Second)},
+		{to: base.Add(300 * time.Second)},
+		{from: base.Add(200 * time.Second), to: base.Add(250 * time.Second)},
+		{from: base.Add(time.Hour)},
+	}
+	for _, curr := range ranges {
+		t.Run(fmt.Sprintf("%v-%v", curr.from, curr.to), func(t *testing.T) {
+			expected := memoryRecorder.Intervals(curr.from, curr.to)
+			actual := segmentRecorder.Intervals(curr.from, curr.to)
+			if len(expected) == 0 && len(actual) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("%s", diff.ObjectReflectDiff(expected, actual))
+			}
+		})
+	}
+
+	segmentRecorder.compactors.Wait()
+	segmentRecorder.lock.Lock()
+	numSegments := len(segmentRecorder.segments)
+	segmentRecorder.lock.Unlock()
+	if numSegments == 0 || numSegments > 10 {
+		t.Errorf("expected segments to be written and compacted, got %d segments", numSegments)
+	}
+}
+
+func TestSegmentRecorder_CurrentResourceState(t *testing.T) {
+	segmentRecorder, err := NewSegmentRecorder(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	segmentRecorder.RecordResource("pods", &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod", UID: "uid"}})
+
+	resources := segmentRecorder.CurrentResourceState()
+	if _, ok := resources["pods"][monitorapi.InstanceKey{Namespace: "ns", Name: "pod", UID: "uid"}].(*corev1.Pod); !ok {
+		t.Fatalf("missing pod in %v", resources)
+	}
+
+	dir := segmentRecorder.dir
+	if err := segmentRecorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("expected %q to be removed, got %v", dir, err)
+	}
+}
@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// ReplayArtifacts holds the saved output of a previous monitor run that is needed to run the monitor tests again
+// without a cluster.
+type ReplayArtifacts struct {
+	// TimeSuffix is the suffix shared by all the artifacts of the original run, for instance _20230214-203340.
+	TimeSuffix  string
+	Intervals   monitorapi.Intervals
+	Resources   monitorapi.ResourcesMap
+	ClusterData platformidentification.ClusterData
+
+	// Beginning and End bound the saved intervals.
+	Beginning time.Time
+	End       time.Time
+}
+
+// LoadReplayArtifacts reads the e2e-events, resource-* and cluster-data files written by a previous monitor run
+// from artifactDir. If timeSuffix is empty, artifactDir must contain the artifacts of exactly one run.
+func LoadReplayArtifacts(artifactDir, timeSuffix string) (*ReplayArtifacts, error) {
+	if len(timeSuffix) == 0 {
+		eventFiles, err := filepath.Glob(filepath.Join(artifactDir, "e2e-events*.json"))
+		if err != nil {
+			return nil, err
+		}
+		switch len(eventFiles) {
+		case 0:
+			return nil, fmt.Errorf("no e2e-events*.json files found in %q", artifactDir)
+		case 1:
+			timeSuffix = strings.TrimSuffix(strings.TrimPrefix(filepath.Base(eventFiles[0]), "e2e-events"), ".json")
+		default:
+			return nil, fmt.Errorf("found multiple monitor runs in %q, choose one by time suffix: %v", artifactDir, eventFiles)
+		}
+	}
+
+	ret := &ReplayArtifacts{
+		TimeSuffix: timeSuffix,
+		Resources:  monitorapi.ResourcesMap{},
+	}
+
+	intervals, err := monitorserialization.EventsFromFile(filepath.Join(artifactDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read intervals: %w", err)
+	}
+	sort.Sort(intervals)
+	ret.Intervals = intervals
+	for _, interval := range intervals {
+		if !interval.From.IsZero() && (ret.Beginning.IsZero() || interval.From.Before(ret.Beginning)) {
+			ret.Beginning = interval.From
+		}
+		if interval.To.After(ret.End) {
+			ret.End = interval.To
+		}
+		if interval.From.After(ret.End) {
+			ret.End = interval.From
+		}
+	}
+
+	resourceFiles, err := filepath.Glob(filepath.Join(artifactDir, fmt.Sprintf("resource-*%s.zip", timeSuffix)))
+	if err != nil {
+		return nil, err
+	}
+	for _, resourceFile := range resourceFiles {
+		resourceType := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(resourceFile), "resource-"), timeSuffix+".zip")
+		instances, err := monitorserialization.InstanceMapFromFile(resourceFile, resourceType)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", resourceType, err)
+		}
+		ret.Resources[resourceType] = instances
+	}
+
+	clusterDataBytes, err := os.ReadFile(filepath.Join(artifactDir, fmt.Sprintf("cluster-data%s.json", timeSuffix)))
+	switch {
+	case os.IsNotExist(err):
+		fmt.Fprintf(os.Stderr, "No cluster data found in %q, monitor tests depending on the job type may fail\n", artifactDir)
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(clusterDataBytes, &ret.ClusterData); err != nil {
+			return nil, fmt.Errorf("unable to read cluster data: %w", err)
+		}
+	}
+
+	return ret, nil
+}
+
+// NewReplayMonitor creates a monitor that runs the monitor tests against artifacts saved by a previous run instead of
+// a live cluster. StartCollection and CollectData are replaced by PrepareForReplay, all other stages run as usual and
+// write their results to storageDir.  The monitor tests implementing NonReplayableMonitorTest are skipped.
+func NewReplayMonitor(
+	artifacts *ReplayArtifacts,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry) Interface {
+	// bypass the RecordResource bookkeeping so the saved update and recreation counts are preserved.
+	recorder := &recorder{
+		events:            append(monitorapi.Intervals{}, artifacts.Intervals...),
+		recordedResources: artifacts.Resources,
+	}
+	return &Monitor{
+		recorder:            recorder,
+		monitorTestRegistry: monitorTestRegistry,
+		storageDir:          storageDir,
+		replay:              artifacts,
+	}
+}
+
+// withoutReplayedIntervals removes computed intervals that are already present in the replayed artifacts.  Saved
+// artifacts include the intervals computed by the original run, recomputing them would double count them.
+func withoutReplayedIntervals(computed, replayed monitorapi.Intervals) monitorapi.Intervals {
+	existing := map[string]int{}
+	for _, interval := range replayed {
+		existing[replayKey(interval)]++
+	}
+	ret := monitorapi.Intervals{}
+	for _, interval := range computed {
+		key := replayKey(interval)
+		if existing[key] > 0 {
+			existing[key]--
+			continue
+		}
+		ret = append(ret, interval)
+	}
+	return ret
+}
+
+// replayKey uses the serialized form because the saved artifacts lose precision compared to the computed intervals.
+func replayKey(interval monitorapi.Interval) string {
+	key, err := monitorserialization.IntervalToOneLineJSON(interval)
+	if err != nil {
+		return interval.String()
+	}
+	return string(key)
+}
@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLoadReplayArtifacts(t *testing.T) {
+	artifactDir := t.TempDir()
+	timeSuffix := "_20230214-203340"
+
+	from := time.Unix(1000, 0).UTC()
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+			Message(monitorapi.NewMessage().HumanMessage("second")).
+			Build(from.Add(time.Minute), from.Add(2*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Error).
+			Locator(monitorapi.NewLocator().NodeFromName("node-b")).
+			Message(monitorapi.NewMessage().HumanMessage("first")).
+			Build(from, from.Add(time.Second)),
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(artifactDir, "e2e-events"+timeSuffix+".json"), intervals); err != nil {
+		t.Fatal(err)
+	}
+
+	pods := monitorapi.InstanceMap{
+		{Namespace: "ns", Name: "pod", UID: "uid"}: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod", UID: "uid"}},
+	}
+	if err := monitorserialization.InstanceMapToFile(filepath.Join(artifactDir, "resource-pods"+timeSuffix+".zip"), "pods", pods); err != nil {
+		t.Fatal(err)
+	}
+
+	clusterData := platformidentification.ClusterData{JobType: platformidentification.JobType{Platform: "aws", Topology: "ha"}}
+	clusterDataBytes, err := json.Marshal(clusterData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(artifactDir, "cluster-data"+timeSuffix+".json"), clusterDataBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	artifacts, err := LoadReplayArtifacts(artifactDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if artifacts.TimeSuffix != timeSuffix {
+		t.Errorf("expected time suffix %q, got %q", timeSuffix, artifacts.TimeSuffix)
+	}
+	if len(artifacts.Intervals) != 2 || artifacts.Intervals[0].Message.HumanMessage != "first" {
+		t.Errorf("expected two sorted intervals, got %v", artifacts.Intervals.Strings())
+	}
+	if !artifacts.Beginning.Equal(from) || !artifacts.End.Equal(from.Add(2*time.Minute)) {
+		t.Errorf("unexpected bounds %v - %v", artifacts.Beginning, artifacts.End)
+	}
+	if _, ok := artifacts.Resources["pods"][monitorapi.InstanceKey{Namespace: "ns", Name: "pod", UID: "uid"}].(*corev1.Pod); !ok {
+		t.Errorf("expected a typed pod, got %v", artifacts.Resources)
+	}
+	if artifacts.ClusterData.Platform != "aws" || artifacts.ClusterData.Topology != "ha" {
+		t.Errorf("unexpected cluster data %#v", artifacts.ClusterData)
+	}
+}
+
+func TestWithoutReplayedIntervals(t *testing.T) {
+	from := time.Unix(1000, 0).UTC()
+	saved := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).HumanMessage("not ready")).
+		Build(from, from.Add(time.Minute))
+	// recomputed intervals may have more precision than the saved ones.
+	recomputed := saved
+	recomputed.To = recomputed.To.Add(200 * time.Millisecond)
+	added := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName("node-b")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).HumanMessage("not ready")).
+		Build(from, from.Add(time.Minute))
+
+	actual := withoutReplayedIntervals(monitorapi.Intervals{recomputed, added}, monitorapi.Intervals{saved})
+	if len(actual) != 1 || actual[0].Locator.Keys[monitorapi.LocatorNodeKey] != "node-b" {
+		t.Errorf("expected only the new interval, got %v", actual.Strings())
+	}
+}
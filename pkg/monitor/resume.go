@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// NewResumedMonitor creates a monitor continuing the monitor of an interrupted run, whose intervals are given.  The
+// monitor tests evaluate the intervals of both runs, the intervals computed by the interrupted run are not computed
+// twice.
+func NewResumedMonitor(
+	recorder monitorapi.Recorder,
+	adminKubeConfig *rest.Config,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	options Options,
+	resumedIntervals monitorapi.Intervals) Interface {
+	recorder.AddIntervals(resumedIntervals...)
+	return &Monitor{
+		adminKubeConfig:     adminKubeConfig,
+		recorder:            recorder,
+		monitorTestRegistry: monitorTestRegistry,
+		storageDir:          storageDir,
+		options:             options,
+		resumedIntervals:    resumedIntervals,
+	}
+}
+
+// LoadInterruptedRunIntervals reads the intervals of the latest e2e-events file of artifactDir whose intervals span
+// the given time, for instance the start of a test of the interrupted run.  A run interrupted by a signal still
+// writes its intervals, a killed run does not and nil is returned.
+func LoadInterruptedRunIntervals(artifactDir string, spanned time.Time) (monitorapi.Intervals, error) {
+	eventFiles, err := filepath.Glob(filepath.Join(artifactDir, "e2e-events_*.json"))
+	if err != nil {
+		return nil, err
+	}
+	// the time suffixes sort chronologically, a resumed run includes the intervals of the run it resumed.
+	sort.Sort(sort.Reverse(sort.StringSlice(eventFiles)))
+	for _, eventFile := range eventFiles {
+		intervals, err := monitorserialization.EventsFromFile(eventFile)
+		if err != nil {
+			return nil, err
+		}
+		var earliest, latest time.Time
+		for _, interval := range intervals {
+			if !interval.From.IsZero() && (earliest.IsZero() || interval.From.Before(earliest)) {
+				earliest = interval.From
+			}
+			if interval.To.After(latest) {
+				latest = interval.To
+			}
+			if interval.From.After(latest) {
+				latest = interval.From
+			}
+		}
+		if !spanned.Before(earliest) && !spanned.After(latest) {
+			sort.Sort(intervals)
+			return intervals, nil
+		}
+	}
+	return nil, nil
+}
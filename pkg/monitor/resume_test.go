@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func TestLoadInterruptedRunIntervals(t *testing.T) {
+	artifactDir := t.TempDir()
+	from := time.Unix(1000, 0).UTC()
+	intervalsFrom := func(message string, start, end time.Time) monitorapi.Intervals {
+		return monitorapi.Intervals{
+			monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+				Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+				Message(monitorapi.NewMessage().HumanMessage(message)).
+				Build(start, end),
+		}
+	}
+	for suffix, intervals := range map[string]monitorapi.Intervals{
+		// a completed run, before the interrupted one.
+		"_20230214-100000": intervalsFrom("completed", from.Add(-2*time.Hour), from.Add(-time.Hour)),
+		// the interrupted run, and its resumed run which includes its intervals.
+		"_20230214-110000": intervalsFrom("interrupted", from, from.Add(time.Hour)),
+		"_20230214-120000": intervalsFrom("resumed", from, from.Add(2*time.Hour)),
+	} {
+		if err := monitorserialization.EventsToFile(filepath.Join(artifactDir, "e2e-events"+suffix+".json"), intervals); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	intervals, err := LoadInterruptedRunIntervals(artifactDir, from.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 1 || intervals[0].Message.HumanMessage != "resumed" {
+		t.Errorf("expected the intervals of the latest run spanning the test, got %v", intervals.Strings())
+	}
+
+	intervals, err = LoadInterruptedRunIntervals(artifactDir, from.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 0 {
+		t.Errorf("expected no intervals for a run that wrote none, got %v", intervals.Strings())
+	}
+}
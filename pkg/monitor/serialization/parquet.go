@@ -0,0 +1,578 @@
+package monitorserialization
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// The parquet files carry their schema name and version in the file metadata under this key.  Bump the version
+// when columns are removed or change type, adding columns is compatible.
+const parquetSchemaKey = "openshift-tests.schema"
+
+const (
+	intervalsParquetSchema  = "intervals/v1"
+	disruptionParquetSchema = "disruption/v1"
+	junitParquetSchema      = "junit/v1"
+	resourcesParquetSchema  = "resources/v1"
+)
+
+// parquetRowGroupSize bounds how many rows the writers buffer before flushing them to the file.
+const parquetRowGroupSize = 10000
+
+// Times are stored with microsecond precision and a zero time is stored as null.
+var (
+	parquetTimestamp         = parquet.Timestamp(parquet.Microsecond)
+	parquetOptionalTimestamp = parquet.Optional(parquet.Timestamp(parquet.Microsecond))
+	parquetOptionalString    = parquet.Optional(parquet.String())
+)
+
+// intervalLocatorKeys and intervalAnnotationKeys are flattened into one nullable column each, named
+// locator_<key> and annotation_<key> with the dashes replaced by underscores.  Keys that are not listed here are
+// kept as a JSON object in the locator_other and annotation_other columns, add them here to give them a column.
+var (
+	intervalLocatorKeys = []monitorapi.LocatorKey{
+		monitorapi.LocatorClusterOperatorKey,
+		monitorapi.LocatorClusterVersionKey,
+		monitorapi.LocatorNamespaceKey,
+		monitorapi.LocatorDeploymentKey,
+		monitorapi.LocatorNodeKey,
+		monitorapi.LocatorEtcdMemberKey,
+		monitorapi.LocatorNameKey,
+		monitorapi.LocatorHmsgKey,
+		monitorapi.LocatorInstanceKey,
+		monitorapi.LocatorPodKey,
+		monitorapi.LocatorUIDKey,
+		monitorapi.LocatorMirrorUIDKey,
+		monitorapi.LocatorMetricsPathKey,
+		monitorapi.LocatorServiceKey,
+		monitorapi.LocatorContainerKey,
+		monitorapi.LocatorAlertKey,
+		monitorapi.LocatorRouteKey,
+		monitorapi.LocatorBackendDisruptionNameKey,
+		monitorapi.LocatorDisruptionKey,
+		monitorapi.LocatorE2ETestKey,
+		monitorapi.LocatorLoadBalancerKey,
+		monitorapi.LocatorConnectionKey,
+		monitorapi.LocatorProtocolKey,
+		monitorapi.LocatorTargetKey,
+		monitorapi.LocatorRowKey,
+		monitorapi.LocatorServerKey,
+		monitorapi.LocatorMetricKey,
+	}
+	intervalAnnotationKeys = []monitorapi.AnnotationKey{
+		monitorapi.AnnotationAlertState,
+		monitorapi.AnnotationState,
+		monitorapi.AnnotationSeverity,
+		monitorapi.AnnotationReason,
+		monitorapi.AnnotationContainerExitCode,
+		monitorapi.AnnotationCause,
+		monitorapi.AnnotationConfig,
+		monitorapi.AnnotationContainer,
+		monitorapi.AnnotationImage,
+		monitorapi.AnnotationInteresting,
+		monitorapi.AnnotationCount,
+		monitorapi.AnnotationNode,
+		monitorapi.AnnotationEtcdLocalMember,
+		monitorapi.AnnotationEtcdTerm,
+		monitorapi.AnnotationEtcdLeader,
+		monitorapi.AnnotationPreviousEtcdLeader,
+		monitorapi.AnnotationPathological,
+		monitorapi.AnnotationConstructed,
+		monitorapi.AnnotationPhase,
+		monitorapi.AnnotationIsStaticPod,
+		monitorapi.AnnotationDuration,
+		monitorapi.AnnotationRequestAuditID,
+		monitorapi.AnnotationRoles,
+		monitorapi.AnnotationStatus,
+		monitorapi.AnnotationCondition,
+		monitorapi.AnnotationUpgradeHop,
+		monitorapi.AnnotationUpdatedNodes,
+	}
+)
+
+const (
+	locatorColumnPrefix    = "locator_"
+	annotationColumnPrefix = "annotation_"
+	otherColumnSuffix      = "other"
+)
+
+var intervalsSchema = parquet.NewSchema("interval", intervalsGroup())
+
+func intervalsGroup() parquet.Group {
+	group := parquet.Group{
+		"from":         parquetTimestamp,
+		"to":           parquetOptionalTimestamp,
+		"level":        parquet.String(),
+		"source":       parquet.String(),
+		"display":      parquet.Leaf(parquet.BooleanType),
+		"locator_type": parquet.String(),
+		"reason":       parquet.String(),
+		"cause":        parquet.String(),
+		"message":      parquet.String(),
+
+		locatorColumnPrefix + otherColumnSuffix:    parquetOptionalString,
+		annotationColumnPrefix + otherColumnSuffix: parquetOptionalString,
+	}
+	for _, key := range intervalLocatorKeys {
+		group[parquetColumnName(locatorColumnPrefix, string(key))] = parquetOptionalString
+	}
+	for _, key := range intervalAnnotationKeys {
+		group[parquetColumnName(annotationColumnPrefix, string(key))] = parquetOptionalString
+	}
+	return group
+}
+
+func parquetColumnName(prefix, key string) string {
+	return prefix + strings.ReplaceAll(key, "-", "_")
+}
+
+// IntervalsToParquet writes intervals to filename as parquet, sorted like the e2e-events json.  The known locator
+// keys and annotations are flattened into columns, so the schema is the same for every run.
+func IntervalsToParquet(filename string, intervals monitorapi.Intervals) error {
+	sorted := append(monitorapi.Intervals{}, intervals...)
+	sort.Stable(sorted)
+	return writeParquetFile(filename, intervalsSchema, intervalsParquetSchema, func(w *parquet.GenericWriter[map[string]any]) error {
+		for _, interval := range sorted {
+			row := map[string]any{
+				"from":         interval.From,
+				"level":        interval.Level.String(),
+				"source":       string(interval.Source),
+				"display":      interval.Display,
+				"locator_type": string(interval.Locator.Type),
+				"reason":       string(interval.Message.Reason),
+				"cause":        interval.Message.Cause,
+				"message":      interval.Message.HumanMessage,
+			}
+			if !interval.To.IsZero() {
+				row["to"] = interval.To
+			}
+
+			otherLocatorKeys := map[monitorapi.LocatorKey]string{}
+			for key, value := range interval.Locator.Keys {
+				otherLocatorKeys[key] = value
+			}
+			for _, key := range intervalLocatorKeys {
+				if value, ok := otherLocatorKeys[key]; ok {
+					row[parquetColumnName(locatorColumnPrefix, string(key))] = value
+					delete(otherLocatorKeys, key)
+				}
+			}
+			if err := setOtherColumn(row, locatorColumnPrefix, otherLocatorKeys); err != nil {
+				return err
+			}
+
+			otherAnnotations := map[monitorapi.AnnotationKey]string{}
+			for key, value := range interval.Message.Annotations {
+				otherAnnotations[key] = value
+			}
+			for _, key := range intervalAnnotationKeys {
+				if value, ok := otherAnnotations[key]; ok {
+					row[parquetColumnName(annotationColumnPrefix, string(key))] = value
+					delete(otherAnnotations, key)
+				}
+			}
+			if err := setOtherColumn(row, annotationColumnPrefix, otherAnnotations); err != nil {
+				return err
+			}
+
+			if _, err := w.Write([]map[string]any{row}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// setOtherColumn stores the keys without a column of their own as a JSON object.
+func setOtherColumn[K ~string](row map[string]any, prefix string, others map[K]string) error {
+	if len(others) == 0 {
+		return nil
+	}
+	othersJSON, err := json.Marshal(others)
+	if err != nil {
+		return err
+	}
+	row[prefix+otherColumnSuffix] = string(othersJSON)
+	return nil
+}
+
+// IntervalsFromParquet reads intervals written by IntervalsToParquet.
+func IntervalsFromParquet(filename string) (monitorapi.Intervals, error) {
+	rows, err := readParquetRows(filename, intervalsParquetSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(monitorapi.Intervals, 0, len(rows))
+	for _, row := range rows {
+		level, err := monitorapi.ConditionLevelFromString(parquetString(row["level"]))
+		if err != nil {
+			return nil, err
+		}
+		interval := monitorapi.Interval{
+			Condition: monitorapi.Condition{
+				Level: level,
+				Locator: monitorapi.Locator{
+					Type: monitorapi.LocatorType(parquetString(row["locator_type"])),
+				},
+				Message: monitorapi.Message{
+					Reason:       monitorapi.IntervalReason(parquetString(row["reason"])),
+					Cause:        parquetString(row["cause"]),
+					HumanMessage: parquetString(row["message"]),
+				},
+			},
+			Source:  monitorapi.IntervalSource(parquetString(row["source"])),
+			Display: row["display"] == true,
+			From:    parquetTime(row["from"]),
+			To:      parquetTime(row["to"]),
+		}
+
+		locatorKeys := map[monitorapi.LocatorKey]string{}
+		if err := getOtherColumn(row, locatorColumnPrefix, &locatorKeys); err != nil {
+			return nil, err
+		}
+		for _, key := range intervalLocatorKeys {
+			if value, ok := row[parquetColumnName(locatorColumnPrefix, string(key))].(string); ok {
+				locatorKeys[key] = value
+			}
+		}
+		if len(locatorKeys) > 0 {
+			interval.Locator.Keys = locatorKeys
+		}
+
+		annotations := map[monitorapi.AnnotationKey]string{}
+		if err := getOtherColumn(row, annotationColumnPrefix, &annotations); err != nil {
+			return nil, err
+		}
+		for _, key := range intervalAnnotationKeys {
+			if value, ok := row[parquetColumnName(annotationColumnPrefix, string(key))].(string); ok {
+				annotations[key] = value
+			}
+		}
+		if len(annotations) > 0 {
+			interval.Message.Annotations = annotations
+		}
+
+		ret = append(ret, interval)
+	}
+	return ret, nil
+}
+
+func getOtherColumn[K ~string](row map[string]any, prefix string, into *map[K]string) error {
+	othersJSON, ok := row[prefix+otherColumnSuffix].(string)
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(othersJSON), into); err != nil {
+		return fmt.Errorf("invalid %s%s column: %w", prefix, otherColumnSuffix, err)
+	}
+	return nil
+}
+
+func parquetString(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+// parquetTime converts a timestamp column read into a map, which holds the microseconds since the epoch.
+func parquetTime(value any) time.Time {
+	micros, ok := value.(int64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.UnixMicro(micros).UTC()
+}
+
+type disruptionRow struct {
+	Backend         string     `parquet:"backend"`
+	Connection      string     `parquet:"connection"`
+	Disrupted       bool       `parquet:"disrupted"`
+	From            time.Time  `parquet:"from"`
+	To              *time.Time `parquet:"to"`
+	DurationSeconds float64    `parquet:"duration_seconds"`
+	Message         string     `parquet:"message"`
+}
+
+var disruptionSchema = parquet.NewSchema("disruption", parquet.Group{
+	"backend":          parquet.String(),
+	"connection":       parquet.String(),
+	"disrupted":        parquet.Leaf(parquet.BooleanType),
+	"from":             parquetTimestamp,
+	"to":               parquetOptionalTimestamp,
+	"duration_seconds": parquet.Leaf(parquet.DoubleType),
+	"message":          parquet.String(),
+})
+
+// DisruptionToParquet writes one row per disruption interval, the samples the disruption backends reported.  It is
+// a narrower view of IntervalsToParquet that is easier to aggregate.
+func DisruptionToParquet(filename string, intervals monitorapi.Intervals) error {
+	return writeParquetFile(filename, disruptionSchema, disruptionParquetSchema, func(w *parquet.GenericWriter[disruptionRow]) error {
+		for _, interval := range intervals {
+			if interval.Source != monitorapi.SourceDisruption {
+				continue
+			}
+			duration := 0.0
+			if !interval.To.IsZero() {
+				duration = interval.To.Sub(interval.From).Seconds()
+			}
+			row := disruptionRow{
+				Backend:         interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey],
+				Connection:      interval.Locator.Keys[monitorapi.LocatorConnectionKey],
+				Disrupted:       interval.Level != monitorapi.Info,
+				From:            interval.From,
+				To:              optionalTime(interval.To),
+				DurationSeconds: duration,
+				Message:         interval.Message.HumanMessage,
+			}
+			if _, err := w.Write([]disruptionRow{row}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type junitRow struct {
+	Name            string  `parquet:"name"`
+	Classname       string  `parquet:"classname"`
+	DurationSeconds float64 `parquet:"duration_seconds"`
+	// Status is one of passed, failed or skipped.
+	Status         string  `parquet:"status"`
+	SkipMessage    *string `parquet:"skip_message"`
+	FailureMessage *string `parquet:"failure_message"`
+	FailureOutput  *string `parquet:"failure_output"`
+	SystemOut      string  `parquet:"system_out"`
+	SystemErr      string  `parquet:"system_err"`
+}
+
+var junitSchema = parquet.NewSchema("junit", parquet.Group{
+	"name":             parquet.String(),
+	"classname":        parquet.String(),
+	"duration_seconds": parquet.Leaf(parquet.DoubleType),
+	"status":           parquet.String(),
+	"skip_message":     parquet.Optional(parquet.String()),
+	"failure_message":  parquet.Optional(parquet.String()),
+	"failure_output":   parquet.Optional(parquet.String()),
+	"system_out":       parquet.String(),
+	"system_err":       parquet.String(),
+})
+
+// JUnitsToParquet writes junits to filename as parquet.  status is one of passed, failed or skipped.
+func JUnitsToParquet(filename string, junits []*junitapi.JUnitTestCase) error {
+	return writeParquetFile(filename, junitSchema, junitParquetSchema, func(w *parquet.GenericWriter[junitRow]) error {
+		for _, junit := range junits {
+			row := junitRow{
+				Name:            junit.Name,
+				Classname:       junit.Classname,
+				DurationSeconds: junit.Duration,
+				Status:          "passed",
+				SystemOut:       junit.SystemOut,
+				SystemErr:       junit.SystemErr,
+			}
+			if junit.SkipMessage != nil {
+				row.Status = "skipped"
+				row.SkipMessage = &junit.SkipMessage.Message
+			}
+			if junit.FailureOutput != nil {
+				row.Status = "failed"
+				row.FailureMessage = &junit.FailureOutput.Message
+				row.FailureOutput = &junit.FailureOutput.Output
+			}
+			if _, err := w.Write([]junitRow{row}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// JUnitsFromParquet reads junits written by JUnitsToParquet.
+func JUnitsFromParquet(filename string) ([]*junitapi.JUnitTestCase, error) {
+	rows, err := readParquetFile[junitRow](filename, junitParquetSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, row := range rows {
+		junit := &junitapi.JUnitTestCase{
+			Name:      row.Name,
+			Classname: row.Classname,
+			Duration:  row.DurationSeconds,
+			SystemOut: row.SystemOut,
+			SystemErr: row.SystemErr,
+		}
+		if row.SkipMessage != nil {
+			junit.SkipMessage = &junitapi.SkipMessage{Message: *row.SkipMessage}
+		}
+		if row.FailureMessage != nil || row.FailureOutput != nil {
+			junit.FailureOutput = &junitapi.FailureOutput{}
+			if row.FailureMessage != nil {
+				junit.FailureOutput.Message = *row.FailureMessage
+			}
+			if row.FailureOutput != nil {
+				junit.FailureOutput.Output = *row.FailureOutput
+			}
+		}
+		ret = append(ret, junit)
+	}
+	return ret, nil
+}
+
+type resourceRow struct {
+	ResourceType string `parquet:"resource_type"`
+	Namespace    string `parquet:"namespace"`
+	Name         string `parquet:"name"`
+	UID          string `parquet:"uid"`
+	// Object is the JSON serialized object.
+	Object string `parquet:"object"`
+}
+
+var resourcesSchema = parquet.NewSchema("resource", parquet.Group{
+	"resource_type": parquet.String(),
+	"namespace":     parquet.String(),
+	"name":          parquet.String(),
+	"uid":           parquet.String(),
+	"object":        parquet.String(),
+})
+
+// ResourcesToParquet writes the tracked resources to filename as parquet, with one row per instance.  The object
+// column holds the JSON serialized object.
+func ResourcesToParquet(filename string, resources monitorapi.ResourcesMap) error {
+	return writeParquetFile(filename, resourcesSchema, resourcesParquetSchema, func(w *parquet.GenericWriter[resourceRow]) error {
+		for _, resourceType := range sets.StringKeySet(resources).List() {
+			instances := resources[resourceType]
+			keys := []monitorapi.InstanceKey{}
+			for key := range instances {
+				keys = append(keys, key)
+			}
+			sort.Slice(keys, func(i, j int) bool {
+				if keys[i].Namespace != keys[j].Namespace {
+					return keys[i].Namespace < keys[j].Namespace
+				}
+				if keys[i].Name != keys[j].Name {
+					return keys[i].Name < keys[j].Name
+				}
+				return keys[i].UID < keys[j].UID
+			})
+			for _, key := range keys {
+				obj := instances[key]
+				objJSON, err := json.Marshal(obj)
+				if err != nil {
+					return fmt.Errorf("unable to serialize %s %s/%s: %w", resourceType, key.Namespace, key.Name, err)
+				}
+				row := resourceRow{ResourceType: resourceType, Namespace: key.Namespace, Name: key.Name, UID: key.UID, Object: string(objJSON)}
+				if metadata, err := meta.Accessor(obj); err == nil {
+					row.Namespace, row.Name = metadata.GetNamespace(), metadata.GetName()
+				}
+				if _, err := w.Write([]resourceRow{row}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// writeParquetFile streams the rows written by write to filename, flushing them every parquetRowGroupSize rows.
+func writeParquetFile[T any](filename string, schema *parquet.Schema, schemaName string, write func(w *parquet.GenericWriter[T]) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := parquet.NewGenericWriter[T](file,
+		schema,
+		parquet.Compression(&parquet.Snappy),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		parquet.KeyValueMetadata(parquetSchemaKey, schemaName),
+	)
+	if err := write(w); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return file.Close()
+}
+
+// readParquetFile reads all the rows of filename, after checking that it holds schemaName.
+func readParquetFile[T any](filename string, schemaName string) ([]T, error) {
+	var ret []T
+	err := openParquetFile(filename, schemaName, func(parquetFile *parquet.File) error {
+		r := parquet.NewGenericReader[T](parquetFile)
+		defer r.Close()
+		ret = make([]T, parquetFile.NumRows())
+		n, err := r.Read(ret)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		ret = ret[:n]
+		return nil
+	})
+	return ret, err
+}
+
+// readParquetRows reads all the rows of filename into maps keyed by column name, after checking that it holds
+// schemaName.  Timestamps are read as the microseconds since the epoch.
+func readParquetRows(filename string, schemaName string) ([]map[string]any, error) {
+	var ret []map[string]any
+	err := openParquetFile(filename, schemaName, func(parquetFile *parquet.File) error {
+		r := parquet.NewGenericReader[map[string]any](parquetFile, parquetFile.Schema())
+		defer r.Close()
+		ret = make([]map[string]any, parquetFile.NumRows())
+		for i := range ret {
+			ret[i] = map[string]any{}
+		}
+		n, err := r.Read(ret)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		ret = ret[:n]
+		return nil
+	})
+	return ret, err
+}
+
+func openParquetFile(filename string, schemaName string, read func(parquetFile *parquet.File) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	parquetFile, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	if actual, _ := parquetFile.Lookup(parquetSchemaKey); actual != schemaName {
+		return fmt.Errorf("%s: expected schema %q, got %q", filename, schemaName, actual)
+	}
+	if err := read(parquetFile); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
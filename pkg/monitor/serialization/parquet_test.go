@@ -0,0 +1,110 @@
+package monitorserialization
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestIntervalsParquetRoundTrip(t *testing.T) {
+	from := time.Date(2023, 2, 14, 20, 33, 40, 123456000, time.UTC)
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().Disruption("kube-api-new-connections", "", "", "", "", monitorapi.NewConnectionType)).
+			Message(monitorapi.NewMessage().Reason("DisruptionBegan").HumanMessage("stopped responding to GET requests")).
+			Display().
+			Build(from, from.Add(3*time.Second)),
+		monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).Cause("kubelet").
+				WithAnnotation(monitorapi.AnnotationCount, "3").WithAnnotation("not-flattened", "kept").HumanMessage("quoted \"message\"\nwith newline")).
+			Build(from.Add(-time.Minute), time.Time{}),
+	}
+
+	filename := filepath.Join(t.TempDir(), "e2e-events.parquet")
+	if err := IntervalsToParquet(filename, intervals); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := IntervalsFromParquet(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := monitorapi.Intervals{intervals[1], intervals[0]}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s", diff.ObjectReflectDiff(expected, actual))
+	}
+
+	if _, err := JUnitsFromParquet(filename); err == nil {
+		t.Errorf("expected reading intervals as junits to fail")
+	}
+}
+
+func TestJUnitsParquetRoundTrip(t *testing.T) {
+	junits := []*junitapi.JUnitTestCase{
+		{Name: "passed", Duration: 1.5, SystemOut: "out"},
+		{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "not supported"}},
+		{Name: "failed", Classname: "monitor", FailureOutput: &junitapi.FailureOutput{Message: "failed", Output: "details"}, SystemErr: "err"},
+	}
+
+	filename := filepath.Join(t.TempDir(), "junit.parquet")
+	if err := JUnitsToParquet(filename, junits); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := JUnitsFromParquet(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(junits, actual) {
+		t.Errorf("%s", diff.ObjectReflectDiff(junits, actual))
+	}
+}
+
+func TestIntervalsParquetSchemaIsFixed(t *testing.T) {
+	from := time.Date(2023, 2, 14, 20, 33, 40, 0, time.UTC)
+	schemaOf := func(interval monitorapi.Interval) string {
+		filename := filepath.Join(t.TempDir(), "e2e-events.parquet")
+		if err := IntervalsToParquet(filename, monitorapi.Intervals{interval}); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parquetFile, err := parquet.OpenFile(file, info.Size())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parquetFile.Schema().String()
+	}
+
+	nodeSchema := schemaOf(monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).HumanMessage("not ready")).
+		Build(from, from))
+	podSchema := schemaOf(monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().PodFromNames("ns", "pod", "uid")).
+		Message(monitorapi.NewMessage().Reason("Created").WithAnnotation(monitorapi.AnnotationContainer, "c").HumanMessage("created")).
+		Build(from, from))
+	if nodeSchema != podSchema {
+		t.Errorf("expected the same schema for all intervals, got\n%s\nand\n%s", nodeSchema, podSchema)
+	}
+	for _, column := range []string{"locator_node", "locator_backend_disruption_name", "annotation_count", "annotation_other"} {
+		if !strings.Contains(nodeSchema, " "+column+" (STRING);") {
+			t.Errorf("expected a %s column, got\n%s", column, nodeSchema)
+		}
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -73,3 +75,60 @@ func InstanceMapToFile(filename string, resourceType string, instances monitorap
 
 	return ioutil.WriteFile(filename, byteBuffer.Bytes(), 0644)
 }
+
+// typedResources maps the resource types tracked by the monitor to the typed objects the monitor tests expect.
+// Resource types that are not listed here are read as unstructured objects.
+var typedResources = map[string]func() runtime.Object{
+	"events": func() runtime.Object { return &corev1.Event{} },
+	"pods":   func() runtime.Object { return &corev1.Pod{} },
+}
+
+// InstanceMapFromFile reads a file written by InstanceMapToFile.
+func InstanceMapFromFile(filename string, resourceType string) (monitorapi.InstanceMap, error) {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	instances := monitorapi.InstanceMap{}
+	for _, zipFile := range zipReader.File {
+		if filepath.Base(zipFile.Name) != resourceType+".json" {
+			continue
+		}
+		nsReader, err := zipFile.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(nsReader)
+		nsReader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		// the written lists have no kind, so they cannot be read back as an UnstructuredList.
+		nsList := struct {
+			Items []map[string]interface{} `json:"items"`
+		}{}
+		if err := json.Unmarshal(data, &nsList); err != nil {
+			return nil, fmt.Errorf("unable to read %q from %q: %w", zipFile.Name, filename, err)
+		}
+		for i := range nsList.Items {
+			item := &unstructured.Unstructured{Object: nsList.Items[i]}
+			var obj runtime.Object = item
+			if newObj, ok := typedResources[resourceType]; ok {
+				typedObj := newObj()
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, typedObj); err != nil {
+					return nil, fmt.Errorf("unable to convert %s/%s from %q: %w", item.GetNamespace(), item.GetName(), filename, err)
+				}
+				obj = typedObj
+			}
+			instances[monitorapi.InstanceKey{
+				Namespace: item.GetNamespace(),
+				Name:      item.GetName(),
+				UID:       fmt.Sprintf("%v", item.GetUID()),
+			}] = obj
+		}
+	}
+	return instances, nil
+}
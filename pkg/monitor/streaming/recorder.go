@@ -0,0 +1,133 @@
+package streaming
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// EventType describes what happened to the interval of an Event.
+type EventType string
+
+const (
+	// IntervalAdded is sent for intervals that are recorded complete, through AddIntervals, Record or RecordAt.
+	IntervalAdded EventType = "added"
+	// IntervalStarted is sent by StartInterval, the interval has no To yet.
+	IntervalStarted EventType = "started"
+	// IntervalEnded is sent by EndInterval with the final interval.
+	IntervalEnded EventType = "ended"
+)
+
+// Event is sent to subscribers for every interval written to the Recorder.
+type Event struct {
+	Type     EventType
+	Interval monitorapi.Interval
+}
+
+// Recorder wraps a recorder and sends every interval written through it to its subscribers.
+type Recorder struct {
+	delegate monitorapi.Recorder
+
+	lock        sync.Mutex
+	nextID      int
+	subscribers map[int]chan Event
+}
+
+var _ monitorapi.Recorder = &Recorder{}
+
+func WrapRecorder(delegate monitorapi.Recorder) *Recorder {
+	return &Recorder{
+		delegate:    delegate,
+		subscribers: map[int]chan Event{},
+	}
+}
+
+// Subscribe returns a channel receiving the events recorded from now on and a function to stop the subscription.
+// Events are never allowed to block the recorder: a subscriber that falls more than buffer events behind is
+// dropped and its channel is closed.
+func (r *Recorder) Subscribe(buffer int) (<-chan Event, func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	id := r.nextID
+	r.nextID++
+	ch := make(chan Event, buffer)
+	r.subscribers[id] = ch
+
+	return ch, func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if _, ok := r.subscribers[id]; ok {
+			delete(r.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+func (r *Recorder) publish(eventType EventType, intervals ...monitorapi.Interval) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, interval := range intervals {
+		for id, ch := range r.subscribers {
+			select {
+			case ch <- Event{Type: eventType, Interval: interval}:
+			default:
+				delete(r.subscribers, id)
+				close(ch)
+			}
+		}
+	}
+}
+
+func (r *Recorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return r.delegate.CurrentResourceState()
+}
+
+func (r *Recorder) RecordResource(resourceType string, obj runtime.Object) {
+	r.delegate.RecordResource(resourceType, obj)
+}
+
+func (r *Recorder) Record(conditions ...monitorapi.Condition) {
+	r.RecordAt(time.Now().UTC(), conditions...)
+}
+
+func (r *Recorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	r.AddIntervals(intervals...)
+}
+
+func (r *Recorder) AddIntervals(intervals ...monitorapi.Interval) {
+	r.delegate.AddIntervals(intervals...)
+	r.publish(IntervalAdded, intervals...)
+}
+
+func (r *Recorder) StartInterval(interval monitorapi.Interval) int {
+	ret := r.delegate.StartInterval(interval)
+	r.publish(IntervalStarted, interval)
+	return ret
+}
+
+func (r *Recorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	ret := r.delegate.EndInterval(startedInterval, t)
+	if ret != nil {
+		r.publish(IntervalEnded, *ret)
+	}
+	return ret
+}
+
+func (r *Recorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	return r.delegate.Intervals(from, to)
+}
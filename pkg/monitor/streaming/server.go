@@ -0,0 +1,240 @@
+package streaming
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/test/extended/testdata"
+)
+
+const (
+	// subscriberBuffer is the number of events a stream client can fall behind before it is disconnected.
+	subscriberBuffer  = 1000
+	keepAliveInterval = 15 * time.Second
+)
+
+// Server exposes the intervals and resources of a running monitor over HTTP:
+//
+//	GET /intervals?from=&to=&query=       intervals recorded so far, in the e2e-events json format
+//	GET /intervals/stream?since=&query=   server-sent events for every interval recorded from now on
+//	GET /resources?type=                  the tracked resources, by resource type
+//	GET /timeline?query=                  an html timeline of the intervals recorded so far
+//
+// from, to and since are RFC3339 times.  query is an interval query, see monitorapi.IntervalQuery.  The temporal
+// operators of a stream query are evaluated against the intervals recorded when the stream was opened.
+type Server struct {
+	recorder *Recorder
+	mux      *http.ServeMux
+}
+
+func NewServer(recorder *Recorder) *Server {
+	s := &Server{
+		recorder: recorder,
+		mux:      http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/intervals", s.handleIntervals)
+	s.mux.HandleFunc("/intervals/stream", s.handleStream)
+	s.mux.HandleFunc("/resources", s.handleResources)
+	s.mux.HandleFunc("/timeline", s.handleTimeline)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mux.ServeHTTP(w, req)
+}
+
+// ListenAndServe serves on address until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx is done.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler: s,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "error shutting down the interval server: %v\n", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving monitor intervals on http://%s\n", listener.Addr())
+	if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleIntervals(w http.ResponseWriter, req *http.Request) {
+	intervals, err := s.queryIntervals(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	intervalsJSON, err := monitorserialization.IntervalsToJSON(intervals)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(intervalsJSON)
+}
+
+func (s *Server) handleTimeline(w http.ResponseWriter, req *http.Request) {
+	intervals, err := s.queryIntervals(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	intervalsJSON, err := monitorserialization.EventsIntervalsToJSON(intervals)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	e2eChartHTML := testdata.MustAsset("e2echart/e2e-chart-template.html")
+	e2eChartHTML = bytes.ReplaceAll(e2eChartHTML, []byte("EVENT_INTERVAL_TITLE_GOES_HERE"), []byte(fmt.Sprintf("Intervals - live at %s", time.Now().UTC().Format(time.RFC3339))))
+	e2eChartHTML = bytes.ReplaceAll(e2eChartHTML, []byte("EVENT_INTERVAL_JSON_GOES_HERE"), intervalsJSON)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(e2eChartHTML)
+}
+
+func (s *Server) queryIntervals(req *http.Request) (monitorapi.Intervals, error) {
+	from, err := parseTimeParam(req, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseTimeParam(req, "to")
+	if err != nil {
+		return nil, err
+	}
+	query, err := monitorapi.ParseIntervalQuery(req.URL.Query().Get("query"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	intervals := s.recorder.Intervals(from, to)
+	return intervals.Filter(query.Matcher(intervals)), nil
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, req *http.Request) {
+	since, err := parseTimeParam(req, "since")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query, err := monitorapi.ParseIntervalQuery(req.URL.Query().Get("query"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// subscribe before reading the snapshot so that no interval is missed, an interval may be sent twice instead.
+	events, unsubscribe := s.recorder.Subscribe(subscriberBuffer)
+	defer unsubscribe()
+	snapshot := s.recorder.Intervals(time.Time{}, time.Time{})
+	matches := query.Matcher(snapshot)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if !since.IsZero() {
+		for _, interval := range snapshot {
+			if interval.From.Before(since) || !matches(interval) {
+				continue
+			}
+			if err := writeServerSentEvent(w, IntervalAdded, interval); err != nil {
+				return
+			}
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// the client was too slow and has been dropped, it can reconnect with since to catch up.
+				return
+			}
+			if !matches(event.Interval) {
+				continue
+			}
+			if err := writeServerSentEvent(w, event.Type, event.Interval); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeServerSentEvent(w http.ResponseWriter, eventType EventType, interval monitorapi.Interval) error {
+	intervalJSON, err := monitorserialization.IntervalToOneLineJSON(interval)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, intervalJSON)
+	return err
+}
+
+func (s *Server) handleResources(w http.ResponseWriter, req *http.Request) {
+	resources := s.recorder.CurrentResourceState()
+	ret := map[string][]interface{}{}
+	for resourceType, instances := range resources {
+		if requested := req.URL.Query().Get("type"); len(requested) > 0 && requested != resourceType {
+			continue
+		}
+		ret[resourceType] = []interface{}{}
+		for _, obj := range instances {
+			ret[resourceType] = append(ret[resourceType], obj)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ret); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing resources: %v\n", err)
+	}
+}
+
+func parseTimeParam(req *http.Request, name string) (time.Time, error) {
+	value := req.URL.Query().Get(name)
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	ret, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s, expected RFC3339: %w", name, err)
+	}
+	return ret, nil
+}
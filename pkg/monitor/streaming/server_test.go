@@ -0,0 +1,174 @@
+package streaming
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func nodeInterval(node string, from time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).HumanMessage("not ready")).
+		Build(from, from.Add(time.Minute))
+}
+
+func TestServer_Intervals(t *testing.T) {
+	recorder := WrapRecorder(monitor.NewRecorder())
+	from := time.Date(2023, 2, 14, 20, 0, 0, 0, time.UTC)
+	recorder.AddIntervals(nodeInterval("node-a", from), nodeInterval("node-b", from.Add(time.Hour)))
+	recorder.RecordResource("pods", &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pod", UID: "uid"}})
+
+	server := httptest.NewServer(NewServer(recorder))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/intervals?query=" + url.QueryEscape("locator.node=node-b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var list monitorserialization.EventIntervalList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Locator.Keys[monitorapi.LocatorNodeKey] != "node-b" {
+		t.Errorf("unexpected intervals %#v", list.Items)
+	}
+
+	resp, err = http.Get(server.URL + "/intervals?query=" + url.QueryEscape("unknown=field"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a bad request for an invalid query, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/resources?type=pods")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	resources := map[string][]corev1.Pod{}
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		t.Fatal(err)
+	}
+	if len(resources["pods"]) != 1 || resources["pods"][0].Name != "pod" {
+		t.Errorf("unexpected resources %#v", resources)
+	}
+}
+
+// TestServer_IntervalsWhileIntervalOpen ensures querying the intervals of a running monitor doesn't move the interval
+// a later EndInterval closes.
+func TestServer_IntervalsWhileIntervalOpen(t *testing.T) {
+	recorder := WrapRecorder(monitor.NewRecorder())
+	from := time.Date(2023, 2, 14, 20, 0, 0, 0, time.UTC)
+	started := nodeInterval("node-started", from.Add(time.Hour))
+	started.To = time.Time{}
+	startedInterval := recorder.StartInterval(started)
+	// recorded after, but sorted before the open interval
+	recorder.AddIntervals(nodeInterval("node-earlier", from))
+
+	server := httptest.NewServer(NewServer(recorder))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/intervals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	ended := recorder.EndInterval(startedInterval, from.Add(2*time.Hour))
+	if ended == nil || ended.Locator.Keys[monitorapi.LocatorNodeKey] != "node-started" {
+		t.Fatalf("expected the started interval to end, got %v", ended)
+	}
+	for _, interval := range recorder.Intervals(time.Time{}, time.Time{}) {
+		switch node := interval.Locator.Keys[monitorapi.LocatorNodeKey]; {
+		case node == "node-started" && !interval.To.Equal(from.Add(2*time.Hour)):
+			t.Errorf("expected the started interval to end at %v, got %v", from.Add(2*time.Hour), interval.To)
+		case node == "node-earlier" && !interval.To.Equal(from.Add(time.Minute)):
+			t.Errorf("expected the earlier interval to be unchanged, got %v", interval.To)
+		}
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	recorder := WrapRecorder(monitor.NewRecorder())
+	from := time.Date(2023, 2, 14, 20, 0, 0, 0, time.UTC)
+	recorder.AddIntervals(nodeInterval("node-old", from))
+
+	server := httptest.NewServer(NewServer(recorder))
+	defer server.Close()
+
+	since := url.QueryEscape(from.Format(time.RFC3339))
+	query := url.QueryEscape(`locator.node=~"node-(old|new)"`)
+	resp, err := http.Get(server.URL + "/intervals/stream?since=" + since + "&query=" + query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+
+	recorder.AddIntervals(nodeInterval("node-ignored", from.Add(time.Minute)))
+	id := recorder.StartInterval(nodeInterval("node-new", from.Add(time.Minute)))
+	recorder.EndInterval(id, from.Add(2*time.Minute))
+
+	expected := []string{"added node-old", "started node-new", "ended node-new"}
+	reader := bufio.NewReader(resp.Body)
+	actual := []string{}
+	eventType := ""
+	for len(actual) < len(expected) {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			interval, err := monitorserialization.IntervalFromJSON([]byte(strings.TrimPrefix(line, "data: ")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual = append(actual, eventType+" "+interval.Locator.Keys[monitorapi.LocatorNodeKey])
+		}
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("expected %v, got %v", expected, actual)
+			break
+		}
+	}
+}
+
+func TestRecorder_DropsSlowSubscribers(t *testing.T) {
+	recorder := WrapRecorder(monitor.NewRecorder())
+	events, unsubscribe := recorder.Subscribe(1)
+	defer unsubscribe()
+
+	from := time.Date(2023, 2, 14, 20, 0, 0, 0, time.UTC)
+	recorder.AddIntervals(nodeInterval("node-a", from), nodeInterval("node-b", from))
+
+	if event := <-events; event.Interval.Locator.Keys[monitorapi.LocatorNodeKey] != "node-a" {
+		t.Errorf("unexpected event %#v", event)
+	}
+	if _, ok := <-events; ok {
+		t.Errorf("expected the slow subscriber to be dropped")
+	}
+	if len(recorder.Intervals(time.Time{}, time.Time{})) != 2 {
+		t.Errorf("expected the delegate to record every interval")
+	}
+}
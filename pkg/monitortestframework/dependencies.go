@@ -0,0 +1,122 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// constructionGraph orders ConstructComputedIntervals across the monitor tests of a registry.
+type constructionGraph struct {
+	// dependencies maps every monitor test to the monitor tests it directly depends on.
+	dependencies map[string]sets.String
+	// blocked maps monitor tests that cannot run because they are in or after a dependency cycle to the cycle.
+	blocked map[string]string
+}
+
+func newConstructionGraph(monitorTests map[string]*monitorTesttItem) *constructionGraph {
+	providers := map[string]sets.String{}
+	for name, monitorTest := range monitorTests {
+		provider, ok := monitorTest.monitorTest.(ComputedIntervalSourceProvider)
+		if !ok {
+			continue
+		}
+		for _, source := range provider.ComputedIntervalSources() {
+			if _, ok := providers[string(source)]; !ok {
+				providers[string(source)] = sets.NewString()
+			}
+			providers[string(source)].Insert(name)
+		}
+	}
+
+	ret := &constructionGraph{
+		dependencies: map[string]sets.String{},
+		blocked:      map[string]string{},
+	}
+	for name, monitorTest := range monitorTests {
+		ret.dependencies[name] = sets.NewString()
+		dependent, ok := monitorTest.monitorTest.(DependentMonitorTest)
+		if !ok {
+			continue
+		}
+		dependencies := dependent.ConstructionDependencies()
+		for _, dependency := range dependencies.MonitorTests {
+			if _, ok := monitorTests[dependency]; ok {
+				ret.dependencies[name].Insert(dependency)
+			}
+		}
+		for _, source := range dependencies.IntervalSources {
+			ret.dependencies[name].Insert(providers[string(source)].UnsortedList()...)
+		}
+		// providing a source you depend on is not a cycle.
+		ret.dependencies[name].Delete(name)
+	}
+
+	// remove monitor tests without pending dependencies until none are left, what remains is blocked by a cycle.
+	pending := map[string]sets.String{}
+	for name, dependencies := range ret.dependencies {
+		pending[name] = sets.NewString(dependencies.UnsortedList()...)
+	}
+	for {
+		ready := []string{}
+		for name, dependencies := range pending {
+			if dependencies.Len() == 0 {
+				ready = append(ready, name)
+			}
+		}
+		if len(ready) == 0 {
+			break
+		}
+		for _, name := range ready {
+			delete(pending, name)
+		}
+		for _, dependencies := range pending {
+			dependencies.Delete(ready...)
+		}
+	}
+	for name := range pending {
+		ret.blocked[name] = findCycle(name, pending)
+	}
+
+	return ret
+}
+
+// findCycle follows the pending dependencies of start until a monitor test repeats.  Every pending monitor test has
+// at least one pending dependency, so a cycle is always found.
+func findCycle(start string, pending map[string]sets.String) string {
+	path := []string{start}
+	seen := map[string]int{start: 0}
+	for {
+		next := pending[path[len(path)-1]].List()[0]
+		if index, ok := seen[next]; ok {
+			return strings.Join(append(path[index:], next), " -> ")
+		}
+		seen[next] = len(path)
+		path = append(path, next)
+	}
+}
+
+// ancestors returns every monitor test that name depends on, directly or not.
+func (g *constructionGraph) ancestors(name string) sets.String {
+	ret := sets.NewString()
+	toVisit := g.dependencies[name].List()
+	for len(toVisit) > 0 {
+		curr := toVisit[0]
+		toVisit = toVisit[1:]
+		if ret.Has(curr) {
+			continue
+		}
+		ret.Insert(curr)
+		toVisit = append(toVisit, g.dependencies[curr].List()...)
+	}
+	return ret
+}
+
+func (g *constructionGraph) blockedError(name string) error {
+	cycle, ok := g.blocked[name]
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf("cannot construct intervals because of the dependency cycle %s", cycle)
+}
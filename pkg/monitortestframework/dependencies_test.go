@@ -0,0 +1,179 @@
+package monitortestframework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+// fakeConstruction computes one interval of its own source, and records the sources it was given.
+type fakeConstruction struct {
+	source       monitorapi.IntervalSource
+	dependencies ConstructionDependencies
+
+	seenSources []monitorapi.IntervalSource
+}
+
+func (f *fakeConstruction) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (f *fakeConstruction) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeConstruction) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	for _, interval := range startingIntervals {
+		f.seenSources = append(f.seenSources, interval.Source)
+	}
+	return monitorapi.Intervals{monitorapi.NewInterval(f.source, monitorapi.Info).Build(beginning, end)}, nil
+}
+
+func (f *fakeConstruction) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (f *fakeConstruction) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (f *fakeConstruction) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakeConstruction) ComputedIntervalSources() []monitorapi.IntervalSource {
+	return []monitorapi.IntervalSource{f.source}
+}
+
+func (f *fakeConstruction) ConstructionDependencies() ConstructionDependencies {
+	return f.dependencies
+}
+
+func TestConstructComputedIntervals_Dependencies(t *testing.T) {
+	raw := monitorapi.NewInterval("Raw", monitorapi.Info).Build(time.Unix(1, 0), time.Unix(2, 0))
+
+	nodes := &fakeConstruction{source: "Nodes"}
+	operators := &fakeConstruction{source: "Operators"}
+	// depends on nodes by source and on operators by name.
+	combined := &fakeConstruction{source: "Combined", dependencies: ConstructionDependencies{
+		MonitorTests:    []string{"operators", "disabled"},
+		IntervalSources: []monitorapi.IntervalSource{"Nodes"},
+	}}
+	// depends on nodes through combined.
+	downstream := &fakeConstruction{source: "Downstream", dependencies: ConstructionDependencies{
+		MonitorTests: []string{"combined"},
+	}}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("nodes", "Test Framework", nodes)
+	registry.AddMonitorTestOrDie("operators", "Test Framework", operators)
+	registry.AddMonitorTestOrDie("combined", "Test Framework", combined)
+	registry.AddMonitorTestOrDie("downstream", "Test Framework", downstream)
+
+	intervals, junits, err := registry.ConstructComputedIntervals(context.Background(), monitorapi.Intervals{raw}, nil, time.Unix(0, 0), time.Unix(10, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 4 || len(junits) != 4 {
+		t.Errorf("expected one interval and one junit per monitor test, got %d and %d", len(intervals), len(junits))
+	}
+
+	expected := map[*fakeConstruction]string{
+		nodes:      "Raw",
+		operators:  "Raw",
+		combined:   "Nodes,Operators,Raw",
+		downstream: "Combined,Nodes,Operators,Raw",
+	}
+	for monitorTest, expectedSources := range expected {
+		actual := []string{}
+		for _, source := range monitorTest.seenSources {
+			actual = append(actual, string(source))
+		}
+		// the starting intervals are sorted by time, which is the same for all of them, then by source.
+		if strings.Join(actual, ",") != expectedSources {
+			t.Errorf("%s: expected %v, got %v", monitorTest.source, expectedSources, actual)
+		}
+	}
+}
+
+func TestConstructComputedIntervals_Cycle(t *testing.T) {
+	first := &fakeConstruction{source: "First", dependencies: ConstructionDependencies{IntervalSources: []monitorapi.IntervalSource{"Second"}}}
+	second := &fakeConstruction{source: "Second", dependencies: ConstructionDependencies{MonitorTests: []string{"first"}}}
+	after := &fakeConstruction{source: "After", dependencies: ConstructionDependencies{MonitorTests: []string{"second"}}}
+	independent := &fakeConstruction{source: "Independent"}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("first", "Test Framework", first)
+	registry.AddMonitorTestOrDie("second", "Test Framework", second)
+	registry.AddMonitorTestOrDie("after", "Test Framework", after)
+	registry.AddMonitorTestOrDie("independent", "Test Framework", independent)
+
+	intervals, junits, err := registry.ConstructComputedIntervals(context.Background(), nil, nil, time.Unix(0, 0), time.Unix(10, 0))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(intervals) != 1 || intervals[0].Source != "Independent" {
+		t.Errorf("expected only the independent monitor test to run, got %v", intervals)
+	}
+
+	failed := []string{}
+	for _, junit := range junits {
+		if junit.FailureOutput != nil {
+			failed = append(failed, junit.Name)
+			if !strings.Contains(junit.FailureOutput.Output, "first -> second -> first") && !strings.Contains(junit.FailureOutput.Output, "second -> first -> second") {
+				t.Errorf("expected the cycle in %q", junit.FailureOutput.Output)
+			}
+		}
+	}
+	if len(failed) != 3 {
+		t.Errorf("expected the cycle and its dependents to fail, got %v", failed)
+	}
+}
+
+// sharingConstruction records the first of the intervals it is given.
+type sharingConstruction struct {
+	fakeConstruction
+
+	firstInterval *monitorapi.Interval
+}
+
+func (f *sharingConstruction) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	f.firstInterval = &startingIntervals[0]
+	return f.fakeConstruction.ConstructComputedIntervals(ctx, startingIntervals, recordedResources, beginning, end)
+}
+
+// The memory of the interval construction does not grow with the number of monitor tests, only those with
+// dependencies get a copy of the starting intervals.
+func TestConstructComputedIntervals_SharesStartingIntervals(t *testing.T) {
+	startingIntervals := monitorapi.Intervals{}
+	for i := 0; i < 100; i++ {
+		startingIntervals = append(startingIntervals, monitorapi.NewInterval("Raw", monitorapi.Info).Build(time.Unix(int64(i), 0), time.Unix(int64(i+1), 0)))
+	}
+
+	independent := &sharingConstruction{fakeConstruction: fakeConstruction{source: "Independent"}}
+	dependent := &sharingConstruction{fakeConstruction: fakeConstruction{
+		source:       "Dependent",
+		dependencies: ConstructionDependencies{MonitorTests: []string{"independent"}},
+	}}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("independent", "Test Framework", independent)
+	registry.AddMonitorTestOrDie("dependent", "Test Framework", dependent)
+	if _, _, err := registry.ConstructComputedIntervals(context.Background(), startingIntervals, nil, time.Unix(0, 0), time.Unix(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if independent.firstInterval != &startingIntervals[0] {
+		t.Errorf("expected the monitor test without dependencies to be given the starting intervals")
+	}
+	if dependent.firstInterval == &startingIntervals[0] {
+		t.Errorf("expected the monitor test with dependencies to be given a copy of the starting intervals")
+	}
+	if len(dependent.seenSources) != len(startingIntervals)+1 {
+		t.Errorf("expected the dependent monitor test to see the starting and the independent intervals, got %v", dependent.seenSources)
+	}
+}
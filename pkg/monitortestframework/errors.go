@@ -1,6 +1,9 @@
 package monitortestframework
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // NotSupportedError represents an error when a monitor test is unsupported for the given environment.
 type NotSupportedError struct {
@@ -19,3 +22,14 @@ type FlakeError struct {
 func (e *FlakeError) Error() string {
 	return fmt.Sprintf("test flake with error: %v", e.Err)
 }
+
+// PhaseTimeoutError is returned for a phase of a monitor test that did not finish in time.
+type PhaseTimeoutError struct {
+	MonitorTest string
+	Phase       Phase
+	Timeout     time.Duration
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("monitor test %q did not finish %s within %v and was abandoned", e.MonitorTest, e.Phase, e.Timeout)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,11 +16,16 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
 type monitorTestRegistry struct {
-	monitorTests map[string]*monitorTesttItem
+	monitorTests  map[string]*monitorTesttItem
+	phaseTimeouts PhaseTimeouts
+
+	timingLock sync.Mutex
+	timings    []PhaseTiming
 }
 
 type monitorTesttItem struct {
@@ -57,6 +63,7 @@ func (r *monitorTestRegistry) AddMonitorTestOrDie(name, jiraComponent string, mo
 
 func (r *monitorTestRegistry) GetRegistryFor(names ...string) (MonitorTestRegistry, error) {
 	ret := NewMonitorTestRegistry().(*monitorTestRegistry)
+	ret.phaseTimeouts = r.phaseTimeouts
 
 	missingNames := []string{}
 	for _, name := range names {
@@ -78,6 +85,16 @@ func (r *monitorTestRegistry) ListMonitorTests() sets.String {
 	return sets.StringKeySet(r.monitorTests)
 }
 
+func (r *monitorTestRegistry) SetPhaseTimeouts(timeouts PhaseTimeouts) {
+	r.phaseTimeouts = timeouts
+}
+
+func (r *monitorTestRegistry) PhaseTimings() []PhaseTiming {
+	r.timingLock.Lock()
+	defer r.timingLock.Unlock()
+	return append([]PhaseTiming{}, r.timings...)
+}
+
 func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) ([]*junitapi.JUnitTestCase, error) {
 	wg := sync.WaitGroup{}
 	junitCh := make(chan *junitapi.JUnitTestCase, 2*len(r.monitorTests))
@@ -91,10 +108,9 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 			testName := fmt.Sprintf("[Jira:%q] monitor test %v setup", invariant.jiraComponent, invariant.name)
 			logrus.Infof("  Starting %v for %v", invariant.name, invariant.jiraComponent)
 
-			start := time.Now()
-			err := startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
-			end := time.Now()
-			duration := end.Sub(start)
+			_, duration, err := runPhase(ctx, r, invariant, PhaseStartCollection, func(ctx context.Context) (struct{}, error) {
+				return struct{}{}, startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
+			})
 			if err != nil {
 				var nsErr *NotSupportedError
 				if errors.As(err, &nsErr) {
@@ -159,13 +175,17 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 			defer wg.Done()
 			testName := fmt.Sprintf("[Jira:%q] monitor test %v collection", monitorTest.jiraComponent, monitorTest.name)
 
-			start := time.Now()
+			type collected struct {
+				intervals monitorapi.Intervals
+				junits    []*junitapi.JUnitTestCase
+			}
 			logrus.Infof("  Starting CollectData for %s", testName)
-			localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
-			intervalsCh <- localIntervals
-			junitCh <- localJunits
-			end := time.Now()
-			duration := end.Sub(start)
+			local, duration, err := runPhase(ctx, r, monitorTest, PhaseCollectData, func(ctx context.Context) (collected, error) {
+				localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
+				return collected{intervals: localIntervals, junits: localJunits}, err
+			})
+			intervalsCh <- local.intervals
+			junitCh <- local.junits
 			if err != nil {
 				var nsErr *NotSupportedError
 				if errors.As(err, &nsErr) {
@@ -230,19 +250,35 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 	return intervals, junits, utilerrors.NewAggregate(errs)
 }
 
-func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	intervals := monitorapi.Intervals{}
+func (r *monitorTestRegistry) PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
 
-	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+	for name, monitorTest := range r.monitorTests {
+		testName := fmt.Sprintf("[Jira:%q] monitor test %v replay preparation", monitorTest.jiraComponent, monitorTest.name)
+		if nonReplayable, ok := monitorTest.monitorTest.(NonReplayableMonitorTest); ok {
+			// its clients and state are set by StartCollection, which a replay never calls.
+			delete(r.monitorTests, name)
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name: testName,
+				SkipMessage: &junitapi.SkipMessage{
+					Message: nonReplayable.NotReplayableReason(),
+				},
+			})
+			continue
+		}
+		replayable, ok := monitorTest.monitorTest.(ReplayableMonitorTest)
+		if !ok {
+			// it only uses the intervals and resources passed to the later stages.
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name: testName,
+			})
+			continue
+		}
 
-		start := time.Now()
-		localIntervals, err := constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
-		intervals = append(intervals, localIntervals...)
-		end := time.Now()
-		duration := end.Sub(start)
+		_, duration, err := runPhase(ctx, r, monitorTest, PhasePrepareForReplay, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, prepareForReplayWithPanicProtection(ctx, replayable, clusterData, beginning, end)
+		})
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -261,9 +297,9 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 				Name:     testName,
 				Duration: duration.Seconds(),
 				FailureOutput: &junitapi.FailureOutput{
-					Output: fmt.Sprintf("failed during interval construction\n%v", err),
+					Output: fmt.Sprintf("failed during replay preparation\n%v", err),
 				},
-				SystemOut: fmt.Sprintf("failed during interval construction\n%v", err),
+				SystemOut: fmt.Sprintf("failed during replay preparation\n%v", err),
 			})
 			var flakeErr *FlakeError
 			if !errors.As(err, &flakeErr) {
@@ -277,9 +313,126 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 		})
 	}
 
+	return junits, utilerrors.NewAggregate(errs)
+}
+
+func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	type constructionResult struct {
+		done      chan struct{}
+		intervals monitorapi.Intervals
+		junits    []*junitapi.JUnitTestCase
+		err       error
+	}
+
+	graph := newConstructionGraph(r.monitorTests)
+	results := map[string]*constructionResult{}
+	for name := range r.monitorTests {
+		results[name] = &constructionResult{done: make(chan struct{})}
+	}
+
+	wg := sync.WaitGroup{}
+	for name := range r.monitorTests {
+		wg.Add(1)
+		go func(monitorTest *monitorTesttItem, result *constructionResult) {
+			defer wg.Done()
+			defer close(result.done)
+
+			if err := graph.blockedError(monitorTest.name); err != nil {
+				testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+				result.err = err
+				result.junits = []*junitapi.JUnitTestCase{
+					{
+						Name: testName,
+						FailureOutput: &junitapi.FailureOutput{
+							Output: fmt.Sprintf("failed during interval construction\n%v", err),
+						},
+						SystemOut: fmt.Sprintf("failed during interval construction\n%v", err),
+					},
+				}
+				return
+			}
+
+			// startingIntervals is shared read-only by every monitor test, only those with dependencies get a copy
+			// holding the intervals of their dependencies too.
+			inputIntervals := startingIntervals
+			if ancestors := graph.ancestors(monitorTest.name); ancestors.Len() > 0 {
+				for _, ancestor := range ancestors.List() {
+					<-results[ancestor].done
+				}
+				inputIntervals = append(monitorapi.Intervals{}, startingIntervals...)
+				for _, ancestor := range ancestors.List() {
+					inputIntervals = append(inputIntervals, results[ancestor].intervals...)
+				}
+				sort.Sort(inputIntervals)
+			}
+
+			result.intervals, result.junits, result.err = r.constructComputedIntervals(ctx, monitorTest, inputIntervals, recordedResources, beginning, end)
+		}(r.monitorTests[name], results[name])
+	}
+	wg.Wait()
+
+	intervals := monitorapi.Intervals{}
+	junits := []*junitapi.JUnitTestCase{}
+	errs := []error{}
+	for _, name := range sets.StringKeySet(results).List() {
+		intervals = append(intervals, results[name].intervals...)
+		junits = append(junits, results[name].junits...)
+		if results[name].err != nil {
+			errs = append(errs, results[name].err)
+		}
+	}
+
 	return intervals, junits, utilerrors.NewAggregate(errs)
 }
 
+func (r *monitorTestRegistry) constructComputedIntervals(ctx context.Context, monitorTest *monitorTesttItem, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+
+	intervals, duration, err := runPhase(ctx, r, monitorTest, PhaseConstructComputedIntervals, func(ctx context.Context) (monitorapi.Intervals, error) {
+		return constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
+	})
+	if err != nil {
+		var nsErr *NotSupportedError
+		if errors.As(err, &nsErr) {
+			return intervals, []*junitapi.JUnitTestCase{
+				{
+					Name:     testName,
+					Duration: duration.Seconds(),
+					SkipMessage: &junitapi.SkipMessage{
+						Message: nsErr.Reason,
+					},
+				},
+			}, nil
+		}
+
+		junits := []*junitapi.JUnitTestCase{
+			{
+				Name:     testName,
+				Duration: duration.Seconds(),
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("failed during interval construction\n%v", err),
+				},
+				SystemOut: fmt.Sprintf("failed during interval construction\n%v", err),
+			},
+		}
+		var flakeErr *FlakeError
+		if !errors.As(err, &flakeErr) {
+			return intervals, junits, err
+		}
+		return intervals, append(junits, &junitapi.JUnitTestCase{
+			Name:     testName,
+			Duration: duration.Seconds(),
+		}), err
+	}
+
+	return intervals, []*junitapi.JUnitTestCase{
+		{
+			Name:     testName,
+			Duration: duration.Seconds(),
+		},
+	}, nil
+}
+
 func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
@@ -287,11 +440,10 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 	for _, monitorTest := range r.monitorTests {
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v test evaluation", monitorTest.jiraComponent, monitorTest.name)
 
-		start := time.Now()
-		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
+		localJunits, duration, err := runPhase(ctx, r, monitorTest, PhaseEvaluateTestsFromConstructedIntervals, func(ctx context.Context) ([]*junitapi.JUnitTestCase, error) {
+			return evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
+		})
 		junits = append(junits, localJunits...)
-		end := time.Now()
-		duration := end.Sub(start)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -336,8 +488,6 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 	for _, monitorTest := range r.monitorTests {
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v writing to storage", monitorTest.jiraComponent, monitorTest.name)
 
-		start := time.Now()
-
 		var finalIntervalLength = len(finalIntervals)
 		fmt.Fprintf(os.Stderr, "Processing monitorTest: %s\n", monitorTest.name)
 		fmt.Fprintf(os.Stderr, "  finalIntervals size = %d\n", finalIntervalLength)
@@ -346,9 +496,9 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 			fmt.Fprintf(os.Stderr, "  last interval time: From = %s; To = %s\n", finalIntervals[finalIntervalLength-1].From, finalIntervals[finalIntervalLength-1].To)
 		}
 
-		err := writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)
-		end := time.Now()
-		duration := end.Sub(start)
+		_, duration, err := runPhase(ctx, r, monitorTest, PhaseWriteContentToStorage, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)
+		})
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -383,6 +533,11 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 		})
 	}
 
+	// this is the last phase, so the timings are complete.
+	if err := r.writePhaseTimings(storageDir, timeSuffix); err != nil {
+		logrus.WithError(err).Warn("unable to write monitor test phase timings")
+	}
+
 	return junits, utilerrors.NewAggregate(errs)
 }
 
@@ -394,11 +549,10 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 		testName := fmt.Sprintf("[Jira:%q] monitor test %v cleanup", monitorTest.jiraComponent, monitorTest.name)
 		log := logrus.WithField("monitorTest", monitorTest.name)
 
-		start := time.Now()
 		log.Info("beginning cleanup")
-		err := cleanupWithPanicProtection(ctx, monitorTest.monitorTest)
-		end := time.Now()
-		duration := end.Sub(start)
+		_, duration, err := runPhase(ctx, r, monitorTest, PhaseCleanup, func(ctx context.Context) (struct{}, error) {
+			return struct{}{}, cleanupWithPanicProtection(ctx, monitorTest.monitorTest)
+		})
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
@@ -0,0 +1,78 @@
+package monitortestframework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// replayableConstruction records the cluster data it was prepared for replay with.
+type replayableConstruction struct {
+	fakeConstruction
+
+	clusterData *platformidentification.ClusterData
+}
+
+func (r *replayableConstruction) PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) error {
+	r.clusterData = &clusterData
+	return nil
+}
+
+// clusterBoundConstruction needs the cluster after StartCollection.
+type clusterBoundConstruction struct {
+	fakeConstruction
+}
+
+func (c *clusterBoundConstruction) NotReplayableReason() string {
+	return "queries the cluster while evaluating"
+}
+
+func TestPrepareForReplay_SkipsMonitorTestsNeedingACluster(t *testing.T) {
+	replayable := &replayableConstruction{fakeConstruction: fakeConstruction{source: "Replayable"}}
+	intervalsOnly := &fakeConstruction{source: "IntervalsOnly"}
+	clusterBound := &clusterBoundConstruction{fakeConstruction: fakeConstruction{source: "ClusterBound"}}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("replayable", "Test Framework", replayable)
+	registry.AddMonitorTestOrDie("intervals-only", "Test Framework", intervalsOnly)
+	registry.AddMonitorTestOrDie("cluster-bound", "Test Framework", clusterBound)
+
+	clusterData := platformidentification.ClusterData{JobType: platformidentification.JobType{Platform: "aws"}}
+	junits, err := registry.PrepareForReplay(context.Background(), clusterData, time.Unix(0, 0), time.Unix(10, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayable.clusterData == nil || replayable.clusterData.Platform != "aws" {
+		t.Errorf("expected the replayable monitor test to get the cluster data, got %#v", replayable.clusterData)
+	}
+	var skipped, prepared, passed bool
+	for _, junit := range junits {
+		switch {
+		case junit.Name == `[Jira:"Test Framework"] monitor test cluster-bound replay preparation` && junit.SkipMessage != nil:
+			skipped = junit.SkipMessage.Message == "queries the cluster while evaluating"
+		case junit.Name == `[Jira:"Test Framework"] monitor test replayable replay preparation` && junit.SkipMessage == nil && junit.FailureOutput == nil:
+			prepared = true
+		case junit.Name == `[Jira:"Test Framework"] monitor test intervals-only replay preparation` && junit.SkipMessage == nil && junit.FailureOutput == nil:
+			passed = true
+		}
+	}
+	if !skipped || !prepared || !passed {
+		t.Errorf("expected cluster-bound to be skipped, replayable to be prepared and intervals-only to pass, got %#v", junits)
+	}
+
+	intervals, _, err := registry.ConstructComputedIntervals(context.Background(), nil, nil, time.Unix(0, 0), time.Unix(10, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := sets.NewString()
+	for _, interval := range intervals {
+		sources.Insert(string(interval.Source))
+	}
+	if !sources.Equal(sets.NewString("Replayable", "IntervalsOnly")) {
+		t.Errorf("expected the replayable and intervals-only monitor tests to construct intervals, got %v", intervals.Strings())
+	}
+}
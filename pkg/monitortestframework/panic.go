@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
@@ -39,6 +40,19 @@ func collectDataWithPanicProtection(ctx context.Context, monitortest MonitorTest
 	return
 }
 
+func prepareForReplayWithPanicProtection(ctx context.Context, monitortest ReplayableMonitorTest, clusterData platformidentification.ClusterData, beginning, end time.Time) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+			logrus.Error("recovering from panic")
+			fmt.Print(debug.Stack())
+		}
+	}()
+
+	err = monitortest.PrepareForReplay(ctx, clusterData, beginning, end)
+	return
+}
+
 func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitortest MonitorTest, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (intervals monitorapi.Intervals, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,276 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/origin/pkg/dataloader"
+)
+
+// Phase is a lifecycle method of a MonitorTest.
+type Phase string
+
+const (
+	PhaseStartCollection                       Phase = "StartCollection"
+	PhaseCollectData                           Phase = "CollectData"
+	PhasePrepareForReplay                      Phase = "PrepareForReplay"
+	PhaseConstructComputedIntervals            Phase = "ConstructComputedIntervals"
+	PhaseEvaluateTestsFromConstructedIntervals Phase = "EvaluateTestsFromConstructedIntervals"
+	PhaseWriteContentToStorage                 Phase = "WriteContentToStorage"
+	PhaseCleanup                               Phase = "Cleanup"
+)
+
+var AllPhases = []Phase{
+	PhaseStartCollection,
+	PhaseCollectData,
+	PhasePrepareForReplay,
+	PhaseConstructComputedIntervals,
+	PhaseEvaluateTestsFromConstructedIntervals,
+	PhaseWriteContentToStorage,
+	PhaseCleanup,
+}
+
+// PhaseTimeoutsEnv lists phase timeouts, separated by commas and in the form parsed by ParsePhaseTimeouts, that
+// replace the built-in defaults for every command, for instance for the jobs whose CollectData needs more than 45m.
+const PhaseTimeoutsEnv = "OPENSHIFT_TESTS_MONITOR_PHASE_TIMEOUTS"
+
+// PhaseTimeoutsFlagUsage is the help of the --monitor-phase-timeout flag of the commands running monitor tests.
+const PhaseTimeoutsFlagUsage = "Override how long a monitor test lifecycle method may run before it is abandoned and reported as a failure, as [<monitor test>:]<phase>=<duration>.  " +
+	"Phases are StartCollection, CollectData, PrepareForReplay, ConstructComputedIntervals, EvaluateTestsFromConstructedIntervals, WriteContentToStorage and Cleanup.  " +
+	"A duration of 0 disables the timeout.  The defaults, 45m for CollectData, 30m for WriteContentToStorage and 15m for the other phases, " +
+	"are overridden for every command by " + PhaseTimeoutsEnv + ", a comma separated list of the same form."
+
+// phaseCancellationGracePeriod is how long a timed out phase is waited for once its context is cancelled.
+var phaseCancellationGracePeriod = time.Minute
+
+// defaultPhaseTimeouts are generous on purpose, they exist to turn a hung monitor test into a junit failure before
+// CI kills the job, not to police slow monitor tests.
+var defaultPhaseTimeouts = map[Phase]time.Duration{
+	PhaseStartCollection:                       15 * time.Minute,
+	PhaseCollectData:                           45 * time.Minute,
+	PhasePrepareForReplay:                      15 * time.Minute,
+	PhaseConstructComputedIntervals:            15 * time.Minute,
+	PhaseEvaluateTestsFromConstructedIntervals: 15 * time.Minute,
+	PhaseWriteContentToStorage:                 30 * time.Minute,
+	PhaseCleanup:                               15 * time.Minute,
+}
+
+// PhaseTimeouts bounds how long each lifecycle method of a MonitorTest may run.  A timeout of zero disables the
+// deadline.  Phases without a configured timeout use the built-in defaults.
+type PhaseTimeouts struct {
+	// Default applies to every monitor test.
+	Default map[Phase]time.Duration
+	// MonitorTests overrides Default for the monitor tests with the given names.
+	MonitorTests map[string]map[Phase]time.Duration
+}
+
+func (t PhaseTimeouts) timeoutFor(monitorTest string, phase Phase) time.Duration {
+	if timeout, ok := t.MonitorTests[monitorTest][phase]; ok {
+		return timeout
+	}
+	if timeout, ok := t.Default[phase]; ok {
+		return timeout
+	}
+	return defaultPhaseTimeouts[phase]
+}
+
+// ParsePhaseTimeouts parses values of the form [<monitor test>:]<phase>=<duration>, for instance
+// CollectData=1h or audit-log-analyzer:CollectData=90m.  The values of PhaseTimeoutsEnv come first, so the given
+// values override them.
+func ParsePhaseTimeouts(values []string) (PhaseTimeouts, error) {
+	ret := PhaseTimeouts{
+		Default:      map[Phase]time.Duration{},
+		MonitorTests: map[string]map[Phase]time.Duration{},
+	}
+	if envValues := os.Getenv(PhaseTimeoutsEnv); len(envValues) > 0 {
+		values = append(strings.Split(envValues, ","), values...)
+	}
+	for _, value := range values {
+		key, durationString, ok := strings.Cut(value, "=")
+		if !ok {
+			return PhaseTimeouts{}, fmt.Errorf("%q must be of the form [<monitor test>:]<phase>=<duration>", value)
+		}
+		monitorTest, phaseString, hasMonitorTest := strings.Cut(key, ":")
+		if !hasMonitorTest {
+			phaseString = key
+		}
+		phase := Phase(phaseString)
+		if _, ok := defaultPhaseTimeouts[phase]; !ok {
+			return PhaseTimeouts{}, fmt.Errorf("%q has an unknown phase %q, must be one of %v", value, phaseString, AllPhases)
+		}
+		duration, err := time.ParseDuration(durationString)
+		if err != nil {
+			return PhaseTimeouts{}, fmt.Errorf("%q has an invalid duration: %w", value, err)
+		}
+		if duration < 0 {
+			return PhaseTimeouts{}, fmt.Errorf("%q has a negative duration", value)
+		}
+
+		if !hasMonitorTest {
+			ret.Default[phase] = duration
+			continue
+		}
+		if _, ok := ret.MonitorTests[monitorTest]; !ok {
+			ret.MonitorTests[monitorTest] = map[Phase]time.Duration{}
+		}
+		ret.MonitorTests[monitorTest][phase] = duration
+	}
+	return ret, nil
+}
+
+// PhaseTiming records one call of a lifecycle method of a monitor test.
+type PhaseTiming struct {
+	MonitorTest   string
+	JiraComponent string
+	Phase         Phase
+	Start         time.Time
+	Duration      time.Duration
+	Timeout       time.Duration
+	// Result is one of Passed, Failed, Flaked, Skipped or TimedOut.
+	Result string
+}
+
+func phaseResult(err error) string {
+	var nsErr *NotSupportedError
+	var flakeErr *FlakeError
+	var timeoutErr *PhaseTimeoutError
+	switch {
+	case err == nil:
+		return "Passed"
+	case errors.As(err, &nsErr):
+		return "Skipped"
+	case errors.As(err, &timeoutErr):
+		return "TimedOut"
+	case errors.As(err, &flakeErr):
+		return "Flaked"
+	default:
+		return "Failed"
+	}
+}
+
+// runPhase calls fn under the watchdog of the phase: once the timeout expires, fn is abandoned and a
+// PhaseTimeoutError is returned so that the remaining monitor tests can carry on.  Except for StartCollection, whose
+// context must stay valid for the entire collection, fn gets a context cancelled with the timeout and is waited for
+// phaseCancellationGracePeriod to stop on its own.  A fn ignoring its context, or timing out in StartCollection, keeps
+// running in the background until the process exits, any result it produces later is dropped.
+func runPhase[T any](ctx context.Context, r *monitorTestRegistry, monitorTest *monitorTesttItem, phase Phase, fn func(ctx context.Context) (T, error)) (T, time.Duration, error) {
+	timeout := r.phaseTimeouts.timeoutFor(monitorTest.name, phase)
+
+	start := time.Now()
+	ret, err := callWithTimeout(ctx, monitorTest.name, phase, timeout, fn)
+	duration := time.Now().Sub(start)
+	if err != nil {
+		var timeoutErr *PhaseTimeoutError
+		if errors.As(err, &timeoutErr) {
+			logrus.WithError(err).Errorf("  Abandoning %s for %s", phase, monitorTest.name)
+		}
+	}
+
+	r.timingLock.Lock()
+	defer r.timingLock.Unlock()
+	r.timings = append(r.timings, PhaseTiming{
+		MonitorTest:   monitorTest.name,
+		JiraComponent: monitorTest.jiraComponent,
+		Phase:         phase,
+		Start:         start,
+		Duration:      duration,
+		Timeout:       timeout,
+		Result:        phaseResult(err),
+	})
+
+	return ret, duration, err
+}
+
+func callWithTimeout[T any](ctx context.Context, monitorTestName string, phase Phase, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	phaseCtx := ctx
+	if phase != PhaseStartCollection {
+		var cancel context.CancelFunc
+		phaseCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		value, err := fn(phaseCtx)
+		resultCh <- result{value: value, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var zero T
+	select {
+	case curr := <-resultCh:
+		return curr.value, curr.err
+	case <-timer.C:
+	}
+
+	timeoutErr := &PhaseTimeoutError{MonitorTest: monitorTestName, Phase: phase, Timeout: timeout}
+	if phase == PhaseStartCollection {
+		return zero, timeoutErr
+	}
+	// the context of the phase expired with the timer, give fn a chance to notice.
+	grace := time.NewTimer(phaseCancellationGracePeriod)
+	defer grace.Stop()
+	select {
+	case <-resultCh:
+	case <-grace.C:
+		logrus.Warnf("  %s of %s did not stop within %v of its cancellation, leaving it running", phase, monitorTestName, phaseCancellationGracePeriod)
+	}
+	return zero, timeoutErr
+}
+
+// writePhaseTimings writes the timings of every phase run so far in a file loaded by the ci-data-loader.
+func (r *monitorTestRegistry) writePhaseTimings(storageDir, timeSuffix string) error {
+	r.timingLock.Lock()
+	timings := append([]PhaseTiming{}, r.timings...)
+	r.timingLock.Unlock()
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Start.Before(timings[j].Start)
+	})
+	rows := []map[string]string{}
+	for _, timing := range timings {
+		rows = append(rows, map[string]string{
+			"MonitorTest":     timing.MonitorTest,
+			"JiraComponent":   timing.JiraComponent,
+			"Phase":           string(timing.Phase),
+			"StartTime":       timing.Start.UTC().Format(time.RFC3339),
+			"DurationSeconds": strconv.FormatFloat(timing.Duration.Seconds(), 'f', 3, 64),
+			"TimeoutSeconds":  strconv.FormatFloat(timing.Timeout.Seconds(), 'f', 0, 64),
+			"Result":          timing.Result,
+		})
+	}
+
+	dataFile := dataloader.DataFile{
+		TableName: "monitor_test_phase_timings",
+		Schema: map[string]dataloader.DataType{
+			"MonitorTest":     dataloader.DataTypeString,
+			"JiraComponent":   dataloader.DataTypeString,
+			"Phase":           dataloader.DataTypeString,
+			"StartTime":       dataloader.DataTypeTimestamp,
+			"DurationSeconds": dataloader.DataTypeFloat64,
+			"TimeoutSeconds":  dataloader.DataTypeFloat64,
+			"Result":          dataloader.DataTypeString,
+		},
+		Rows: rows,
+	}
+	fileName := filepath.Join(storageDir, fmt.Sprintf("monitor-test-phase-timings%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	return dataloader.WriteDataFile(fileName, dataFile)
+}
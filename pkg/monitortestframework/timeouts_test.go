@@ -0,0 +1,192 @@
+package monitortestframework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+func TestParsePhaseTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    PhaseTimeouts
+		wantErr string
+	}{
+		{
+			name:   "default and per monitor test",
+			values: []string{"CollectData=1h", "audit-log-analyzer:CollectData=90m", "audit-log-analyzer:Cleanup=0"},
+			want: PhaseTimeouts{
+				Default: map[Phase]time.Duration{PhaseCollectData: time.Hour},
+				MonitorTests: map[string]map[Phase]time.Duration{
+					"audit-log-analyzer": {PhaseCollectData: 90 * time.Minute, PhaseCleanup: 0},
+				},
+			},
+		},
+		{
+			name:    "unknown phase",
+			values:  []string{"Collect=1h"},
+			wantErr: `unknown phase "Collect"`,
+		},
+		{
+			name:    "missing duration",
+			values:  []string{"CollectData"},
+			wantErr: "must be of the form",
+		},
+		{
+			name:    "negative duration",
+			values:  []string{"CollectData=-1m"},
+			wantErr: "negative duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePhaseTimeouts(tt.values)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+			if timeout := got.timeoutFor("other", PhaseCleanup); timeout != defaultPhaseTimeouts[PhaseCleanup] {
+				t.Errorf("expected the built-in default for unconfigured phases, got %v", timeout)
+			}
+		})
+	}
+}
+
+// hangingMonitorTest never returns from CollectData until released, and records the context of StartCollection.
+type hangingMonitorTest struct {
+	fakeConstruction
+
+	release           chan struct{}
+	collectionCtx     context.Context
+	collectedInterval monitorapi.Interval
+}
+
+func (h *hangingMonitorTest) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	h.collectionCtx = ctx
+	return nil
+}
+
+func (h *hangingMonitorTest) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	<-h.release
+	return monitorapi.Intervals{h.collectedInterval}, nil, nil
+}
+
+func TestParsePhaseTimeouts_environment(t *testing.T) {
+	t.Setenv(PhaseTimeoutsEnv, "CollectData=2h,WriteContentToStorage=1h")
+	got, err := ParsePhaseTimeouts([]string{"CollectData=90m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Phase]time.Duration{PhaseCollectData: 90 * time.Minute, PhaseWriteContentToStorage: time.Hour}
+	if !reflect.DeepEqual(got.Default, want) {
+		t.Errorf("expected the flags to override the environment, %v, got %v", want, got.Default)
+	}
+}
+
+func TestCallWithTimeout_waitsForTheCancelledPhase(t *testing.T) {
+	stopped := make(chan struct{})
+	_, err := callWithTimeout(context.Background(), "cancellable", PhaseCollectData, 10*time.Millisecond, func(ctx context.Context) (struct{}, error) {
+		<-ctx.Done()
+		close(stopped)
+		return struct{}{}, ctx.Err()
+	})
+	var timeoutErr *PhaseTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Errorf("expected the cancelled phase to have stopped before the timeout is reported")
+	}
+}
+
+func TestRegistry_PhaseTimeouts(t *testing.T) {
+	// the hanging monitor test ignores the cancellation of its context.
+	defer func(gracePeriod time.Duration) { phaseCancellationGracePeriod = gracePeriod }(phaseCancellationGracePeriod)
+	phaseCancellationGracePeriod = 10 * time.Millisecond
+
+	hanging := &hangingMonitorTest{
+		fakeConstruction:  fakeConstruction{source: "Hanging"},
+		release:           make(chan struct{}),
+		collectedInterval: monitorapi.NewInterval("Hanging", monitorapi.Info).Build(time.Unix(1, 0), time.Unix(2, 0)),
+	}
+	defer close(hanging.release)
+	other := &fakeConstruction{source: "Other"}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("hanging", "Test Framework", hanging)
+	registry.AddMonitorTestOrDie("other", "Test Framework", other)
+	registry.SetPhaseTimeouts(PhaseTimeouts{
+		Default:      map[Phase]time.Duration{PhaseStartCollection: 50 * time.Millisecond},
+		MonitorTests: map[string]map[Phase]time.Duration{"hanging": {PhaseCollectData: 50 * time.Millisecond}},
+	})
+
+	ctx := context.Background()
+	if _, err := registry.StartCollection(ctx, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := hanging.collectionCtx.Err(); err != nil {
+		t.Errorf("expected the collection context to outlive the StartCollection timeout, got %v", err)
+	}
+
+	intervals, junits, _ := registry.CollectData(ctx, "", time.Unix(0, 0), time.Unix(10, 0))
+	if len(intervals) != 0 {
+		t.Errorf("expected the intervals of the abandoned monitor test to be dropped, got %v", intervals)
+	}
+	var timedOut, passed bool
+	for _, junit := range junits {
+		switch {
+		case junit.Name == `[Jira:"Test Framework"] monitor test hanging collection` && junit.FailureOutput != nil:
+			timedOut = strings.Contains(junit.FailureOutput.Output, `monitor test "hanging" did not finish CollectData within 50ms`)
+		case junit.Name == `[Jira:"Test Framework"] monitor test other collection` && junit.FailureOutput == nil:
+			passed = true
+		}
+	}
+	if !timedOut || !passed {
+		t.Errorf("expected a timeout failure for hanging and a success for other, got %#v", junits)
+	}
+
+	storageDir := t.TempDir()
+	if _, err := registry.WriteContentToStorage(ctx, storageDir, "_suffix", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(storageDir, "monitor-test-phase-timings_suffix-"+dataloader.AutoDataLoaderSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataFile := dataloader.DataFile{}
+	if err := json.Unmarshal(content, &dataFile); err != nil {
+		t.Fatal(err)
+	}
+	// two monitor tests, three phases each.
+	if len(dataFile.Rows) != 6 {
+		t.Errorf("expected 6 timings, got %d", len(dataFile.Rows))
+	}
+	for _, row := range dataFile.Rows {
+		if row["MonitorTest"] == "hanging" && row["Phase"] == string(PhaseCollectData) && row["Result"] != "TimedOut" {
+			t.Errorf("expected the hanging collection to be recorded as timed out, got %v", row)
+		}
+	}
+}
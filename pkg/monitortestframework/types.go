@@ -9,6 +9,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
@@ -34,6 +35,12 @@ type MonitorTestInitializationInfo struct {
 
 	// DisableMonitorTests will remove any monitor tests contained in the provided list
 	DisableMonitorTests []string
+
+	// PhaseTimeouts overrides how long each lifecycle method of the monitor tests may run.
+	PhaseTimeouts PhaseTimeouts
+
+	// WriteParquet writes the intervals and resources as parquet files in addition to the JSON files.
+	WriteParquet bool
 }
 
 type MonitorTest interface {
@@ -48,7 +55,8 @@ type MonitorTest interface {
 	CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
 
 	// ConstructComputedIntervals is called after all InvariantTests have produced raw Intervals.
-	// Order of ConstructComputedIntervals across different InvariantTests is not guaranteed.
+	// Order of ConstructComputedIntervals across different InvariantTests is not guaranteed unless the
+	// InvariantTest implements DependentMonitorTest.
 	// Return *only* the constructed intervals.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (constructedIntervals monitorapi.Intervals, err error)
@@ -74,6 +82,47 @@ type MonitorTest interface {
 	Cleanup(ctx context.Context) error
 }
 
+// ReplayableMonitorTest is implemented by MonitorTests that can be replayed against saved artifacts.  There is no
+// cluster then, so neither StartCollection nor CollectData is called.  PrepareForReplay is called instead with the
+// cluster data saved by the original run, and must set whatever the later stages use, they must not need clients.
+// MonitorTests that only look at the intervals and resources they are given do not need to implement it.
+type ReplayableMonitorTest interface {
+	// PrepareForReplay is called once before ConstructComputedIntervals.  beginning and end bound the saved intervals.
+	// Errors reported will be indicated as junit test failure.
+	PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) error
+}
+
+// NonReplayableMonitorTest is implemented by MonitorTests whose later stages use the clients or state set by
+// StartCollection, for instance to query the cluster while evaluating.  They are skipped during replay.
+type NonReplayableMonitorTest interface {
+	// NotReplayableReason is reported as the skip message of the replay preparation junit.
+	NotReplayableReason() string
+}
+
+// ConstructionDependencies lists what a MonitorTest needs before its ConstructComputedIntervals can run.
+type ConstructionDependencies struct {
+	// MonitorTests are the names of the monitor tests whose computed intervals are needed.  Monitor tests that are
+	// not part of the registry, for instance because they are disabled, are ignored.
+	MonitorTests []string
+	// IntervalSources are the sources of the computed intervals that are needed.  Every monitor test declaring one of
+	// these sources with ComputedIntervalSourceProvider becomes a dependency.
+	IntervalSources []monitorapi.IntervalSource
+}
+
+// DependentMonitorTest is optionally implemented by MonitorTests that build on intervals computed by other
+// MonitorTests.  ConstructComputedIntervals of such a MonitorTest is called once all its dependencies are done, and
+// its startingIntervals also contain the intervals computed by its dependencies, transitively.  Dependency cycles are
+// reported as junit failures of the interval construction of every MonitorTest in or after the cycle.
+type DependentMonitorTest interface {
+	ConstructionDependencies() ConstructionDependencies
+}
+
+// ComputedIntervalSourceProvider is optionally implemented by MonitorTests to declare the sources of the intervals
+// returned by their ConstructComputedIntervals, so that other MonitorTests can depend on those sources.
+type ComputedIntervalSourceProvider interface {
+	ComputedIntervalSources() []monitorapi.IntervalSource
+}
+
 type MonitorTestRegistry interface {
 	AddRegistryOrDie(registry MonitorTestRegistry)
 
@@ -86,6 +135,13 @@ type MonitorTestRegistry interface {
 	GetRegistryFor(names ...string) (MonitorTestRegistry, error)
 	ListMonitorTests() sets.String
 
+	// SetPhaseTimeouts sets the deadlines of the lifecycle methods of the monitor tests.  A method that does not
+	// return in time is abandoned and reported as a junit failure, the remaining monitor tests carry on.
+	SetPhaseTimeouts(timeouts PhaseTimeouts)
+	// PhaseTimings returns the time taken by every lifecycle method called so far.  The timings are also written to
+	// storage by WriteContentToStorage.
+	PhaseTimings() []PhaseTiming
+
 	// StartCollection is responsible for setting up all resources required for collection of data on the cluster.
 	// An error will not stop execution, but will cause a junit failure that will cause the job run to fail.
 	// This allows us to know when setups fail.
@@ -95,8 +151,15 @@ type MonitorTestRegistry interface {
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
 
+	// PrepareForReplay is called in place of StartCollection and CollectData when the monitor tests are run against
+	// saved artifacts instead of a live cluster.  MonitorTests implementing ReplayableMonitorTest are prepared, those
+	// implementing NonReplayableMonitorTest are reported as skipped and removed from the registry, the others are
+	// replayed as they are.
+	PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) ([]*junitapi.JUnitTestCase, error)
+
 	// ConstructComputedIntervals is called after all InvariantTests have produced raw Intervals.
-	// Order of ConstructComputedIntervals across different InvariantTests is not guaranteed.
+	// InvariantTests run in parallel, ordered by the dependencies declared through DependentMonitorTest.  The
+	// startingIntervals are shared by the InvariantTests and must not be modified, sort a copy instead.
 	// Return *only* the constructed intervals.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
@@ -3,6 +3,7 @@ package allowedbackenddisruption
 import (
 	"time"
 
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 )
 
@@ -16,9 +17,9 @@ const (
 	allowedExternalDisruption = 600 * time.Second
 )
 
-// GetAllowedDisruption uses the backend and information about the cluster to choose the best historical p99 to
-// operate against.
+// GetAllowedDisruption uses the backend and information about the cluster to choose the best historical allowance to
+// operate against, using the historicaldata.DefaultAllowanceModels.
 // We enforce "don't get worse" for disruption by watching the aggregate data in CI over many runs.
-func GetAllowedDisruption(backendName string, jobType platformidentification.JobType) (*time.Duration, string, error) {
-	return GetCurrentResults().BestMatchP99(backendName, jobType)
+func GetAllowedDisruption(backendName string, jobType platformidentification.JobType) (*historicaldata.DisruptionAllowance, string, error) {
+	return GetCurrentResults().BestAllowance(backendName, jobType)
 }
@@ -253,3 +253,39 @@ func TestDisruptionDataFileParsing(t *testing.T) {
 	assert.NotEqual(t, percentiles, historicaldata.StatisticalDuration{}, "BestMatchDuration found no match and could not fall back for kube-api-new-connections aws amd64 ovn ha")
 	assert.NoError(t, err)
 }
+
+// TestFallbackChain relaxes job types absent from the query_results.json data file until they match one in it.
+func TestFallbackChain(t *testing.T) {
+	releasesInQueryResults := map[string]bool{}
+	for _, v := range GetCurrentResults().HistoricalData {
+		releasesInQueryResults[v.Release] = true
+	}
+	currentRelease := historicaldata.CurrentReleaseFromMap(releasesInQueryResults)
+
+	// no job runs on this architecture nor platform.
+	jobType := platformidentification.JobType{
+		Release:      currentRelease,
+		FromRelease:  currentRelease,
+		Platform:     "aws-upi",
+		Architecture: "riscv64",
+		Network:      "ovn",
+		Topology:     "ha",
+	}
+	matcher := historicaldata.NewDisruptionMatcherWithHistoricalData(GetCurrentResults().HistoricalData)
+	percentiles, details, err := matcher.BestMatchDuration("kube-api-new-connections", jobType, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, historicaldata.StatisticalDuration{}, percentiles, "expected the default chain not to match")
+	assert.Contains(t, details, "no exact or fuzzy match")
+
+	matcher.FallbackChain = historicaldata.FallbackChain{
+		historicaldata.RelaxToAMD64,
+		{Name: "merge aws-upi", Relax: historicaldata.MapPlatform(map[string]string{"aws-upi": "aws"}, "")},
+		historicaldata.RelaxToPreviousRelease,
+	}
+	percentiles, details, err = matcher.BestMatchDuration("kube-api-new-connections", jobType, 100)
+	assert.NoError(t, err)
+	assert.NotEqual(t, historicaldata.StatisticalDuration{}, percentiles, "expected the chain to match: %s", details)
+	assert.Equal(t, "amd64", percentiles.Architecture)
+	assert.Equal(t, "aws", percentiles.Platform)
+	assert.Contains(t, details, "by relaxing architecture amd64, then merge aws-upi")
+}
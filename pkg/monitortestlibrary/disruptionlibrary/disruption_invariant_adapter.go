@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/monitor/backenddisruption"
@@ -101,7 +102,7 @@ func (w *Availability) CollectData(ctx context.Context) (monitorapi.Intervals, [
 
 func createDisruptionJunit(
 	testName string,
-	allowedDisruption *time.Duration,
+	allowance *historicaldata.DisruptionAllowance,
 	disruptionDetails string,
 	locator monitorapi.Locator,
 	disruptedIntervals monitorapi.Intervals,
@@ -120,7 +121,7 @@ func createDisruptionJunit(
 	// Indicates there is no entry in the query_results.json data file, nor a valid fallback,
 	// we do not wish to run the test. (this likely implies we do not have the required number of
 	// runs in 3 weeks to do a reliable P99)
-	if allowedDisruption == nil {
+	if allowance == nil {
 		return &junitapi.JUnitTestCase{
 			Name: testName,
 			SkipMessage: &junitapi.SkipMessage{
@@ -128,6 +129,7 @@ func createDisruptionJunit(
 			},
 		}
 	}
+	allowedDisruption := &allowance.Allowed
 
 	disruptionDuration := disruptedIntervals.Duration(1 * time.Second)
 	roundedDisruptionDuration := disruptionDuration.Round(time.Second)
@@ -138,12 +140,11 @@ func createDisruptionJunit(
 	// establish this as a first line of defence to detect egregious regressions before they merge.
 	//roundedAllowedDisruption, additionalDetails := calculateAllowedDisruptionWithGrace(*allowedDisruption)
 	allowedDetails := []string{}
-	allowedDetails = append(allowedDetails, fmt.Sprintf("P99 from historical data for similar jobs over past 3 weeks: %s",
-		*allowedDisruption))
+	allowedDetails = append(allowedDetails, allowance.String())
 	if *allowedDisruption < 1*time.Second {
 		t := 1 * time.Second
 		allowedDisruption = &t
-		allowedDetails = append(allowedDetails, "rounded P99 up to always allow one second")
+		allowedDetails = append(allowedDetails, "rounded allowance up to always allow one second")
 	}
 
 	// Allow grace of 5s or 20%, at this layer, with one sample, we're only hoping to find really severe disruption:
@@ -162,6 +163,8 @@ func createDisruptionJunit(
 	if roundedDisruptionDuration <= finalAllowedDisruption {
 		return &junitapi.JUnitTestCase{
 			Name: testName,
+			SystemOut: fmt.Sprintf("%v was unreachable for %s (maxAllowed=%s):\n%s", locator.OldLocator(),
+				roundedDisruptionDuration, finalAllowedDisruption, strings.Join(allowedDetails, "\n")),
 		}
 	}
 
@@ -217,7 +220,34 @@ func (w *Availability) junitForReusedConnections(ctx context.Context, finalInter
 		nil
 }
 
-func historicalAllowedDisruption(ctx context.Context, backend *backenddisruption.BackendSampler, jobType *platformidentification.JobType) (*time.Duration, string, error) {
+// CreatePollerDisruptionJunit evaluates a backend sampled by pollers running in the cluster instead of by a
+// BackendSampler.  The pollers on every node record intervals for the same backend, their error intervals are summed
+// the same way the backend disruption data used for the historical allowances is.
+func CreatePollerDisruptionJunit(testName, backendName string, finalIntervals monitorapi.Intervals, jobType *platformidentification.JobType) (*junitapi.JUnitTestCase, error) {
+	allowance, disruptionDetails, err := allowedbackenddisruption.GetAllowedDisruption(backendName, *jobType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get allowed disruption for %s: %w", backendName, err)
+	}
+	locator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeDisruption,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorBackendDisruptionNameKey: backendName,
+		},
+	}
+	return createDisruptionJunit(
+			testName, allowance, disruptionDetails, locator,
+			finalIntervals.Filter(
+				monitorapi.And(
+					monitorapi.IsEventForBackendDisruptionName(backendName),
+					monitorapi.IsErrorEvent,
+				),
+			),
+			jobType,
+		),
+		nil
+}
+
+func historicalAllowedDisruption(ctx context.Context, backend *backenddisruption.BackendSampler, jobType *platformidentification.JobType) (*historicaldata.DisruptionAllowance, string, error) {
 	return allowedbackenddisruption.GetAllowedDisruption(backend.GetDisruptionBackendName(), *jobType)
 }
 
@@ -0,0 +1,82 @@
+package disruptionlibrary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+// StreamAvailability is the Availability of a long-lived stream, checked by a single StreamSampler.
+type StreamAvailability struct {
+	testName string
+
+	// store the rest config so we can
+	// get the JobType at the end of the run
+	// which will include any upgrade versions
+	adminRESTConfig *rest.Config
+
+	disruptionSampler *backenddisruption.StreamSampler
+}
+
+func NewStreamAvailabilityInvariant(testName string, disruptionSampler *backenddisruption.StreamSampler) *StreamAvailability {
+	return &StreamAvailability{
+		testName:          testName,
+		disruptionSampler: disruptionSampler,
+	}
+}
+
+func (w *StreamAvailability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	if w == nil {
+		return fmt.Errorf("unable to start collection because instance is nil")
+	}
+
+	w.adminRESTConfig = adminRESTConfig
+	return w.disruptionSampler.StartEndpointMonitoring(ctx, recorder, nil)
+}
+
+func (w *StreamAvailability) CollectData(ctx context.Context) (_ monitorapi.Intervals, _ []*junitapi.JUnitTestCase, err error) {
+	if w == nil {
+		return nil, nil, fmt.Errorf("unable to collected data because instance is nil")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in stop: %v", r)
+		}
+	}()
+	w.disruptionSampler.Stop()
+	return nil, nil, nil
+}
+
+func (w *StreamAvailability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w == nil {
+		return nil, fmt.Errorf("unable to evaluate tests because instance is nil")
+	}
+
+	jobType, err := platformidentification.GetJobType(ctx, w.adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	allowed, disruptionDetails, err := allowedbackenddisruption.GetAllowedDisruption(w.disruptionSampler.GetDisruptionBackendName(), *jobType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get stream allowed disruption: %w", err)
+	}
+	return []*junitapi.JUnitTestCase{
+		createDisruptionJunit(
+			w.testName, allowed, disruptionDetails, w.disruptionSampler.GetLocator(),
+			finalIntervals.Filter(
+				monitorapi.And(
+					monitorapi.IsEventForLocator(w.disruptionSampler.GetLocator()),
+					monitorapi.IsErrorEvent,
+				),
+			),
+			jobType,
+		),
+	}, nil
+}
@@ -3,7 +3,6 @@ package historicaldata
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"time"
 
@@ -33,6 +32,8 @@ type AlertDataKey struct {
 
 type AlertBestMatcher struct {
 	HistoricalData map[AlertDataKey]AlertStatisticalData
+	// FallbackChain is tried when the JobType has no historical data, DefaultFallbackChain when nil.
+	FallbackChain FallbackChain
 }
 
 func NewAlertMatcher(historicalJSON []byte) (*AlertBestMatcher, error) {
@@ -82,34 +83,30 @@ func NewAlertMatcherWithHistoricalData(data map[AlertDataKey]AlertStatisticalDat
 	}
 }
 
+func (b *AlertBestMatcher) fallbackChain() FallbackChain {
+	if b.FallbackChain == nil {
+		return DefaultFallbackChain
+	}
+	return b.FallbackChain
+}
+
 func (b *AlertBestMatcher) bestMatch(key AlertDataKey) (AlertStatisticalData, string, error) {
-	exactMatchKey := key
 	logrus.WithField("alertName", key.AlertName).WithField("entries", len(b.HistoricalData)).
 		Debugf("searching for best match for %+v", key.JobType)
 
-	if percentiles, ok := b.HistoricalData[exactMatchKey]; ok {
-		if percentiles.JobRuns >= defaultMinJobRuns {
-			logrus.Infof("found exact match: %+v", percentiles)
-			return percentiles, "", nil
-		}
-		percentiles, matchReason, err := b.evaluateBestGuesser(PreviousReleaseUpgrade, exactMatchKey)
-		if err != nil {
-			return AlertStatisticalData{}, "", err
-		}
-		if percentiles != (AlertStatisticalData{}) {
-			return percentiles, matchReason, nil
-		}
-	}
-
-	// tested in TestGetClosestP95Value in allowedbackendisruption.  Should get a local test at some point.
-	for _, nextBestGuesser := range nextBestGuessers {
-		percentiles, matchReason, err := b.evaluateBestGuesser(nextBestGuesser, exactMatchKey)
-		if err != nil {
-			return AlertStatisticalData{}, "", err
-		}
-		if percentiles != (AlertStatisticalData{}) {
-			return percentiles, matchReason, nil
+	var percentiles AlertStatisticalData
+	matchReason, found := b.fallbackChain().matchWithFallback(key.JobType, func(candidate platformidentification.JobType, exact bool) bool {
+		candidateKey := key
+		candidateKey.JobType = candidate
+		curr, ok := b.HistoricalData[candidateKey]
+		if !ok || curr.JobRuns < defaultMinJobRuns {
+			return false
 		}
+		percentiles = curr
+		return true
+	})
+	if found {
+		return percentiles, matchReason, nil
 	}
 
 	// TODO: ensure our core platforms are here, error if not. We need to be sure our aggregated jobs are running this
@@ -118,27 +115,7 @@ func (b *AlertBestMatcher) bestMatch(key AlertDataKey) (AlertStatisticalData, st
 	// We now only track disruption data for frequently run jobs where we have enough runs to make a reliable P95 or P99
 	// determination. If we did not record historical data for this NURP combination, we do not wish to enforce
 	// disruption testing on a per job basis. Return an empty data result to signal we have no data, and skip the test.
-	return AlertStatisticalData{},
-		fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", key.JobType),
-		nil
-}
-
-func (b *AlertBestMatcher) evaluateBestGuesser(nextBestGuesser NextBestKey, exactMatchKey AlertDataKey) (AlertStatisticalData, string, error) {
-	nextBestJobType, ok := nextBestGuesser(exactMatchKey.JobType)
-	if !ok {
-		return AlertStatisticalData{}, "", nil
-	}
-	nextBestMatchKey := AlertDataKey{
-		AlertName:      exactMatchKey.AlertName,
-		AlertNamespace: exactMatchKey.AlertNamespace,
-		AlertLevel:     exactMatchKey.AlertLevel,
-
-		JobType: nextBestJobType,
-	}
-	if percentiles, ok := b.HistoricalData[nextBestMatchKey]; ok && percentiles.JobRuns >= defaultMinJobRuns {
-		return percentiles, fmt.Sprintf("(no exact match for %#v, fell back to %#v)", exactMatchKey, nextBestMatchKey), nil
-	}
-	return AlertStatisticalData{}, "", nil
+	return AlertStatisticalData{}, matchReason, nil
 }
 
 // BestMatchDuration returns the best possible match for this historical data.  It attempts an exact match first, then
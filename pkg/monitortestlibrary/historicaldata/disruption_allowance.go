@@ -0,0 +1,191 @@
+package historicaldata
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+// DisruptionAllowance is the disruption a backend is allowed on a job type, as computed by an AllowanceModel.
+type DisruptionAllowance struct {
+	// Model is the name of the AllowanceModel that computed the allowance.
+	Model   string
+	Allowed time.Duration
+	// Confidence ranges from 0 to 1, how much of the allowance is backed by runs of the job type itself.
+	Confidence float64
+	// Details explains where the allowance comes from.
+	Details string
+}
+
+func (a DisruptionAllowance) String() string {
+	return fmt.Sprintf("allowance model %s allowed %s with confidence %.2f: %s", a.Model, a.Allowed, a.Confidence, a.Details)
+}
+
+// AllowanceModel computes the disruption allowed for a backend on a job type from the historical data.
+type AllowanceModel interface {
+	Name() string
+	// Allowance returns nil and the reason when the model has no allowance for the backend and job type, in which
+	// case the next model is tried.
+	Allowance(data *DisruptionBestMatcher, backendName string, jobType platformidentification.JobType) (*DisruptionAllowance, string, error)
+}
+
+// DefaultAllowanceModels keeps the P99 of job types with enough runs.  Job types with too few runs for a P99 get a
+// robust allowance from their own P50 and P75 when they have some, or their P99 shrunk toward their platform, instead
+// of being skipped.
+var DefaultAllowanceModels = []AllowanceModel{
+	PercentileAllowanceModel{},
+	RobustAllowanceModel{},
+	BayesianPlatformPriorAllowanceModel{},
+}
+
+// BestAllowance returns the allowance of the first model that has one, or nil and the reasons of every model when
+// none has.  DefaultAllowanceModels are used when no model is given.
+func (b *DisruptionBestMatcher) BestAllowance(backendName string, jobType platformidentification.JobType, models ...AllowanceModel) (*DisruptionAllowance, string, error) {
+	if len(models) == 0 {
+		models = DefaultAllowanceModels
+	}
+	reasons := ""
+	for _, model := range models {
+		allowance, reason, err := model.Allowance(b, backendName, jobType)
+		if err != nil {
+			return nil, "", fmt.Errorf("allowance model %s failed: %w", model.Name(), err)
+		}
+		if allowance != nil {
+			return allowance, allowance.Details, nil
+		}
+		reasons += fmt.Sprintf("%s: %s\n", model.Name(), reason)
+	}
+	return nil, reasons, nil
+}
+
+// runsConfidence is the confidence in a statistic computed from jobRuns runs, full at defaultMinJobRuns.
+func runsConfidence(jobRuns int64) float64 {
+	return math.Min(1, float64(jobRuns)/defaultMinJobRuns)
+}
+
+// PercentileAllowanceModel allows the P99 of the job type, falling back to the previous release, when it has at
+// least defaultMinJobRuns runs.
+type PercentileAllowanceModel struct{}
+
+func (PercentileAllowanceModel) Name() string {
+	return "percentile"
+}
+
+func (m PercentileAllowanceModel) Allowance(data *DisruptionBestMatcher, backendName string, jobType platformidentification.JobType) (*DisruptionAllowance, string, error) {
+	percentiles, matchReason, err := data.bestMatch(backendName, jobType, defaultMinJobRuns)
+	if err != nil {
+		return nil, "", err
+	}
+	if percentiles == (DisruptionStatisticalData{}) {
+		return nil, matchReason, nil
+	}
+	details := fmt.Sprintf("P99 from historical data for similar jobs over past 3 weeks over %d job runs", percentiles.JobRuns)
+	if len(matchReason) > 0 {
+		details += " " + matchReason
+	}
+	return &DisruptionAllowance{
+		Model:      m.Name(),
+		Allowed:    DurationOrDie(percentiles.P99),
+		Confidence: runsConfidence(percentiles.JobRuns),
+		Details:    details,
+	}, "", nil
+}
+
+// RobustAllowanceModel allows a trimmed mean plus K scaled median absolute deviations, which outliers in the
+// historical data move far less than they move the P99.  The historical data only has percentiles, so the
+// trimmed mean is estimated as the mean of the P50, P75 and P95, the percentiles left by trimming 5% on each side,
+// and the median absolute deviation as P75-P50, the deviation of the quartile of a symmetric distribution.
+type RobustAllowanceModel struct {
+	// K is the number of scaled median absolute deviations allowed above the trimmed mean, 3 when zero.
+	K float64
+	// MinJobRuns is the number of runs the job type needs, 10 when zero.
+	MinJobRuns int64
+}
+
+func (RobustAllowanceModel) Name() string {
+	return "trimmed-mean-mad"
+}
+
+func (m RobustAllowanceModel) Allowance(data *DisruptionBestMatcher, backendName string, jobType platformidentification.JobType) (*DisruptionAllowance, string, error) {
+	k := m.K
+	if k == 0 {
+		k = 3
+	}
+	minJobRuns := m.MinJobRuns
+	if minJobRuns == 0 {
+		minJobRuns = 10
+	}
+
+	percentiles, ok := data.HistoricalData[DataKey{BackendName: backendName, JobType: jobType}]
+	if !ok || percentiles.JobRuns < minJobRuns {
+		return nil, fmt.Sprintf("(fewer than %d job runs for jobType=%#v)", minJobRuns, jobType), nil
+	}
+	// older data files have no P50 and P75, and a P75 of zero leaves no deviation to scale.
+	if percentiles.P75 <= percentiles.P50 {
+		return nil, fmt.Sprintf("(no spread between the P50 and the P75 for jobType=%#v)", jobType), nil
+	}
+
+	trimmedMean := (percentiles.P50 + percentiles.P75 + percentiles.P95) / 3
+	// 1.4826 scales the median absolute deviation to the standard deviation of a normal distribution.
+	scaledMAD := 1.4826 * (percentiles.P75 - percentiles.P50)
+	return &DisruptionAllowance{
+		Model:      m.Name(),
+		Allowed:    DurationOrDie(trimmedMean + k*scaledMAD),
+		Confidence: runsConfidence(percentiles.JobRuns),
+		Details: fmt.Sprintf("trimmed mean %.3fs plus %g scaled median absolute deviations of %.3fs over %d job runs",
+			trimmedMean, k, scaledMAD, percentiles.JobRuns),
+	}, "", nil
+}
+
+// BayesianPlatformPriorAllowanceModel allows the P99 of the job type shrunk toward the P99 of its platform, the
+// average over every job type of the release and platform weighted by job runs.  The platform counts as
+// PriorJobRuns runs of the job type, so job types with few runs get about the P99 of their platform, while job
+// types with many runs keep about their own P99.
+type BayesianPlatformPriorAllowanceModel struct {
+	// PriorJobRuns is the weight of the platform P99, defaultMinJobRuns when zero.
+	PriorJobRuns int64
+}
+
+func (BayesianPlatformPriorAllowanceModel) Name() string {
+	return "bayesian-platform-prior"
+}
+
+func (m BayesianPlatformPriorAllowanceModel) Allowance(data *DisruptionBestMatcher, backendName string, jobType platformidentification.JobType) (*DisruptionAllowance, string, error) {
+	priorJobRuns := m.PriorJobRuns
+	if priorJobRuns == 0 {
+		priorJobRuns = defaultMinJobRuns
+	}
+
+	percentiles, ok := data.HistoricalData[DataKey{BackendName: backendName, JobType: jobType}]
+	if !ok || percentiles.JobRuns == 0 {
+		return nil, fmt.Sprintf("(no job runs for jobType=%#v)", jobType), nil
+	}
+
+	var platformJobRuns int64
+	var platformP99Sum float64
+	for key, curr := range data.HistoricalData {
+		if key.BackendName != backendName || key.Release != jobType.Release || key.Platform != jobType.Platform {
+			continue
+		}
+		platformJobRuns += curr.JobRuns
+		platformP99Sum += curr.P99 * float64(curr.JobRuns)
+	}
+	// the platform must have enough runs to be worth shrinking toward.
+	if platformJobRuns < defaultMinJobRuns {
+		return nil, fmt.Sprintf("(fewer than %d job runs for platform %q in release %q)", defaultMinJobRuns, jobType.Platform, jobType.Release), nil
+	}
+	platformP99 := platformP99Sum / float64(platformJobRuns)
+
+	jobRuns := float64(percentiles.JobRuns)
+	weight := jobRuns / (jobRuns + float64(priorJobRuns))
+	posterior := weight*percentiles.P99 + (1-weight)*platformP99
+	return &DisruptionAllowance{
+		Model:      m.Name(),
+		Allowed:    DurationOrDie(posterior),
+		Confidence: weight,
+		Details: fmt.Sprintf("P99 %.3fs over %d job runs shrunk toward the P99 %.3fs of platform %q over %d job runs",
+			percentiles.P99, percentiles.JobRuns, platformP99, jobType.Platform, platformJobRuns),
+	}, "", nil
+}
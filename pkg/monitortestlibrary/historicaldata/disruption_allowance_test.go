@@ -0,0 +1,98 @@
+package historicaldata
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func TestBestAllowance(t *testing.T) {
+	awsHA := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	awsSingle := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "single"}
+	awsSDN := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws", Architecture: "amd64", Network: "sdn", Topology: "ha"}
+	gcpHA := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "gcp", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	azureHA := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "azure", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	backend := "kube-api-new-connections"
+
+	matcher := NewDisruptionMatcherWithHistoricalData(map[DataKey]DisruptionStatisticalData{
+		{BackendName: backend, JobType: awsHA}: {
+			DataKey: DataKey{BackendName: backend, JobType: awsHA}, P50: 1, P75: 2, P95: 6, P99: 10, JobRuns: 300,
+		},
+		{BackendName: backend, JobType: awsSingle}: {
+			DataKey: DataKey{BackendName: backend, JobType: awsSingle}, P50: 20, P75: 30, P95: 60, P99: 90, JobRuns: 100,
+		},
+		{BackendName: backend, JobType: awsSDN}: {
+			DataKey: DataKey{BackendName: backend, JobType: awsSDN}, P99: 4, JobRuns: 50,
+		},
+		{BackendName: backend, JobType: gcpHA}: {
+			DataKey: DataKey{BackendName: backend, JobType: gcpHA}, P99: 4, JobRuns: 20,
+		},
+		{BackendName: backend, JobType: azureHA}: {
+			DataKey: DataKey{BackendName: backend, JobType: azureHA}, P50: 1, P75: 2, P95: 6, P99: 10, JobRuns: 50,
+		},
+	})
+
+	tests := []struct {
+		name           string
+		jobType        platformidentification.JobType
+		wantModel      string
+		wantAllowed    time.Duration
+		wantConfidence float64
+	}{
+		{
+			name:           "enough runs uses the percentile",
+			jobType:        awsHA,
+			wantModel:      "percentile",
+			wantAllowed:    10 * time.Second,
+			wantConfidence: 1,
+		},
+		{
+			// the trimmed mean is (1+2+6)/3, plus 3 times 1.4826*(2-1).
+			name:           "too few runs with a spread uses the robust model",
+			jobType:        azureHA,
+			wantModel:      "trimmed-mean-mad",
+			wantAllowed:    7448 * time.Millisecond,
+			wantConfidence: 0.5,
+		},
+		{
+			// the aws P99 is (10*300 + 90*100 + 4*50) / 450, the job type counts for 50 of 150 runs.
+			name:           "too few runs shrinks toward the platform",
+			jobType:        awsSDN,
+			wantModel:      "bayesian-platform-prior",
+			wantAllowed:    DurationOrDie((4*50 + 12200.0/450*100) / 150),
+			wantConfidence: 50.0 / 150,
+		},
+		{
+			name:           "platform with too few runs is skipped",
+			jobType:        gcpHA,
+			wantModel:      "",
+			wantAllowed:    0,
+			wantConfidence: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowance, reason, err := matcher.BestAllowance(backend, tt.jobType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.wantModel) == 0 {
+				if allowance != nil {
+					t.Fatalf("expected no allowance, got %v", allowance)
+				}
+				if !strings.Contains(reason, "bayesian-platform-prior") {
+					t.Errorf("expected the reason of every model, got %q", reason)
+				}
+				return
+			}
+			if allowance == nil {
+				t.Fatalf("expected an allowance, got none: %s", reason)
+			}
+			if allowance.Model != tt.wantModel || allowance.Allowed != tt.wantAllowed || allowance.Confidence != tt.wantConfidence {
+				t.Errorf("expected %s to allow %s with confidence %v, got %v", tt.wantModel, tt.wantAllowed, tt.wantConfidence, allowance)
+			}
+		})
+	}
+}
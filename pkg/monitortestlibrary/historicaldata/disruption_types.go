@@ -48,6 +48,8 @@ type DataKey struct {
 
 type DisruptionBestMatcher struct {
 	HistoricalData map[DataKey]DisruptionStatisticalData
+	// FallbackChain is tried when the JobType has no historical data, DefaultFallbackChain when nil.
+	FallbackChain FallbackChain
 }
 
 func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error) {
@@ -58,6 +60,8 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 
 	type DecodingPercentile struct {
 		DataKey `json:",inline"`
+		P50     string
+		P75     string
 		P95     string
 		P99     string
 		JobRuns int64
@@ -69,6 +73,15 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 	}
 
 	for _, currDecoded := range decodingPercentilesList {
+		// older data files only have the P95 and the P99.
+		p50, err := parseOptionalFloat(currDecoded.P50)
+		if err != nil {
+			return nil, err
+		}
+		p75, err := parseOptionalFloat(currDecoded.P75)
+		if err != nil {
+			return nil, err
+		}
 		p95, err := strconv.ParseFloat(currDecoded.P95, 64)
 		if err != nil {
 			return nil, err
@@ -79,6 +92,8 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 		}
 		curr := DisruptionStatisticalData{
 			DataKey: currDecoded.DataKey,
+			P50:     p50,
+			P75:     p75,
 			P95:     p95,
 			P99:     p99,
 			JobRuns: currDecoded.JobRuns,
@@ -91,42 +106,47 @@ func NewDisruptionMatcher(historicalJSON []byte) (*DisruptionBestMatcher, error)
 	}, nil
 }
 
+func parseOptionalFloat(value string) (float64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
 func NewDisruptionMatcherWithHistoricalData(data map[DataKey]DisruptionStatisticalData) *DisruptionBestMatcher {
 	return &DisruptionBestMatcher{
 		HistoricalData: data,
 	}
 }
 
-func (b *DisruptionBestMatcher) bestMatch(name string, jobType platformidentification.JobType, minJobRuns int) (DisruptionStatisticalData, string, error) {
-	exactMatchKey := DataKey{
-		BackendName: name,
-		JobType:     jobType,
+func (b *DisruptionBestMatcher) fallbackChain() FallbackChain {
+	if b.FallbackChain == nil {
+		return DefaultFallbackChain
 	}
+	return b.FallbackChain
+}
+
+func (b *DisruptionBestMatcher) bestMatch(name string, jobType platformidentification.JobType, minJobRuns int) (DisruptionStatisticalData, string, error) {
 	logrus.WithField("backend", name).Infof("searching for bestMatch for %+v", jobType)
 	logrus.Infof("historicalData has %d entries", len(b.HistoricalData))
-	if percentiles, ok := b.HistoricalData[exactMatchKey]; ok {
-		if percentiles.JobRuns >= int64(minJobRuns) {
-			logrus.Infof("found exact match: %+v", percentiles)
-			return percentiles, "", nil
-		}
 
-		percentiles, matchReason, err := b.evaluateBestGuesser(name, PreviousReleaseUpgrade, exactMatchKey, jobType)
-		if err != nil {
-			return DisruptionStatisticalData{}, "", err
-		}
-		if percentiles != (DisruptionStatisticalData{}) {
-			return percentiles, matchReason, nil
-		}
-	}
-	// tested in TestGetClosestP99Value in allowedbackendisruption.  Should get a local test at some point.
-	for _, nextBestGuesser := range nextBestGuessers {
-		percentiles, matchReason, err := b.evaluateBestGuesser(name, nextBestGuesser, exactMatchKey, jobType)
-		if err != nil {
-			return DisruptionStatisticalData{}, "", err
+	// tested in TestGetClosestP99Value in allowedbackendisruption and against query_results.json in TestFallbackChain.
+	var percentiles DisruptionStatisticalData
+	matchReason, found := b.fallbackChain().matchWithFallback(jobType, func(candidate platformidentification.JobType, exact bool) bool {
+		curr, ok := b.HistoricalData[DataKey{BackendName: name, JobType: candidate}]
+		if !ok {
+			return false
 		}
-		if percentiles != (DisruptionStatisticalData{}) {
-			return percentiles, matchReason, nil
+		// a fallback always needs enough runs for a reliable P99, whatever the caller accepts for an exact match.
+		if (exact && curr.JobRuns < int64(minJobRuns)) || (!exact && curr.JobRuns <= defaultMinJobRuns) {
+			return false
 		}
+		percentiles = curr
+		return true
+	})
+	if found {
+		logrus.Infof("found match %s: %+v", matchReason, percentiles)
+		return percentiles, matchReason, nil
 	}
 
 	logrus.Warn("no exact or fuzzy match, no results will be returned, test will be skipped")
@@ -137,26 +157,7 @@ func (b *DisruptionBestMatcher) bestMatch(name string, jobType platformidentific
 	// We now only track disruption data for frequently run jobs where we have enough runs to make a reliable P95 or P99
 	// determination. If we did not record historical data for this NURP combination, we do not wish to enforce
 	// disruption testing on a per job basis. Return an empty data result to signal we have no data, and skip the test.
-	return DisruptionStatisticalData{},
-		fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", jobType),
-		nil
-}
-
-func (b *DisruptionBestMatcher) evaluateBestGuesser(name string, nextBestGuesser NextBestKey, exactMatchKey DataKey, jobType platformidentification.JobType) (DisruptionStatisticalData, string, error) {
-	nextBestJobType, ok := nextBestGuesser(jobType)
-	if !ok {
-		return DisruptionStatisticalData{}, "", nil
-	}
-	nextBestMatchKey := DataKey{
-		BackendName: name,
-		JobType:     nextBestJobType,
-	}
-	if percentiles, ok := b.HistoricalData[nextBestMatchKey]; ok && percentiles.JobRuns > defaultMinJobRuns {
-		logrus.Infof("no exact match fell back to %#v", nextBestMatchKey)
-		logrus.Infof("found inexact match: %+v", percentiles)
-		return percentiles, fmt.Sprintf("(no exact match for %#v, fell back to %#v)", exactMatchKey, nextBestMatchKey), nil
-	}
-	return DisruptionStatisticalData{}, "", nil
+	return DisruptionStatisticalData{}, matchReason, nil
 }
 
 // BestMatchDuration returns the best possible match for this historical data.  It attempts an exact match first, then
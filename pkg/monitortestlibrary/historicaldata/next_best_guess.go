@@ -9,22 +9,152 @@ import (
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 )
 
-// nextBestGuessers is the order in which to attempt to lookup other alternative matches that are close to this job type.
-var nextBestGuessers = []NextBestKey{
-	// The only guesser we try not is falling back to previous release. Otherwise if we don't have enough data, we don't
-	// run the test. This was implemented after finding that we fail every attempt at a fallback.
-	// Continuing with previous release helps us in the transition between major releases, so we kept this fallback.
-	PreviousReleaseUpgrade,
-}
-
 // NextBestKey returns the next best key in the query_results.json generated from BigQuery and a bool indicating whether this guesser has an opinion.
 // If the bool is false, the key should not be used.
 // Returning true doesn't mean the key exists, it just means that the key is worth trying.
 type NextBestKey func(in platformidentification.JobType) (platformidentification.JobType, bool)
 
+// JobTypeRelaxation is a step of a FallbackChain, it loosens the JobType used to look up historical data.
+type JobTypeRelaxation struct {
+	// Name describes the step in match reasons.
+	Name  string
+	Relax NextBestKey
+}
+
+var (
+	RelaxToPreviousRelease = JobTypeRelaxation{Name: "previous release", Relax: PreviousReleaseUpgrade}
+	RelaxToAMD64           = JobTypeRelaxation{Name: "architecture amd64", Relax: MapArchitecture(map[string]string{}, "amd64")}
+	RelaxHAToSingle        = JobTypeRelaxation{Name: "topology ha to single", Relax: MapTopology(map[string]string{"ha": "single"}, "")}
+	RelaxToDefaultNetwork  = JobTypeRelaxation{Name: "network ovn", Relax: MapNetwork(map[string]string{}, "ovn")}
+	RelaxMinorPlatforms    = JobTypeRelaxation{Name: "merge minor platforms", Relax: MapPlatform(MinorPlatforms, "")}
+)
+
+// FallbackRelaxations are the relaxations that ParseFallbackChain knows, by name.
+var FallbackRelaxations = map[string]JobTypeRelaxation{
+	"previous-release": RelaxToPreviousRelease,
+	"amd64":            RelaxToAMD64,
+	"ha-to-single":     RelaxHAToSingle,
+	"ovn":              RelaxToDefaultNetwork,
+	"minor-platforms":  RelaxMinorPlatforms,
+}
+
+// MinorPlatforms maps platforms with too few job runs to the platform closest to them.
+var MinorPlatforms = map[string]string{
+	"vsphere-upi": "vsphere",
+}
+
+// FallbackChain is the order in which JobTypes close to a JobType are tried when there is no historical data for
+// it.  Steps are cumulative, every step relaxes the JobType produced by the previous ones.
+type FallbackChain []JobTypeRelaxation
+
+// DefaultFallbackChain only falls back to the previous release.  Other fallbacks were dropped after finding that
+// we fail every attempt at a fallback, but falling back to the previous release helps us in the transition between
+// major releases, so we kept it.  The other relaxations remain available to matchers configured with a chain, and
+// to runs replacing the default with --historical-data-fallback.
+var DefaultFallbackChain = FallbackChain{RelaxToPreviousRelease}
+
+// ParseFallbackChain returns the chain of the FallbackRelaxations named, in order.
+func ParseFallbackChain(names []string) (FallbackChain, error) {
+	chain := FallbackChain{}
+	for _, name := range names {
+		relaxation, ok := FallbackRelaxations[name]
+		if !ok {
+			known := []string{}
+			for knownName := range FallbackRelaxations {
+				known = append(known, knownName)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown relaxation %q, expected one of %s", name, strings.Join(known, ", "))
+		}
+		chain = append(chain, relaxation)
+	}
+	return chain, nil
+}
+
+// fallbackCandidate is a JobType to try and the steps that produced it.
+type fallbackCandidate struct {
+	jobType platformidentification.JobType
+	steps   []string
+}
+
+// candidates returns the JobTypes to try after the exact one, skipping steps that have no opinion or change nothing.
+func (c FallbackChain) candidates(jobType platformidentification.JobType) []fallbackCandidate {
+	ret := []fallbackCandidate{}
+	current := jobType
+	steps := []string{}
+	for _, relaxation := range c {
+		next, ok := relaxation.Relax(current)
+		if !ok || next == current {
+			continue
+		}
+		current = next
+		steps = append(steps, relaxation.Name)
+		ret = append(ret, fallbackCandidate{jobType: current, steps: append([]string{}, steps...)})
+	}
+	return ret
+}
+
+// matchWithFallback looks up the exact JobType, then every candidate of the chain in order.  lookup reports whether it
+// found sufficient data for a JobType, exact tells whether the JobType is the one asked for.  The reason records the
+// steps taken to reach the JobType that matched, or that nothing did.
+func (c FallbackChain) matchWithFallback(jobType platformidentification.JobType, lookup func(jobType platformidentification.JobType, exact bool) bool) (string, bool) {
+	if lookup(jobType, true) {
+		return "", true
+	}
+	for _, candidate := range c.candidates(jobType) {
+		if lookup(candidate.jobType, false) {
+			return fmt.Sprintf("(no exact match for jobType=%#v, fell back to jobType=%#v by relaxing %s)",
+				jobType, candidate.jobType, strings.Join(candidate.steps, ", then ")), true
+		}
+	}
+	return fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", jobType), false
+}
+
+func mapJobTypeField(field func(jobType *platformidentification.JobType) *string, mapping map[string]string, defaultValue string) NextBestKey {
+	return func(in platformidentification.JobType) (platformidentification.JobType, bool) {
+		ret := platformidentification.CloneJobType(in)
+		value := field(&ret)
+		if mapped, ok := mapping[*value]; ok {
+			*value = mapped
+			return ret, true
+		}
+		if len(defaultValue) == 0 {
+			return in, false
+		}
+		*value = defaultValue
+		return ret, true
+	}
+}
+
+// MapArchitecture replaces the Architecture by its mapping, or by defaultValue when it has none and defaultValue
+// is not empty.
+func MapArchitecture(mapping map[string]string, defaultValue string) NextBestKey {
+	return mapJobTypeField(func(jobType *platformidentification.JobType) *string { return &jobType.Architecture }, mapping, defaultValue)
+}
+
+// MapTopology replaces the Topology by its mapping, or by defaultValue when it has none and defaultValue is not
+// empty.
+func MapTopology(mapping map[string]string, defaultValue string) NextBestKey {
+	return mapJobTypeField(func(jobType *platformidentification.JobType) *string { return &jobType.Topology }, mapping, defaultValue)
+}
+
+// MapNetwork replaces the Network by its mapping, or by defaultValue when it has none and defaultValue is not empty.
+func MapNetwork(mapping map[string]string, defaultValue string) NextBestKey {
+	return mapJobTypeField(func(jobType *platformidentification.JobType) *string { return &jobType.Network }, mapping, defaultValue)
+}
+
+// MapPlatform replaces the Platform by its mapping, or by defaultValue when it has none and defaultValue is not
+// empty.
+func MapPlatform(mapping map[string]string, defaultValue string) NextBestKey {
+	return mapJobTypeField(func(jobType *platformidentification.JobType) *string { return &jobType.Platform }, mapping, defaultValue)
+}
+
 // PreviousReleaseUpgrade if we don't have data for the current toRelease, perhaps we have data for the congruent test
 // on the prior release.   A 4.11 to 4.11 upgrade will attempt a 4.10 to 4.10 upgrade.  A 4.11 no upgrade, will attempt a 4.10 no upgrade.
 func PreviousReleaseUpgrade(in platformidentification.JobType) (platformidentification.JobType, bool) {
+	if len(strings.Split(in.Release, ".")) < 2 {
+		return in, false
+	}
 	toReleaseMajor := getMajor(in.Release)
 	toReleaseMinor := getMinor(in.Release)
 
@@ -1,6 +1,13 @@
 package historicaldata
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
 
 func TestCurrentReleaseFromMap(t *testing.T) {
 	// Test case: Empty input map
@@ -42,3 +49,91 @@ func TestCompareReleaseString(t *testing.T) {
 		t.Errorf("Expected true, but got false")
 	}
 }
+
+func TestFallbackChainCandidates(t *testing.T) {
+	chain := FallbackChain{RelaxMinorPlatforms, RelaxToAMD64, RelaxHAToSingle, RelaxToDefaultNetwork, RelaxToPreviousRelease}
+	jobType := platformidentification.JobType{
+		Release:      "4.16",
+		FromRelease:  "4.15",
+		Platform:     "vsphere-upi",
+		Architecture: "amd64",
+		Network:      "sdn",
+		Topology:     "ha",
+	}
+
+	candidates := chain.candidates(jobType)
+	// the architecture is already amd64, so that step is skipped.
+	expectedSteps := [][]string{
+		{"merge minor platforms"},
+		{"merge minor platforms", "topology ha to single"},
+		{"merge minor platforms", "topology ha to single", "network ovn"},
+		{"merge minor platforms", "topology ha to single", "network ovn", "previous release"},
+	}
+	if len(candidates) != len(expectedSteps) {
+		t.Fatalf("expected %d candidates, got %#v", len(expectedSteps), candidates)
+	}
+	for i := range expectedSteps {
+		if !reflect.DeepEqual(candidates[i].steps, expectedSteps[i]) {
+			t.Errorf("expected candidate %d to take steps %v, got %v", i, expectedSteps[i], candidates[i].steps)
+		}
+	}
+	expectedLast := platformidentification.JobType{
+		Release:      "4.15",
+		FromRelease:  "4.14",
+		Platform:     "vsphere",
+		Architecture: "amd64",
+		Network:      "ovn",
+		Topology:     "single",
+	}
+	if last := candidates[len(candidates)-1].jobType; last != expectedLast {
+		t.Errorf("expected %#v, got %#v", expectedLast, last)
+	}
+	if jobType.Platform != "vsphere-upi" {
+		t.Errorf("expected the job type to be left unchanged, got %#v", jobType)
+	}
+}
+
+func TestAlertBestMatcherFallbackChain(t *testing.T) {
+	jobType := platformidentification.JobType{Release: "4.16", FromRelease: "4.16", Platform: "vsphere-upi", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	fallbackJobType := platformidentification.JobType{Release: "4.16", FromRelease: "4.16", Platform: "vsphere", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	key := AlertDataKey{AlertName: "KubeAPIErrorBudgetBurn", AlertNamespace: "openshift-kube-apiserver", AlertLevel: "critical", JobType: jobType}
+	fallbackKey := key
+	fallbackKey.JobType = fallbackJobType
+
+	matcher := NewAlertMatcherWithHistoricalData(map[AlertDataKey]AlertStatisticalData{
+		fallbackKey: {AlertDataKey: fallbackKey, P95: 1, P99: 2, JobRuns: 200},
+	})
+	if duration, _, _ := matcher.BestMatchP99(key); duration != nil {
+		t.Fatalf("expected the default chain not to match, got %v", duration)
+	}
+
+	matcher.FallbackChain = FallbackChain{RelaxMinorPlatforms}
+	duration, details, err := matcher.BestMatchP99(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duration == nil || *duration != 2*time.Second {
+		t.Fatalf("expected the vsphere P99, got %v: %s", duration, details)
+	}
+	if !strings.Contains(details, "by relaxing merge minor platforms") {
+		t.Errorf("expected the reason to record the relaxation, got %q", details)
+	}
+}
+
+func TestParseFallbackChain(t *testing.T) {
+	chain, err := ParseFallbackChain([]string{"minor-platforms", "ha-to-single", "previous-release"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, relaxation := range chain {
+		names = append(names, relaxation.Name)
+	}
+	if expected := []string{"merge minor platforms", "topology ha to single", "previous release"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	if _, err := ParseFallbackChain([]string{"previous-release", "arm64"}); err == nil || !strings.Contains(err.Error(), `unknown relaxation "arm64"`) {
+		t.Errorf("expected an unknown relaxation error, got %v", err)
+	}
+}
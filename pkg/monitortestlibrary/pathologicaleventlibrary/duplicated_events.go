@@ -22,19 +22,19 @@ import (
 )
 
 func TestDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig *rest.Config) []*junitapi.JUnitTestCase {
+	platform, topology := clusterInfraInfoOrEmpty(kubeClientConfig)
+	return TestDuplicatedEventForUpgradeOn(events, kubeClientConfig, platform, topology)
+}
+
+// TestDuplicatedEventForUpgradeOn is TestDuplicatedEventForUpgrade for a cluster whose platform and topology are
+// already known, for instance from the cluster data saved by a replayed run.
+func TestDuplicatedEventForUpgradeOn(events monitorapi.Intervals, kubeClientConfig *rest.Config, platform v1.PlatformType, topology v1.TopologyMode) []*junitapi.JUnitTestCase {
 	registry := NewUpgradePathologicalEventMatchers(kubeClientConfig, events)
 
 	evaluator := duplicateEventsEvaluator{
 		registry: registry,
-	}
-
-	platform, topology, err := GetClusterInfraInfo(kubeClientConfig)
-	if err != nil {
-		logrus.WithError(err).Error("could not fetch cluster infra info")
-	} else {
-		// These could be coming out "" in theory
-		evaluator.platform = platform
-		evaluator.topology = topology
+		platform: platform,
+		topology: topology,
 	}
 
 	tests := []*junitapi.JUnitTestCase{}
@@ -44,19 +44,19 @@ func TestDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig
 }
 
 func TestDuplicatedEventForStableSystem(events monitorapi.Intervals, clientConfig *rest.Config) []*junitapi.JUnitTestCase {
+	platform, topology := clusterInfraInfoOrEmpty(clientConfig)
+	return TestDuplicatedEventForStableSystemOn(events, clientConfig, platform, topology)
+}
+
+// TestDuplicatedEventForStableSystemOn is TestDuplicatedEventForStableSystem for a cluster whose platform and
+// topology are already known, for instance from the cluster data saved by a replayed run.
+func TestDuplicatedEventForStableSystemOn(events monitorapi.Intervals, clientConfig *rest.Config, platform v1.PlatformType, topology v1.TopologyMode) []*junitapi.JUnitTestCase {
 	registry := NewUniversalPathologicalEventMatchers(clientConfig, events)
 
 	evaluator := duplicateEventsEvaluator{
 		registry: registry,
-	}
-
-	platform, topology, err := GetClusterInfraInfo(clientConfig)
-	if err != nil {
-		logrus.WithError(err).Error("could not fetch cluster infra info")
-	} else {
-		// These could be coming out "" in theory
-		evaluator.platform = platform
-		evaluator.topology = topology
+		platform: platform,
+		topology: topology,
 	}
 
 	tests := []*junitapi.JUnitTestCase{}
@@ -65,6 +65,17 @@ func TestDuplicatedEventForStableSystem(events monitorapi.Intervals, clientConfi
 	return tests
 }
 
+// clusterInfraInfoOrEmpty is GetClusterInfraInfo, logging the error and returning empty values when it fails.
+func clusterInfraInfoOrEmpty(clientConfig *rest.Config) (v1.PlatformType, v1.TopologyMode) {
+	platform, topology, err := GetClusterInfraInfo(clientConfig)
+	if err != nil {
+		logrus.WithError(err).Error("could not fetch cluster infra info")
+		return "", ""
+	}
+	// These could be coming out "" in theory
+	return platform, topology
+}
+
 type duplicateEventsEvaluator struct {
 	registry *AllowedPathologicalEventRegistry
 
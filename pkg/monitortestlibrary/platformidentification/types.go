@@ -226,6 +226,42 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 	}, nil
 }
 
+// InfrastructurePlatformAndTopology maps the Platform and Topology of a JobType back to the infrastructure values
+// GetJobType derived them from, for instance when the cluster is no longer reachable.  Unknown values map to "".
+func InfrastructurePlatformAndTopology(jobType JobType) (configv1.PlatformType, configv1.TopologyMode) {
+	var platform configv1.PlatformType
+	switch jobType.Platform {
+	case "aws":
+		platform = configv1.AWSPlatformType
+	case "gcp":
+		platform = configv1.GCPPlatformType
+	case "azure":
+		platform = configv1.AzurePlatformType
+	case "vsphere":
+		platform = configv1.VSpherePlatformType
+	case "metal":
+		platform = configv1.BareMetalPlatformType
+	case "ovirt":
+		platform = configv1.OvirtPlatformType
+	case "openstack":
+		platform = configv1.OpenStackPlatformType
+	case "libvirt":
+		platform = configv1.LibvirtPlatformType
+	}
+
+	var topology configv1.TopologyMode
+	switch jobType.Topology {
+	case "ha":
+		topology = configv1.HighlyAvailableTopologyMode
+	case "single":
+		topology = configv1.SingleReplicaTopologyMode
+	case "external":
+		topology = configv1.ExternalTopologyMode
+	}
+
+	return platform, topology
+}
+
 func VersionFromHistory(history configv1.UpdateHistory) string {
 	versionParts := strings.Split(history.Version, ".")
 	if len(versionParts) < 2 {
@@ -55,3 +55,7 @@ func (*legacyMonitorTests) WriteContentToStorage(ctx context.Context, storageDir
 func (*legacyMonitorTests) Cleanup(ctx context.Context) error {
 	return nil
 }
+
+func (w *legacyMonitorTests) NotReplayableReason() string {
+	return "the operator tests read the cluster configuration while evaluating"
+}
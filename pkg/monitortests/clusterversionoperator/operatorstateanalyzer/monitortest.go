@@ -35,6 +35,10 @@ func (*operatorStateChecker) ConstructComputedIntervals(ctx context.Context, sta
 	return ret, nil
 }
 
+func (*operatorStateChecker) ComputedIntervalSources() []monitorapi.IntervalSource {
+	return []monitorapi.IntervalSource{monitorapi.SourceOperatorState}
+}
+
 func (*operatorStateChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	return nil, nil
 }
@@ -0,0 +1,203 @@
+package upgradehops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+)
+
+// upgradeHopsAnalyzer splits an upgrade through several images into one interval per upgrade, a hop, and
+// reports the disruption and the node updates of every hop.
+type upgradeHopsAnalyzer struct {
+}
+
+func NewAnalyzer() monitortestframework.MonitorTest {
+	return &upgradeHopsAnalyzer{}
+}
+
+func (w *upgradeHopsAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *upgradeHopsAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*upgradeHopsAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return intervalsFromUpgradeEvents(startingIntervals, end), nil
+}
+
+func (*upgradeHopsAnalyzer) ComputedIntervalSources() []monitorapi.IntervalSource {
+	return []monitorapi.IntervalSource{monitorapi.SourceUpgradeHop}
+}
+
+// ConstructionDependencies waits for the node state intervals, the node updates of every hop are counted from them.
+func (*upgradeHopsAnalyzer) ConstructionDependencies() monitortestframework.ConstructionDependencies {
+	return monitortestframework.ConstructionDependencies{
+		IntervalSources: []monitorapi.IntervalSource{monitorapi.SourceNodeState},
+	}
+}
+
+func (*upgradeHopsAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*upgradeHopsAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	hopsDisruption := computeHopsDisruption(finalIntervals)
+	if len(hopsDisruption.Hops) == 0 {
+		return nil
+	}
+	jsonContent, err := json.MarshalIndent(hopsDisruption, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("upgrade-hops-disruption%s.json", timeSuffix)), jsonContent, 0644)
+}
+
+func (*upgradeHopsAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+// intervalsFromUpgradeEvents returns an interval for every upgrade started by the upgrade test, from its
+// UpgradeStarted event to the UpgradeComplete or UpgradeFailed event ending it, or to the end of the run.  A
+// rollback belongs to the hop it aborts.  Every hop is annotated with the number of nodes updated while it ran, which
+// stays 0 for the intermediate hops when the worker pools are paused.
+func intervalsFromUpgradeEvents(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	nodeUpdates := startingIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceNodeState && interval.Message.Annotations[monitorapi.AnnotationPhase] == "Update"
+	})
+
+	var upgradeEvents monitorapi.Intervals
+	for _, event := range startingIntervals {
+		if event.Source != monitorapi.SourceKubeEvent || event.Locator.Keys[monitorapi.LocatorClusterVersionKey] != "cluster" {
+			continue
+		}
+		switch event.Message.Reason {
+		case monitorapi.UpgradeStartedReason, monitorapi.UpgradeCompleteReason, monitorapi.UpgradeFailedReason:
+			upgradeEvents = append(upgradeEvents, event)
+		}
+	}
+	sort.SliceStable(upgradeEvents, func(i, j int) bool {
+		return upgradeEvents[i].From.Before(upgradeEvents[j].From)
+	})
+
+	locator := monitorapi.NewLocator().ClusterVersion(&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})
+	ret := monitorapi.Intervals{}
+	var started *monitorapi.Interval
+	hopInterval := func(to time.Time, status string) monitorapi.Interval {
+		hop := strconv.Itoa(len(ret) + 1)
+		updatedNodes := sets.NewString()
+		for _, nodeUpdate := range nodeUpdates.Cut(started.From, to) {
+			updatedNodes.Insert(nodeUpdate.Locator.Keys[monitorapi.LocatorNodeKey])
+		}
+		return monitorapi.NewInterval(monitorapi.SourceUpgradeHop, monitorapi.Info).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.UpgradeHopReason).
+				WithAnnotation(monitorapi.AnnotationUpgradeHop, hop).
+				WithAnnotation(monitorapi.AnnotationStatus, status).
+				WithAnnotation(monitorapi.AnnotationUpdatedNodes, strconv.Itoa(updatedNodes.Len())).
+				HumanMessagef("hop/%s %s", hop, started.Message.HumanMessage)).
+			Display().
+			Build(started.From, to)
+	}
+	for i := range upgradeEvents {
+		event := upgradeEvents[i]
+		switch {
+		case event.Message.Reason == monitorapi.UpgradeStartedReason:
+			if started != nil {
+				ret = append(ret, hopInterval(event.From, "Unfinished"))
+			}
+			started = &event
+		case started != nil:
+			// the upgrade test gives up on the upgrades at the first failure
+			ret = append(ret, hopInterval(event.From, event.Message.Reason.String()))
+			started = nil
+		}
+	}
+	if started != nil {
+		ret = append(ret, hopInterval(end, "Unfinished"))
+	}
+	return ret
+}
+
+// UpgradeHopsDisruption is the disruption during every hop of an upgrade and during all of them.
+type UpgradeHopsDisruption struct {
+	Hops []UpgradeHopDisruption
+	// TotalBackendDisruptions is keyed by backend disruption name, like the backend disruption summary.
+	TotalBackendDisruptions map[string]metav1.Duration
+}
+
+type UpgradeHopDisruption struct {
+	Hop int
+	// Message is the version and image of the hop.
+	Message string
+	// Status is the reason of the event ending the hop, or Unfinished.
+	Status string
+	From   time.Time
+	To     time.Time
+	// BackendDisruptions is keyed by backend disruption name.
+	BackendDisruptions map[string]metav1.Duration
+}
+
+func computeHopsDisruption(finalIntervals monitorapi.Intervals) *UpgradeHopsDisruption {
+	ret := &UpgradeHopsDisruption{
+		TotalBackendDisruptions: map[string]metav1.Duration{},
+	}
+
+	disruptionIntervals := finalIntervals.Filter(
+		monitorapi.And(
+			monitorapi.IsDisruptionEvent,
+			monitorapi.Or(monitorapi.IsErrorEvent, monitorapi.IsInfoEvent),
+		),
+	)
+	backendDisruptionNames := map[string]bool{}
+	for _, interval := range disruptionIntervals {
+		backendDisruptionNames[monitorapi.BackendDisruptionNameFromLocator(interval.Locator)] = true
+	}
+
+	for _, hopInterval := range finalIntervals {
+		if hopInterval.Source != monitorapi.SourceUpgradeHop {
+			continue
+		}
+		hop, err := strconv.Atoi(hopInterval.Message.Annotations[monitorapi.AnnotationUpgradeHop])
+		if err != nil {
+			continue
+		}
+		hopDisruption := UpgradeHopDisruption{
+			Hop:                hop,
+			Message:            hopInterval.Message.HumanMessage,
+			Status:             hopInterval.Message.Annotations[monitorapi.AnnotationStatus],
+			From:               hopInterval.From,
+			To:                 hopInterval.To,
+			BackendDisruptions: map[string]metav1.Duration{},
+		}
+		hopDisruptionIntervals := disruptionIntervals.Cut(hopInterval.From, hopInterval.To)
+		for backendDisruptionName := range backendDisruptionNames {
+			disruptedDuration, _ := monitorapi.BackendDisruptionSeconds(backendDisruptionName, hopDisruptionIntervals)
+			hopDisruption.BackendDisruptions[backendDisruptionName] = metav1.Duration{Duration: disruptedDuration}
+
+			total := ret.TotalBackendDisruptions[backendDisruptionName]
+			total.Duration += disruptedDuration
+			ret.TotalBackendDisruptions[backendDisruptionName] = total
+		}
+		ret.Hops = append(ret.Hops, hopDisruption)
+	}
+	sort.Slice(ret.Hops, func(i, j int) bool {
+		return ret.Hops[i].Hop < ret.Hops[j].Hop
+	})
+
+	return ret
+}
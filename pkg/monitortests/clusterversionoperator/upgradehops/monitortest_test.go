@@ -0,0 +1,142 @@
+package upgradehops
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/node/nodestateanalyzer"
+)
+
+func upgradeEvent(reason monitorapi.IntervalReason, note string, at time.Time) monitorapi.Interval {
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: monitorapi.Info,
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeClusterVersion,
+				Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorClusterVersionKey: "cluster"},
+			},
+			Message: monitorapi.Message{Reason: reason, HumanMessage: note},
+		},
+		Source: monitorapi.SourceKubeEvent,
+		From:   at,
+		To:     at.Add(time.Second),
+	}
+}
+
+func disruption(name string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: monitorapi.Error,
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeDisruption,
+				Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorBackendDisruptionNameKey: name},
+			},
+			Message: monitorapi.Message{Reason: monitorapi.DisruptionBeganEventReason},
+		},
+		Source: monitorapi.SourceDisruption,
+		From:   from,
+		To:     to,
+	}
+}
+
+func nodeEvent(node string, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(string(reason))).
+		Build(at, at)
+}
+
+func TestComputeHopsDisruption(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minute := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+	end := minute(100)
+
+	intervals := monitorapi.Intervals{
+		upgradeEvent(monitorapi.UpgradeStartedReason, "version/4.15.0 image/a", minute(0)),
+		upgradeEvent(monitorapi.UpgradePoolsPausedReason, "pools/worker", minute(1)),
+		upgradeEvent(monitorapi.UpgradeCompleteReason, "version/4.15.0 image/a", minute(40)),
+		upgradeEvent(monitorapi.UpgradeStartedReason, "version/4.16.0 image/b", minute(50)),
+		upgradeEvent(monitorapi.UpgradeRollbackReason, "version/4.15.0 image/a", minute(60)),
+		upgradeEvent(monitorapi.UpgradeFailedReason, "failed to settle operators", minute(90)),
+		// spans the end of the first hop
+		disruption("kube-api-new-connections", minute(39), minute(42)),
+		disruption("kube-api-new-connections", minute(55), minute(56)),
+		disruption("ingress-new-connections", minute(95), minute(96)),
+	}
+	hops := intervalsFromUpgradeEvents(intervals, end)
+	if !assert.Len(t, hops, 2) {
+		return
+	}
+	assert.Equal(t, "hop/1 version/4.15.0 image/a", hops[0].Message.HumanMessage)
+	assert.Equal(t, minute(0), hops[0].From)
+	assert.Equal(t, minute(40), hops[0].To)
+	assert.Equal(t, "UpgradeComplete", hops[0].Message.Annotations[monitorapi.AnnotationStatus])
+	assert.Equal(t, "2", hops[1].Message.Annotations[monitorapi.AnnotationUpgradeHop])
+	assert.Equal(t, minute(90), hops[1].To)
+	assert.Equal(t, "UpgradeFailed", hops[1].Message.Annotations[monitorapi.AnnotationStatus])
+
+	hopsDisruption := computeHopsDisruption(append(intervals, hops...))
+	if !assert.Len(t, hopsDisruption.Hops, 2) {
+		return
+	}
+	assert.Equal(t, time.Minute, hopsDisruption.Hops[0].BackendDisruptions["kube-api-new-connections"].Duration)
+	assert.Equal(t, time.Minute, hopsDisruption.Hops[1].BackendDisruptions["kube-api-new-connections"].Duration)
+	assert.Equal(t, 2*time.Minute, hopsDisruption.TotalBackendDisruptions["kube-api-new-connections"].Duration)
+	// outside of the hops
+	assert.Equal(t, time.Duration(0), hopsDisruption.TotalBackendDisruptions["ingress-new-connections"].Duration)
+}
+
+func TestIntervalsFromUnfinishedUpgrade(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	hops := intervalsFromUpgradeEvents(monitorapi.Intervals{
+		upgradeEvent(monitorapi.UpgradeStartedReason, "version/4.15.0 image/a", start),
+	}, end)
+	if !assert.Len(t, hops, 1) {
+		return
+	}
+	assert.Equal(t, end, hops[0].To)
+	assert.Equal(t, "Unfinished", hops[0].Message.Annotations[monitorapi.AnnotationStatus])
+
+	assert.Empty(t, intervalsFromUpgradeEvents(nil, end))
+}
+
+// The node updates come from the node state intervals of the node-state-analyzer, the registry has to construct them
+// before the hops.
+func TestConstructHopsAfterNodeState(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minute := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+
+	registry := monitortestframework.NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("node-state-analyzer", "Node / Kubelet", nodestateanalyzer.NewAnalyzer())
+	registry.AddMonitorTestOrDie("upgrade-hops-analyzer", "Cluster Version Operator", NewAnalyzer())
+
+	intervals, _, err := registry.ConstructComputedIntervals(context.Background(), monitorapi.Intervals{
+		upgradeEvent(monitorapi.UpgradeStartedReason, "version/4.15.0 image/a", minute(0)),
+		nodeEvent("master-0", "MachineConfigChange", minute(10)),
+		nodeEvent("master-0", "MachineConfigReached", minute(20)),
+		nodeEvent("master-1", "MachineConfigChange", minute(20)),
+		nodeEvent("master-1", "MachineConfigReached", minute(30)),
+		upgradeEvent(monitorapi.UpgradeCompleteReason, "version/4.15.0 image/a", minute(40)),
+		upgradeEvent(monitorapi.UpgradeStartedReason, "version/4.16.0 image/b", minute(50)),
+		upgradeEvent(monitorapi.UpgradeCompleteReason, "version/4.16.0 image/b", minute(90)),
+	}, nil, start, minute(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hops := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceUpgradeHop
+	})
+	if !assert.Len(t, hops, 2) {
+		return
+	}
+	assert.Equal(t, "2", hops[0].Message.Annotations[monitorapi.AnnotationUpdatedNodes])
+	assert.Equal(t, "0", hops[1].Message.Annotations[monitorapi.AnnotationUpdatedNodes])
+}
@@ -55,6 +55,12 @@ func (w *legacyMonitorTests) StartCollection(ctx context.Context, adminRESTConfi
 	return nil
 }
 
+func (w *legacyMonitorTests) PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) error {
+	jobType := platformidentification.CloneJobType(clusterData.JobType)
+	w.jobType = &jobType
+	return nil
+}
+
 func (w *legacyMonitorTests) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	return nil, nil, w.notSupportedReason
 }
@@ -48,3 +48,7 @@ func (*legacyMonitorTests) WriteContentToStorage(ctx context.Context, storageDir
 func (*legacyMonitorTests) Cleanup(ctx context.Context) error {
 	return nil
 }
+
+func (w *legacyMonitorTests) NotReplayableReason() string {
+	return "the static pod lifecycle test lists the events of the cluster while evaluating"
+}
@@ -146,6 +146,10 @@ func (sc *statefulsetsChecker) Cleanup(ctx context.Context) error {
 	return nil
 }
 
+func (sc *statefulsetsChecker) NotReplayableReason() string {
+	return "the statefulset UIDs are compared with the ones read from the cluster by StartCollection"
+}
+
 func (sc *statefulsetsChecker) getStatefulsetsUID(ctx context.Context) (map[string]string, error) {
 	statefulsetsUID := make(map[string]string)
 	var failures []error
@@ -0,0 +1,338 @@
+package disruptiondns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// backendPrefix matches the --disruption-backend-prefix of the poller daemonset.
+const backendPrefix = "dns"
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace          *corev1.Namespace
+	pollerRoleBinding  *rbacv1.RoleBinding
+	dnsPollerDaemonSet *appsv1.DaemonSet
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	pollerRoleBinding = resourceread.ReadRoleBindingV1OrDie(yamlOrDie("poller-rolebinding.yaml"))
+	dnsPollerDaemonSet = resourceread.ReadDaemonSetV1OrDie(yamlOrDie("dns-poller-daemonset.yaml"))
+}
+
+// dnsAvailability runs a poller on every node resolving an in-cluster service name and an external name with the
+// cluster DNS service.  The pollers record their disruption intervals in their logs, which are collected at the end.
+type dnsAvailability struct {
+	payloadImagePullSpec string
+	notSupportedReason   error
+	namespaceName        string
+	kubeClient           kubernetes.Interface
+
+	// store the rest config so we can get the JobType at the end of the run, which will include any upgrade versions.
+	// jobType is only set up front when replaying.
+	adminRESTConfig *rest.Config
+	jobType         *platformidentification.JobType
+}
+
+func NewDNSAvailabilityInvariant(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &dnsAvailability{
+		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+	}
+}
+
+func updateDaemonSetENVs(daemonSet *appsv1.DaemonSet, deploymentID, dnsServer, externalName string) *appsv1.DaemonSet {
+	for i, env := range daemonSet.Spec.Template.Spec.Containers[0].Env {
+		switch env.Name {
+		case "DEPLOYMENT_ID":
+			daemonSet.Spec.Template.Spec.Containers[0].Env[i].Value = deploymentID
+		case "DNS_SERVER":
+			daemonSet.Spec.Template.Spec.Containers[0].Env[i].Value = dnsServer
+		case "EXTERNAL_NAME":
+			daemonSet.Spec.Template.Spec.Containers[0].Env[i].Value = externalName
+		}
+	}
+
+	return daemonSet
+}
+
+func (w *dnsAvailability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	deploymentID := uuid.New().String()
+	w.adminRESTConfig = adminRESTConfig
+
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	dnsService, err := w.kubeClient.CoreV1().Services("openshift-dns").Get(ctx, "dns-default", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the cluster DNS service openshift-dns/dns-default does not exist"}
+		return w.notSupportedReason
+	}
+	if err != nil {
+		return err
+	}
+
+	// the API server name is outside of the cluster domain, so the cluster DNS forwards it upstream.
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	infra, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	apiServerURL, err := url.Parse(infra.Status.APIServerURL)
+	if err != nil {
+		return fmt.Errorf("unable to parse the API server URL %q: %w", infra.Status.APIServerURL, err)
+	}
+
+	openshiftTestsImagePullSpec, err := disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, adminRESTConfig, w.payloadImagePullSpec, nil)
+	if err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: fmt.Sprintf("unable to determine openshift-tests image: %v", err)}
+		return w.notSupportedReason
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(context.Background(), namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	if _, err = w.kubeClient.RbacV1().RoleBindings(w.namespaceName).Create(context.Background(), pollerRoleBinding, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	// the daemonset runs a poller on every node whose taints it tolerates, so unschedulable nodes and nodes tainted
+	// for other workloads are skipped instead of leaving pollers pending.
+	daemonSet := dnsPollerDaemonSet.DeepCopy()
+	daemonSet.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
+	daemonSet = updateDaemonSetENVs(daemonSet, deploymentID, dnsService.Spec.ClusterIP, apiServerURL.Hostname())
+	if _, err = w.kubeClient.AppsV1().DaemonSets(w.namespaceName).Create(context.Background(), daemonSet, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *dnsAvailability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	// create the stop collecting configmap and wait for 30s to thing to have stopped.  the 30s is just a guess
+	if _, err := w.kubeClient.CoreV1().ConfigMaps(w.namespaceName).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "stop-collecting"},
+	}, metav1.CreateOptions{}); err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case <-time.After(30 * time.Second):
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	intervals, junits, errs := w.collectDetailsForPoller(ctx)
+	return intervals, junits, utilerrors.NewAggregate(errs)
+}
+
+func (w *dnsAvailability) collectDetailsForPoller(ctx context.Context) (monitorapi.Intervals, []*junitapi.JUnitTestCase, []error) {
+	pollerLabel, err := labels.NewRequirement("network.openshift.io/disruption-actor", selection.Equals, []string{"poller"})
+	if err != nil {
+		return nil, nil, []error{err}
+	}
+	typeLabel, err := labels.NewRequirement("network.openshift.io/disruption-target", selection.Equals, []string{"dns"})
+	if err != nil {
+		return nil, nil, []error{err}
+	}
+	pollerPods, err := w.kubeClient.CoreV1().Pods(w.namespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.NewSelector().Add(*pollerLabel).Add(*typeLabel).String(),
+	})
+	if err != nil {
+		return nil, nil, []error{err}
+	}
+
+	retIntervals := monitorapi.Intervals{}
+	errs := []error{}
+	buf := &bytes.Buffer{}
+	podsWithoutIntervals := []string{}
+	for _, pollerPod := range pollerPods.Items {
+		fmt.Fprintf(buf, "\n\nLogs for -n %v pod/%v\n", pollerPod.Namespace, pollerPod.Name)
+		logStream, err := w.kubeClient.CoreV1().Pods(w.namespaceName).GetLogs(pollerPod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		foundInterval := false
+		scanner := bufio.NewScanner(logStream)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			buf.Write(line)
+			buf.Write([]byte("\n"))
+			if len(line) == 0 {
+				continue
+			}
+
+			// not all lines are json, ignore errors.
+			if currInterval, err := monitorserialization.IntervalFromJSON(line); err == nil {
+				retIntervals = append(retIntervals, *currInterval)
+				foundInterval = true
+			}
+		}
+		logStream.Close()
+		if !foundInterval {
+			podsWithoutIntervals = append(podsWithoutIntervals, pollerPod.Name)
+		}
+	}
+
+	failures := []string{}
+	if len(podsWithoutIntervals) > 0 {
+		failures = append(failures, fmt.Sprintf("%d pods lacked sampler output: [%v]", len(podsWithoutIntervals), strings.Join(podsWithoutIntervals, ", ")))
+	}
+	if len(pollerPods.Items) == 0 {
+		failures = append(failures, "no pods found for the dns poller")
+	}
+
+	logJunit := &junitapi.JUnitTestCase{
+		Name:      "[sig-network] can collect dns poller pod logs",
+		SystemOut: string(buf.Bytes()),
+	}
+	if len(failures) > 0 {
+		logJunit.FailureOutput = &junitapi.FailureOutput{
+			Output: strings.Join(failures, "\n"),
+		}
+	}
+
+	return retIntervals, []*junitapi.JUnitTestCase{logJunit}, errs
+}
+
+func (w *dnsAvailability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+// PrepareForReplay uses the job type of the original run, the saved intervals include the ones of the pollers.
+func (w *dnsAvailability) PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) error {
+	jobType := platformidentification.CloneJobType(clusterData.JobType)
+	w.jobType = &jobType
+	return nil
+}
+
+func (w *dnsAvailability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+
+	jobType := w.jobType
+	if jobType == nil {
+		var err error
+		jobType, err = platformidentification.GetJobType(ctx, w.adminRESTConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	junits := []*junitapi.JUnitTestCase{}
+	for _, backendName := range backendNames() {
+		testName := fmt.Sprintf("[sig-network] disruption/%s should be available throughout the test", backendName)
+		junit, err := disruptionlibrary.CreatePollerDisruptionJunit(testName, backendName, finalIntervals, jobType)
+		if err != nil {
+			return nil, err
+		}
+		junits = append(junits, junit)
+	}
+	return junits, nil
+}
+
+// backendNames are the historical backend disruption names the pollers record, see poll-dns.
+func backendNames() []string {
+	ret := []string{}
+	for _, target := range []string{"service", "external"} {
+		for _, protocol := range []backenddisruption.DNSProtocol{backenddisruption.DNSOverUDP, backenddisruption.DNSOverTCP} {
+			for _, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+				ret = append(ret, fmt.Sprintf("%s-%s-%s-%v-connections", backendPrefix, target, protocol, connectionType))
+			}
+		}
+	}
+	return ret
+}
+
+func (w *dnsAvailability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *dnsAvailability) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *dnsAvailability) Cleanup(ctx context.Context) error {
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted)
+		if err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting namespace: %s took %.2f seconds", w.namespaceName, time.Now().Sub(startTime).Seconds())
+	}
+	return nil
+}
@@ -56,3 +56,7 @@ func (*legacyMonitorTests) WriteContentToStorage(ctx context.Context, storageDir
 func (*legacyMonitorTests) Cleanup(ctx context.Context) error {
 	return nil
 }
+
+func (w *legacyMonitorTests) NotReplayableReason() string {
+	return "the pod sandbox test reads the platform of the cluster while evaluating"
+}
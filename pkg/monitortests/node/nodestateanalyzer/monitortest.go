@@ -33,6 +33,10 @@ func (*nodeStateAnalyzer) ConstructComputedIntervals(ctx context.Context, starti
 	return ret, nil
 }
 
+func (*nodeStateAnalyzer) ComputedIntervalSources() []monitorapi.IntervalSource {
+	return []monitorapi.IntervalSource{monitorapi.SourceNodeState}
+}
+
 func (*nodeStateAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	return nil, nil
 }
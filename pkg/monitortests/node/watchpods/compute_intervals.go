@@ -48,7 +48,13 @@ func intervalsFromEvents_PodChanges(events monitorapi.Intervals, beginning, end
 }
 
 func createPodIntervalsFromInstants(input monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, startTime, endTime time.Time) monitorapi.Intervals {
-	sort.Stable(ByPodLifecycle(input))
+	var podIntervals monitorapi.Intervals
+	for _, event := range input {
+		if _, ok := event.Locator.Keys[monitorapi.LocatorPodKey]; ok {
+			podIntervals = append(podIntervals, event)
+		}
+	}
+	sort.Stable(ByPodLifecycle(podIntervals))
 	// these *static* locators to events. These are NOT the same as the actual event locators because nodes are not consistently assigned.
 	// As such we need to strip out all but the essential locator keys for both pods and containers so we can consistently key them in maps
 	// across their lifecycle changes.
@@ -61,11 +67,8 @@ func createPodIntervalsFromInstants(input monitorapi.Intervals, recordedResource
 	// without having to parse legacy locator strings (which we're using in the map keys)
 	locatorKeyToLocator := map[string]monitorapi.Locator{}
 
-	for i := range input {
-		event := input[i]
-		if _, ok := event.Locator.Keys[monitorapi.LocatorPodKey]; !ok {
-			continue
-		}
+	for i := range podIntervals {
+		event := podIntervals[i]
 
 		// We have to strip out container, this needs to be just pod locator here
 		podLocator := monitorapi.PodFrom(event.Locator).ToLocator()
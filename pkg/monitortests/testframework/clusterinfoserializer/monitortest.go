@@ -56,6 +56,10 @@ func (*clusterInfoSerializer) Cleanup(ctx context.Context) error {
 	return nil
 }
 
+func (w *clusterInfoSerializer) NotReplayableReason() string {
+	return "the cluster data is read from the cluster when it is written"
+}
+
 func writeClusterData(filename string, clusterData platformidentification.ClusterData) error {
 	jsonContent, err := json.MarshalIndent(clusterData, "", "    ")
 	if err != nil {
@@ -0,0 +1,97 @@
+package disruptionstreams
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/disruptionlibrary"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	kubeAPIStreamTestName    = "[sig-api-machinery] disruption/kube-api connection/stream should be available throughout the test"
+	grpcHealthStreamTestName = "[sig-network] disruption/grpc-health connection/stream should be available throughout the test"
+
+	// kubeAPIWatchPath is a watch of the kube-apiserver, held open as a WebSocket.
+	kubeAPIWatchPath = "/api/v1/namespaces/default/configmaps?watch=true"
+
+	// GRPCHealthTargetEnv, if set, is the host:port of a gRPC server whose health service is watched over TLS, there
+	// is no gRPC server in the cluster that openshift-tests can reach on its own.
+	GRPCHealthTargetEnv = "OPENSHIFT_TESTS_GRPC_HEALTH_TARGET"
+)
+
+// availability holds long-lived streams open during the run: a WebSocket watch of the kube-apiserver, and the health
+// stream of the gRPC server of GRPCHealthTargetEnv.
+type availability struct {
+	disruptionCheckers []*disruptionlibrary.StreamAvailability
+}
+
+func NewAvailabilityInvariant() monitortestframework.MonitorTest {
+	return &availability{}
+}
+
+func (w *availability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeAPISampler, err := backenddisruption.NewAPIServerWebSocketBackend(adminRESTConfig, "kube-api", kubeAPIWatchPath)
+	if err != nil {
+		return err
+	}
+	w.disruptionCheckers = append(w.disruptionCheckers, disruptionlibrary.NewStreamAvailabilityInvariant(kubeAPIStreamTestName, kubeAPISampler))
+
+	if target := os.Getenv(GRPCHealthTargetEnv); len(target) > 0 {
+		grpcSampler := backenddisruption.NewStreamBackendFromOpenshiftTests(
+			"grpc-health-stream-connections",
+			backenddisruption.NewGRPCHealthDialer(target, "").WithTLSConfig(&tls.Config{}),
+		)
+		w.disruptionCheckers = append(w.disruptionCheckers, disruptionlibrary.NewStreamAvailabilityInvariant(grpcHealthStreamTestName, grpcSampler))
+	}
+
+	for i := range w.disruptionCheckers {
+		if err := w.disruptionCheckers[i].StartCollection(ctx, adminRESTConfig, recorder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *availability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	errs := []error{}
+	for i := range w.disruptionCheckers {
+		if _, _, err := w.disruptionCheckers[i].CollectData(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return nil, nil, utilerrors.NewAggregate(errs)
+}
+
+func (*availability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *availability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	junits := []*junitapi.JUnitTestCase{}
+	errs := []error{}
+	for i := range w.disruptionCheckers {
+		localJunits, err := w.disruptionCheckers[i].EvaluateTestsFromConstructedIntervals(ctx, finalIntervals)
+		junits = append(junits, localJunits...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return junits, utilerrors.NewAggregate(errs)
+}
+
+func (*availability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*availability) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -12,14 +12,19 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/rest"
 )
 
 type intervalSerializer struct {
+	writeParquet bool
 }
 
-func NewIntervalSerializer() monitortestframework.MonitorTest {
-	return &intervalSerializer{}
+// NewIntervalSerializer writes the final intervals as e2e-events json and, when writeParquet is set, as parquet.
+func NewIntervalSerializer(writeParquet bool) monitortestframework.MonitorTest {
+	return &intervalSerializer{
+		writeParquet: writeParquet,
+	}
 }
 
 func (w *intervalSerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
@@ -38,8 +43,26 @@ func (*intervalSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Con
 	return nil, nil
 }
 
-func (*intervalSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return monitorserialization.EventsToFile(filepath.Join(storageDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)), finalIntervals)
+func (w *intervalSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	errs := []error{}
+	if err := monitorserialization.EventsToFile(filepath.Join(storageDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)), finalIntervals); err != nil {
+		errs = append(errs, err)
+	}
+	if !w.writeParquet {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	// the parquet files carry the same content with typed columns so they can be loaded directly by analysis tools.
+	if err := monitorserialization.IntervalsToParquet(filepath.Join(storageDir, fmt.Sprintf("e2e-events%s.parquet", timeSuffix)), finalIntervals); err != nil {
+		errs = append(errs, err)
+	}
+	if err := monitorserialization.DisruptionToParquet(filepath.Join(storageDir, fmt.Sprintf("e2e-disruption%s.parquet", timeSuffix)), finalIntervals); err != nil {
+		errs = append(errs, err)
+	}
+	if err := monitorserialization.ResourcesToParquet(filepath.Join(storageDir, fmt.Sprintf("e2e-resources%s.parquet", timeSuffix)), finalResourceState); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
 }
 
 func (*intervalSerializer) Cleanup(ctx context.Context) error {
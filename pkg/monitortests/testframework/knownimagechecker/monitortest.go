@@ -207,6 +207,10 @@ func (*clusterImageValidator) Cleanup(ctx context.Context) error {
 	return nil
 }
 
+func (w *clusterImageValidator) NotReplayableReason() string {
+	return "the pods and allowed images are read from the cluster while evaluating"
+}
+
 func hasAnyStringPrefix(s string, prefixes []string) bool {
 	for _, prefix := range prefixes {
 		if strings.HasPrefix(s, prefix) {
@@ -40,20 +40,20 @@ func testAlerts(events monitorapi.Intervals,
 	// please keep any use of the rest.Config isolated to this function and do not have the actual
 	// invariant tests themselves hitting a live cluster.
 
-	configClient := configv1client.NewForConfigOrDie(restConfig)
 	featureSet := configv1.Default
-	featureGate, err := configClient.ConfigV1().FeatureGates().Get(context.TODO(), "cluster", metav1.GetOptions{})
-	if err != nil {
-		framework.Logf("ERROR: error checking feature gates in cluster, ignoring: %v", err)
-	} else {
-		featureSet = featureGate.Spec.FeatureSet
-	}
-
 	var etcdAllowance allowedalerts.AlertTestAllowanceCalculator
 	etcdAllowance = allowedalerts.DefaultAllowances
-	// if we have a restConfig,  use it.
+	// if we have a restConfig,  use it.  There is none when replaying saved intervals.
 	var kubeClient *kubernetes.Clientset
 	if restConfig != nil {
+		configClient := configv1client.NewForConfigOrDie(restConfig)
+		featureGate, err := configClient.ConfigV1().FeatureGates().Get(context.TODO(), "cluster", metav1.GetOptions{})
+		if err != nil {
+			framework.Logf("ERROR: error checking feature gates in cluster, ignoring: %v", err)
+		} else {
+			featureSet = featureGate.Spec.FeatureSet
+		}
+
 		kubeClient, err = kubernetes.NewForConfig(restConfig)
 		if err != nil {
 			panic(err)
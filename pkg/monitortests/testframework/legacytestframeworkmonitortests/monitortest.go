@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	configv1 "github.com/openshift/api/config/v1"
+
 	"github.com/openshift/origin/pkg/monitortestframework"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
@@ -18,6 +20,7 @@ import (
 
 type legacyMonitorTests struct {
 	adminRESTConfig            *rest.Config
+	jobType                    *platformidentification.JobType
 	duration                   time.Duration
 	recordedResources          monitorapi.ResourcesMap
 	clusterStabilityDuringTest *monitortestframework.ClusterStabilityDuringTest
@@ -32,6 +35,13 @@ func (w *legacyMonitorTests) StartCollection(ctx context.Context, adminRESTConfi
 	return nil
 }
 
+func (w *legacyMonitorTests) PrepareForReplay(ctx context.Context, clusterData platformidentification.ClusterData, beginning, end time.Time) error {
+	jobType := platformidentification.CloneJobType(clusterData.JobType)
+	w.jobType = &jobType
+	w.duration = end.Sub(beginning)
+	return nil
+}
+
 func (w *legacyMonitorTests) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	w.duration = end.Sub(beginning)
 	return nil, nil, nil
@@ -43,21 +53,38 @@ func (w *legacyMonitorTests) ConstructComputedIntervals(ctx context.Context, sta
 }
 
 func (w *legacyMonitorTests) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	jobType, err := platformidentification.GetJobType(context.TODO(), w.adminRESTConfig)
-	if err != nil {
-		// JobType will be nil here, but we want test cases to all fail if this is the case, so we rely on them to nil check
-		logrus.WithError(err).Warn("ERROR: unable to determine job type for alert testing, jobType will be nil")
+	jobType := w.jobType
+	if jobType == nil {
+		var err error
+		jobType, err = platformidentification.GetJobType(context.TODO(), w.adminRESTConfig)
+		if err != nil {
+			// JobType will be nil here, but we want test cases to all fail if this is the case, so we rely on them to nil check
+			logrus.WithError(err).Warn("ERROR: unable to determine job type for alert testing, jobType will be nil")
+		}
+	}
+
+	var platform configv1.PlatformType
+	var topology configv1.TopologyMode
+	if w.adminRESTConfig != nil {
+		var err error
+		platform, topology, err = pathologicaleventlibrary.GetClusterInfraInfo(w.adminRESTConfig)
+		if err != nil {
+			logrus.WithError(err).Error("could not fetch cluster infra info")
+		}
+	} else if jobType != nil {
+		// replayed, the infrastructure comes from the saved cluster data.
+		platform, topology = platformidentification.InfrastructurePlatformAndTopology(*jobType)
 	}
 
 	junits := []*junitapi.JUnitTestCase{}
 
 	isUpgrade := platformidentification.DidUpgradeHappenDuringCollection(finalIntervals, time.Time{}, time.Time{})
 	if isUpgrade {
-		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForUpgrade(finalIntervals, w.adminRESTConfig)...)
+		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForUpgradeOn(finalIntervals, w.adminRESTConfig, platform, topology)...)
 		junits = append(junits, testAlerts(finalIntervals, alerts.AllowedAlertsDuringUpgrade, jobType, w.clusterStabilityDuringTest,
 			w.adminRESTConfig, w.duration, w.recordedResources)...)
 	} else {
-		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForStableSystem(finalIntervals, w.adminRESTConfig)...)
+		junits = append(junits, pathologicaleventlibrary.TestDuplicatedEventForStableSystemOn(finalIntervals, w.adminRESTConfig, platform, topology)...)
 		junits = append(junits, testAlerts(finalIntervals, alerts.AllowedAlertsDuringConformance, jobType, w.clusterStabilityDuringTest,
 			w.adminRESTConfig, w.duration, w.recordedResources)...)
 	}
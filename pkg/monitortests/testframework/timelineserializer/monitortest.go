@@ -66,6 +66,12 @@ func (*timelineSerializer) WriteContentToStorage(ctx context.Context, storageDir
 	if err != nil {
 		errs = append(errs, err)
 	}
+	disruptionRenderer, err := NewSpyglassEventIntervalRendererForQuery("disruption", DisruptionQuery)
+	if err != nil {
+		errs = append(errs, err)
+	} else if err := disruptionRenderer.WriteRunData(storageDir, nil, customOrderedEvents, timeSuffix); err != nil {
+		errs = append(errs, err)
+	}
 	err = NewPodEventIntervalRenderer().WriteRunData(storageDir, nil, customOrderedEvents, timeSuffix)
 	if err != nil {
 		errs = append(errs, err)
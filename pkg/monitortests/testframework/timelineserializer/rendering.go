@@ -20,6 +20,8 @@ type eventIntervalRenderer struct {
 	name           string
 	filenameBaseFn filenameBaseFunc
 	filter         monitorapi.EventIntervalMatchesFunc
+	// query, if set, is bound to the rendered intervals and used instead of filter.
+	query *monitorapi.IntervalQuery
 }
 
 func NewSpyglassEventIntervalRenderer(name string, filter monitorapi.EventIntervalMatchesFunc) eventIntervalRenderer {
@@ -42,6 +44,18 @@ func NewNonSpyglassEventIntervalRenderer(name string, filter monitorapi.EventInt
 	}
 }
 
+// NewSpyglassEventIntervalRendererForQuery creates a renderer for the intervals matching query.  See
+// monitorapi.IntervalQuery for the syntax.
+func NewSpyglassEventIntervalRendererForQuery(name, query string) (eventIntervalRenderer, error) {
+	parsedQuery, err := monitorapi.ParseIntervalQuery(query)
+	if err != nil {
+		return eventIntervalRenderer{}, fmt.Errorf("invalid query for %q: %w", name, err)
+	}
+	ret := NewSpyglassEventIntervalRenderer(name, BelongsInEverything)
+	ret.query = parsedQuery
+	return ret, nil
+}
+
 func (r eventIntervalRenderer) WriteRunData(artifactDir string, _ monitorapi.ResourcesMap, events monitorapi.Intervals, timeSuffix string) error {
 	filenameBase := r.filenameBaseFn(timeSuffix)
 	return r.writeEventData(artifactDir, filenameBase, events, timeSuffix)
@@ -49,7 +63,11 @@ func (r eventIntervalRenderer) WriteRunData(artifactDir string, _ monitorapi.Res
 
 func (r eventIntervalRenderer) writeEventData(artifactDir, filenameBase string, events monitorapi.Intervals, timeSuffix string) error {
 	errs := []error{}
-	interestingEvents := events.Filter(r.filter)
+	filter := r.filter
+	if r.query != nil {
+		filter = r.query.Matcher(events)
+	}
+	interestingEvents := events.Filter(filter)
 
 	if err := monitorserialization.IntervalsToFile(filepath.Join(artifactDir, fmt.Sprintf("%s.json", filenameBase)), interestingEvents); err != nil {
 		errs = append(errs, err)
@@ -77,6 +95,10 @@ func (r eventIntervalRenderer) writeEventData(artifactDir, filenameBase string,
 	return utilerrors.NewAggregate(errs)
 }
 
+// DisruptionQuery selects the disruption intervals and the warnings and errors overlapping them, to look for what
+// went wrong when a backend was unavailable.  See monitorapi.IntervalQuery for the syntax.
+const DisruptionQuery = `source=Disruption OR (level!=Info AND overlaps(source=Disruption))`
+
 func BelongsInEverything(eventInterval monitorapi.Interval) bool {
 	return true
 }
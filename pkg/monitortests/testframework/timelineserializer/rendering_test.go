@@ -2,8 +2,12 @@ package timelineserializer
 
 import (
 	_ "embed"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 )
 
@@ -27,3 +31,38 @@ func TestBelongsInKubeAPIServer(t *testing.T) {
 		}
 	}
 }
+
+func TestDisruptionQueryRenderer(t *testing.T) {
+	interval := func(source monitorapi.IntervalSource, level monitorapi.IntervalLevel, from, to int64) monitorapi.Interval {
+		return monitorapi.NewInterval(source, level).
+			Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+			Message(monitorapi.NewMessage().HumanMessage(string(source))).
+			Build(time.Unix(from, 0), time.Unix(to, 0))
+	}
+	intervals := monitorapi.Intervals{
+		interval(monitorapi.SourceDisruption, monitorapi.Error, 100, 200),
+		interval(monitorapi.SourceAlert, monitorapi.Warning, 150, 300),
+		interval(monitorapi.SourceAlert, monitorapi.Info, 150, 300),
+		interval(monitorapi.SourceAlert, monitorapi.Error, 250, 300),
+	}
+
+	renderer, err := NewSpyglassEventIntervalRendererForQuery("disruption", DisruptionQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifactDir := t.TempDir()
+	if err := renderer.WriteRunData(artifactDir, nil, intervals, "_test"); err != nil {
+		t.Fatal(err)
+	}
+	written, err := os.ReadFile(filepath.Join(artifactDir, "e2e-timelines_disruption_test.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := monitorserialization.IntervalsFromJSON(written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rendered) != 2 || rendered[0].Source != monitorapi.SourceDisruption || rendered[1].Level != monitorapi.Warning {
+		t.Errorf("expected the disruption and the warning overlapping it, got %v", rendered.Strings())
+	}
+}
@@ -87,6 +87,10 @@ func (w *watchRequestCountSerializer) Cleanup(ctx context.Context) error {
 	return nil
 }
 
+func (w *watchRequestCountSerializer) NotReplayableReason() string {
+	return "the watch request counts are read from the cluster when they are written"
+}
+
 type OperatorKey struct {
 	NodeName string
 	Operator string
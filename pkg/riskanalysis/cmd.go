@@ -120,8 +120,13 @@ func (opt *Options) Run() error {
 	}
 
 	// Write html file for spyglass
+	// a local risk analysis has no sippy to link to, the page omits its links
+	uiURL := sippyUiURL
+	if _, ok := isLocalSippyURL(opt.SippyURL); ok {
+		uiURL = ""
+	}
 	riskAnalysisHTMLTemplate := testdata.MustAsset("e2echart/test-risk-analysis.html")
-	html := bytes.ReplaceAll(riskAnalysisHTMLTemplate, []byte("TEST_RISK_ANALYSIS_SIPPY_URL_GOES_HERE"), []byte(sippyUiURL))
+	html := bytes.ReplaceAll(riskAnalysisHTMLTemplate, []byte("TEST_RISK_ANALYSIS_SIPPY_URL_GOES_HERE"), []byte(uiURL))
 	html = bytes.ReplaceAll(html, []byte("TEST_RISK_ANALYSIS_JSON_GOES_HERE"), riskAnalysisBytes)
 	html = bytes.ReplaceAll(html, []byte("TEST_DISRUPTION_ANALYSIS_JSON_GOES_HERE"), disruptionBytes)
 	path := filepath.Join(opt.JUnitDir, fmt.Sprintf("%s.html", "test-risk-analysis"))
@@ -144,20 +149,32 @@ type raRequestLog struct {
 
 // readWriteRiskAnalysis requests Risk Analysis from sippy, writes the results to disk, and returns the RA html to include in prow job output.
 // If the request fails, it will try up to maxTries times before returning an error; an error means no RA data returned.
+// A local sippy URL computes the analysis from a pass rate file instead.
 func (opt *Options) readWriteRiskAnalysis(inputBytes []byte) ([]byte, error) {
-	req, err := http.NewRequest("GET", opt.SippyURL, bytes.NewBuffer(inputBytes))
-	if err != nil {
-		logrus.WithError(err).Error("Error creating GET request during risk analysis")
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	riskAnalysisBytes, err := opt.requestRiskAnalysis(req, &http.Client{}, &realSleeper{})
-	if err != nil {
-		return nil, err
+	var riskAnalysisBytes []byte
+	if passRatesPath, ok := isLocalSippyURL(opt.SippyURL); ok {
+		logrus.Infof("Computing risk analysis locally from: %s", passRatesPath)
+		var err error
+		riskAnalysisBytes, err = requestLocalRiskAnalysis(passRatesPath, inputBytes)
+		if err != nil {
+			logrus.WithError(err).Error("Error computing risk analysis locally")
+			return nil, err
+		}
+	} else {
+		req, err := http.NewRequest("GET", opt.SippyURL, bytes.NewBuffer(inputBytes))
+		if err != nil {
+			logrus.WithError(err).Error("Error creating GET request during risk analysis")
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		riskAnalysisBytes, err = opt.requestRiskAnalysis(req, &http.Client{}, &realSleeper{})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	outputFile := filepath.Join(opt.JUnitDir, raDataFile)
-	err = os.WriteFile(outputFile, riskAnalysisBytes, 0644)
+	err := os.WriteFile(outputFile, riskAnalysisBytes, 0644)
 	if err != nil {
 		logrus.WithError(err).Error("Error writing risk analysis json artifact")
 	} else {
@@ -0,0 +1,147 @@
+package riskanalysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LocalSippyURLPrefix selects the local risk analysis, for instance --sippy-url=local:///path/to/pass-rates.json.
+// The file holds the JSON list of tests returned by the sippy tests API.
+const LocalSippyURLPrefix = "local://"
+
+// Risk levels are the levels of sippy, so that the local risk analysis is read like the one of sippy: 100 is high
+// and 50 is medium.
+var (
+	RiskLevelNone    = RiskLevel{Name: "None", Level: 0}
+	RiskLevelLow     = RiskLevel{Name: "Low", Level: 1}
+	RiskLevelUnknown = RiskLevel{Name: "Unknown", Level: 25}
+	RiskLevelMedium  = RiskLevel{Name: "Medium", Level: 50}
+	RiskLevelHigh    = RiskLevel{Name: "High", Level: 100}
+)
+
+const (
+	// localMinRuns is the number of runs a test needs for its pass rate to mean anything.
+	localMinRuns = 7
+	// localHighRiskPassPercentage and localMediumRiskPassPercentage are the pass rates above which failing is unusual.
+	localHighRiskPassPercentage   = 98
+	localMediumRiskPassPercentage = 80
+	// localMaxFailures is the number of failures beyond which the job run is high risk whatever the tests.
+	localMaxFailures = 20
+)
+
+// isLocalSippyURL returns the pass rate file of a local sippy URL.
+func isLocalSippyURL(sippyURL string) (string, bool) {
+	if !strings.HasPrefix(sippyURL, LocalSippyURLPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(sippyURL, LocalSippyURLPrefix), true
+}
+
+// readTestPassRates reads the pass rate file of the local risk analysis.
+func readTestPassRates(path string) (map[string]TestPassRate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	passRates := []TestPassRate{}
+	if err := json.Unmarshal(content, &passRates); err != nil {
+		return nil, fmt.Errorf("failed to read test pass rates from %q: %w", path, err)
+	}
+	ret := map[string]TestPassRate{}
+	for _, passRate := range passRates {
+		ret[passRate.Name] = passRate
+	}
+	return ret, nil
+}
+
+// localRiskAnalysis analyzes the failures of the job run like sippy does: a failure of a test that usually passes
+// is risky, a failure of a test that often fails is not.
+func localRiskAnalysis(jobRun *ProwJobRun, passRates map[string]TestPassRate) *ProwJobRunRiskAnalysis {
+	analysis := &ProwJobRunRiskAnalysis{
+		ProwJobName:    jobRun.ProwJob.Name,
+		ProwJobRunID:   jobRun.ID,
+		Release:        jobRun.ClusterData.Release,
+		CompareRelease: jobRun.ClusterData.Release,
+		Tests:          []ProwJobRunTestRiskAnalysis{},
+		OpenBugs:       []Bug{},
+		OverallRisk: JobFailureRisk{
+			Level:              RiskLevelNone,
+			Reasons:            []string{},
+			JobRunTestCount:    jobRun.TestCount,
+			JobRunTestFailures: len(jobRun.Tests),
+		},
+	}
+
+	for _, test := range jobRun.Tests {
+		passRate, ok := passRates[test.Test.Name]
+		testAnalysis := ProwJobRunTestRiskAnalysis{
+			Name:     test.Test.Name,
+			TestId:   passRate.ID,
+			Risk:     testFailureRisk(passRate, ok),
+			OpenBugs: []Bug{},
+		}
+		analysis.Tests = append(analysis.Tests, testAnalysis)
+		if testAnalysis.Risk.Level.Level > analysis.OverallRisk.Level.Level {
+			analysis.OverallRisk.Level = testAnalysis.Risk.Level
+		}
+	}
+	sort.SliceStable(analysis.Tests, func(i, j int) bool {
+		return analysis.Tests[i].Risk.Level.Level > analysis.Tests[j].Risk.Level.Level
+	})
+
+	switch {
+	case len(jobRun.Tests) > localMaxFailures:
+		analysis.OverallRisk.Level = RiskLevelHigh
+		analysis.OverallRisk.Reasons = append(analysis.OverallRisk.Reasons,
+			fmt.Sprintf("%d tests failed in this run: High", len(jobRun.Tests)))
+	case len(jobRun.Tests) > 0:
+		analysis.OverallRisk.Reasons = append(analysis.OverallRisk.Reasons,
+			fmt.Sprintf("Maximum failed test risk: %s", analysis.OverallRisk.Level.Name))
+	}
+	return analysis
+}
+
+func testFailureRisk(passRate TestPassRate, found bool) TestFailureRisk {
+	if !found {
+		return TestFailureRisk{
+			Level:   RiskLevelUnknown,
+			Reasons: []string{"No historical pass rate for this test."},
+		}
+	}
+
+	risk := TestFailureRisk{
+		CurrentRuns:           passRate.CurrentRuns,
+		CurrentPasses:         passRate.CurrentSuccesses,
+		CurrentPassPercentage: passRate.CurrentPassPercentage,
+	}
+	reason := fmt.Sprintf("This test has passed %.2f%% of %d runs in the historical pass rates.", passRate.CurrentPassPercentage, passRate.CurrentRuns)
+	switch {
+	case passRate.CurrentRuns < localMinRuns:
+		risk.Level = RiskLevelUnknown
+		reason = fmt.Sprintf("This test has only %d runs in the historical pass rates.", passRate.CurrentRuns)
+	case passRate.CurrentPassPercentage >= localHighRiskPassPercentage:
+		risk.Level = RiskLevelHigh
+	case passRate.CurrentPassPercentage >= localMediumRiskPassPercentage:
+		risk.Level = RiskLevelMedium
+	default:
+		risk.Level = RiskLevelLow
+	}
+	risk.Reasons = []string{reason}
+	return risk
+}
+
+// requestLocalRiskAnalysis computes the risk analysis sippy would return for the job run from a pass rate file.
+func requestLocalRiskAnalysis(passRatesPath string, inputBytes []byte) ([]byte, error) {
+	jobRun := &ProwJobRun{}
+	if err := json.Unmarshal(inputBytes, jobRun); err != nil {
+		return nil, err
+	}
+	passRates, err := readTestPassRates(passRatesPath)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(localRiskAnalysis(jobRun, passRates))
+}
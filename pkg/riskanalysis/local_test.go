@@ -0,0 +1,69 @@
+package riskanalysis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithLocalSippy(t *testing.T) {
+	junitDir := t.TempDir()
+	jobRun := ProwJobRun{
+		ProwJob:   ProwJob{Name: "periodic-ci-openshift-release-master-ci-4.16-e2e-aws-ovn"},
+		TestCount: 100,
+		Tests: []ProwJobRunTest{
+			{Test: Test{Name: "stable test"}, Status: 12},
+			{Test: Test{Name: "flaky test"}, Status: 12},
+			{Test: Test{Name: "broken test"}, Status: 12},
+			{Test: Test{Name: "new test"}, Status: 12},
+		},
+	}
+	jobRun.ClusterData.Release = "4.16"
+	content, err := json.Marshal(jobRun)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(junitDir, testFailureSummaryFilePrefix+"_20240101-000000.json"), content, 0644))
+
+	passRates := []TestPassRate{
+		{ID: 1, Name: "stable test", CurrentRuns: 200, CurrentSuccesses: 199, CurrentPassPercentage: 99.5},
+		{ID: 2, Name: "flaky test", CurrentRuns: 200, CurrentSuccesses: 180, CurrentPassPercentage: 90},
+		{ID: 3, Name: "broken test", CurrentRuns: 200, CurrentSuccesses: 20, CurrentPassPercentage: 10},
+	}
+	content, err = json.Marshal(passRates)
+	require.NoError(t, err)
+	passRatesPath := filepath.Join(t.TempDir(), "pass-rates.json")
+	require.NoError(t, os.WriteFile(passRatesPath, content, 0644))
+
+	opt := &Options{JUnitDir: junitDir, SippyURL: LocalSippyURLPrefix + passRatesPath}
+	require.NoError(t, opt.Run())
+
+	content, err = os.ReadFile(filepath.Join(junitDir, raDataFile))
+	require.NoError(t, err)
+	analysis := &ProwJobRunRiskAnalysis{}
+	require.NoError(t, json.Unmarshal(content, analysis))
+	assert.Equal(t, RiskLevelHigh, analysis.OverallRisk.Level)
+	assert.Equal(t, 100, analysis.OverallRisk.Level.Level, "expected the risk levels of sippy")
+	assert.Equal(t, 4, analysis.OverallRisk.JobRunTestFailures)
+	levels := map[string]RiskLevel{}
+	for _, test := range analysis.Tests {
+		levels[test.Name] = test.Risk.Level
+	}
+	assert.Equal(t, map[string]RiskLevel{
+		"stable test": RiskLevelHigh,
+		"flaky test":  RiskLevelMedium,
+		"broken test": RiskLevelLow,
+		"new test":    RiskLevelUnknown,
+	}, levels)
+
+	html, err := os.ReadFile(filepath.Join(junitDir, "test-risk-analysis.html"))
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(html), `"Name":"stable test"`), "expected the local analysis in the html")
+	assert.False(t, strings.Contains(string(html), "TEST_RISK_ANALYSIS_JSON_GOES_HERE"))
+
+	_, err = os.Stat(filepath.Join(junitDir, raTestResultsFileName))
+	assert.NoError(t, err, "expected the test results to be written for the data loader")
+}
@@ -34,3 +34,59 @@ type ProwJobRunTest struct {
 	Suite  Suite
 	Status int // would like to use smallint here, but gorm auto-migrate breaks trying to change the type every start
 }
+
+// ProwJobRunRiskAnalysis is the risk analysis of the failed tests of a job run, as returned by the sippy API.
+type ProwJobRunRiskAnalysis struct {
+	ProwJobName    string
+	ProwJobRunID   int
+	Release        string
+	CompareRelease string
+	Tests          []ProwJobRunTestRiskAnalysis
+	OverallRisk    JobFailureRisk
+	OpenBugs       []Bug
+}
+
+type ProwJobRunTestRiskAnalysis struct {
+	Name     string
+	TestId   int
+	Risk     TestFailureRisk
+	OpenBugs []Bug
+}
+
+type TestFailureRisk struct {
+	Level                 RiskLevel
+	Reasons               []string
+	CurrentRuns           int
+	CurrentPasses         int
+	CurrentPassPercentage float64
+}
+
+type JobFailureRisk struct {
+	Level                  RiskLevel
+	Reasons                []string
+	JobRunTestCount        int
+	JobRunTestFailures     int
+	NeverStableJob         bool
+	HistoricalRunTestCount int
+}
+
+type RiskLevel struct {
+	Name  string
+	Level int
+}
+
+type Bug struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	URL     string `json:"url"`
+}
+
+// TestPassRate is a test as listed by the sippy tests API, only the fields the local risk analysis uses.
+type TestPassRate struct {
+	ID                    int     `json:"id"`
+	Name                  string  `json:"name"`
+	CurrentRuns           int     `json:"current_runs"`
+	CurrentSuccesses      int     `json:"current_successes"`
+	CurrentFlakes         int     `json:"current_flakes"`
+	CurrentPassPercentage float64 `json:"current_pass_percentage"`
+}
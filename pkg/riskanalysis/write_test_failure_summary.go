@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
@@ -18,6 +19,17 @@ import (
 // This is intended to be later submitted to sippy for a risk analysis of how unusual the
 // test failures were, but that final step is handled elsewhere.
 func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteResults *junitapi.JUnitTestSuite, wasMasterNodeUpdated, outputFileSubStr string) error {
+	restConfig, err := clusterinfo.GetMonitorRESTConfig()
+	if err != nil {
+		return err
+	}
+	clusterData := clusterinfo.CollectClusterData(restConfig, wasMasterNodeUpdated)
+	return WriteJobRunTestFailureSummaryForCluster(artifactDir, timeSuffix, finalSuiteResults, clusterData, outputFileSubStr)
+}
+
+// WriteJobRunTestFailureSummaryForCluster is WriteJobRunTestFailureSummary with cluster data that was already
+// collected, for instance when results from several processes are merged after the fact.
+func WriteJobRunTestFailureSummaryForCluster(artifactDir, timeSuffix string, finalSuiteResults *junitapi.JUnitTestSuite, clusterData platformidentification.ClusterData, outputFileSubStr string) error {
 
 	tests := map[string]*passFail{}
 
@@ -39,14 +51,10 @@ func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteRes
 	// If we can't parse this, we submit without it, it is not required.
 	jobRunID, _ := strconv.Atoi(os.Getenv("BUILD_ID"))
 
-	restConfig, err := clusterinfo.GetMonitorRESTConfig()
-	if err != nil {
-		return err
-	}
 	jr := ProwJobRun{
 		ID:          jobRunID,
 		ProwJob:     ProwJob{Name: os.Getenv("JOB_NAME")},
-		ClusterData: clusterinfo.CollectClusterData(restConfig, wasMasterNodeUpdated),
+		ClusterData: clusterData,
 		Tests:       []ProwJobRunTest{},
 		TestCount:   len(tests),
 	}
@@ -71,9 +79,13 @@ func WriteJobRunTestFailureSummary(artifactDir, timeSuffix string, finalSuiteRes
 	if err != nil {
 		return err
 	}
-	outputFile := filepath.Join(artifactDir, fmt.Sprintf("%s%s%s.json",
+	return ioutil.WriteFile(JobRunTestFailureSummaryPath(artifactDir, timeSuffix, outputFileSubStr), jsonContent, 0644)
+}
+
+// JobRunTestFailureSummaryPath is the file written by WriteJobRunTestFailureSummary.
+func JobRunTestFailureSummaryPath(artifactDir, timeSuffix, outputFileSubStr string) string {
+	return filepath.Join(artifactDir, fmt.Sprintf("%s%s%s.json",
 		testFailureSummaryFilePrefix, outputFileSubStr, timeSuffix))
-	return ioutil.WriteFile(outputFile, jsonContent, 0644)
 }
 
 // passFail is a simple struct to track test names which can appear more than once.
@@ -0,0 +1,81 @@
+package ginkgo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	origingenerated "github.com/openshift/origin/test/extended/util/annotate/generated"
+	originrules "github.com/openshift/origin/test/extended/util/annotate/rules"
+	"k8s.io/kubernetes/openshift-hack/e2e/annotate"
+	k8sgenerated "k8s.io/kubernetes/openshift-hack/e2e/annotate/generated"
+)
+
+const (
+	originAnnotateRules = "test/extended/util/annotate/rules/rules.go"
+	k8sAnnotateRules    = "k8s.io/kubernetes/openshift-hack/e2e/annotate/rules.go"
+)
+
+// TestAnnotation is a label the annotate rules appended to the name of a test.
+type TestAnnotation struct {
+	Label string
+	// Rule is the regex of the rule adding the label, when known.
+	Rule string
+	// Source describes where the label comes from.
+	Source string
+}
+
+func (a TestAnnotation) String() string {
+	if len(a.Rule) == 0 {
+		return fmt.Sprintf("%s added by %s", a.Label, a.Source)
+	}
+	return fmt.Sprintf("%s added by rule `%s` in %s", a.Label, a.Rule, a.Source)
+}
+
+// ExplainAnnotations returns the name a test is registered with, before the annotate rules appended their labels
+// to it, and those labels.  It returns false if no test of openshift-tests has the name, annotated or not.
+func ExplainAnnotations(name string) (string, []TestAnnotation, bool) {
+	rawNames := map[string]struct{}{}
+	for rawName := range origingenerated.Annotations {
+		rawNames[rawName] = struct{}{}
+	}
+	for rawName := range k8sgenerated.Annotations {
+		rawNames[rawName] = struct{}{}
+	}
+
+	for rawName := range rawNames {
+		originLabels, k8sLabels := origingenerated.Annotations[rawName], k8sgenerated.Annotations[rawName]
+		annotatedName := rawName + originLabels + k8sLabels
+		if name != rawName && name != annotatedName {
+			continue
+		}
+		var annotations []TestAnnotation
+		annotations = append(annotations, explainAnnotation(annotatedName, originLabels, originrules.TestMaps, originAnnotateRules)...)
+		annotations = append(annotations, explainAnnotation(annotatedName, k8sLabels, annotate.TestMaps, k8sAnnotateRules)...)
+		return rawName, annotations, true
+	}
+	return name, nil, false
+}
+
+// explainAnnotation finds the rules of testMaps adding the labels to the test.
+func explainAnnotation(name, labels string, testMaps map[string][]string, source string) []TestAnnotation {
+	var annotations []TestAnnotation
+	for _, label := range labelRegex.FindAllString(labels, -1) {
+		annotation := TestAnnotation{Label: label, Source: source}
+		for _, rule := range testMaps[label] {
+			if re, err := regexp.Compile(rule); err == nil && re.MatchString(name) {
+				annotation.Rule = rule
+				break
+			}
+		}
+		switch {
+		case len(annotation.Rule) > 0:
+		case label == "[Suite:k8s]":
+			annotation.Source = "default to the kubernetes e2e tests"
+		case strings.HasPrefix(label, "["+LabelSuite+":"):
+			annotation.Source = "default to the tests without a suite, depending on their [Serial] and [Conformance] labels"
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations
+}
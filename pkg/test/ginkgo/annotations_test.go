@@ -0,0 +1,50 @@
+package ginkgo
+
+import (
+	"strings"
+	"testing"
+
+	origingenerated "github.com/openshift/origin/test/extended/util/annotate/generated"
+	k8sgenerated "k8s.io/kubernetes/openshift-hack/e2e/annotate/generated"
+)
+
+func TestExplainAnnotations(t *testing.T) {
+	for source, generated := range map[string]map[string]string{
+		"origin":     origingenerated.Annotations,
+		"kubernetes": k8sgenerated.Annotations,
+	} {
+		explained := 0
+		for rawName, labels := range generated {
+			if !strings.Contains(labels, "[Skipped:") {
+				continue
+			}
+			for _, name := range []string{rawName, rawName + labels} {
+				gotRawName, annotations, ok := ExplainAnnotations(name)
+				if !ok || gotRawName != rawName {
+					t.Fatalf("expected %q to be annotated as %q, got %q", name, rawName, gotRawName)
+				}
+				annotatedName := rawName
+				for _, annotation := range annotations {
+					annotatedName += " " + annotation.Label
+					if strings.HasPrefix(annotation.Label, "[Skipped:") && len(annotation.Rule) == 0 {
+						t.Errorf("expected a rule for %s of %q", annotation.Label, rawName)
+					}
+				}
+				if annotatedName != rawName+labels {
+					t.Errorf("expected the annotations to rebuild %q, got %q", rawName+labels, annotatedName)
+				}
+			}
+			explained++
+			if explained == 5 {
+				break
+			}
+		}
+		if explained == 0 {
+			t.Fatalf("expected skipped %s tests", source)
+		}
+	}
+
+	if _, _, ok := ExplainAnnotations("[sig-test] not a test"); ok {
+		t.Errorf("expected an unknown test not to be annotated")
+	}
+}
@@ -0,0 +1,222 @@
+package ginkgo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// CheckpointFileName is the file in --junit-dir where the result of every test is appended as it completes.
+const CheckpointFileName = "openshift-tests-checkpoint.jsonl"
+
+// resumedTestOutput replaces the output of the tests resumed from the checkpoint, which only records their results.
+const resumedTestOutput = "the result of this test was recorded by an interrupted run, its output is in the log of that run"
+
+// checkpointEntry is one line of the checkpoint file.  Only the result is recorded, not the output, to keep the
+// checkpoint small.
+type checkpointEntry struct {
+	// Suite is the junit suite name of the run, results are only resumed into the same suite.
+	Suite     string
+	Name      string
+	Start     time.Time
+	End       time.Time
+	TestState TestState
+	// Retry is true for the results of the flake detection retries.
+	Retry bool
+}
+
+// testCheckpoint appends test results to the checkpoint file.  Every result is synced to disk before the next test
+// starts so that a killed process loses at most the tests that were running.
+type testCheckpoint struct {
+	lock  sync.Mutex
+	file  *os.File
+	suite string
+}
+
+// newTestCheckpoint opens the checkpoint file.  Unless appending for a resumed run, the results of a previous run
+// are discarded.
+func newTestCheckpoint(path, suite string, appendToExisting bool) (*testCheckpoint, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !appendToExisting {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &testCheckpoint{
+		file:  file,
+		suite: suite,
+	}, nil
+}
+
+// Record appends the result of the test.  Results without a terminal state are not recorded, the test runs again
+// when the run is resumed.
+func (c *testCheckpoint) Record(test *testCase, result *testRunResult) error {
+	switch result.testState {
+	case TestSucceeded, TestFailed, TestFailedTimeout, TestFlaked, TestSkipped:
+	default:
+		return nil
+	}
+
+	line, err := json.Marshal(checkpointEntry{
+		Suite:     c.suite,
+		Name:      test.name,
+		Start:     result.start,
+		End:       result.end,
+		TestState: result.testState,
+		Retry:     test.previous != nil,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return c.file.Sync()
+}
+
+func (c *testCheckpoint) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.file.Close()
+}
+
+// readCheckpoint reads the results recorded for the suite.  A truncated last line, left by a process killed while
+// writing it, is ignored.
+func readCheckpoint(path, suite string) ([]checkpointEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ret := []checkpointEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	var lastErr error
+	for scanner.Scan() {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		entry := checkpointEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			lastErr = fmt.Errorf("failed to read checkpoint %q: %w", path, err)
+			continue
+		}
+		if entry.Suite != suite {
+			continue
+		}
+		ret = append(ret, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// resumedTests are the results of a previous, interrupted, run of the suite.
+type resumedTests struct {
+	// completed have a result and must not run again.
+	completed []*testCase
+	// retries are the results of the flake detection retries, by test name, in the order of the attempts.
+	retries map[string][]checkpointEntry
+}
+
+// retry returns the recorded result of the attempt, starting at 1, to retry the test.
+func (r resumedTests) retry(testName string, attempt int) (checkpointEntry, bool) {
+	retries := r.retries[testName]
+	if attempt < 1 || attempt > len(retries) {
+		return checkpointEntry{}, false
+	}
+	return retries[attempt-1], true
+}
+
+// resumeTests returns the tests that still need to run and the tests completed by a previous run.  Completed tests
+// reuse the testCase of the suite, so they are reported exactly as if they had run in this process.  Results of
+// tests that are no longer part of the suite are dropped.
+func resumeTests(tests []*testCase, entries []checkpointEntry) ([]*testCase, resumedTests) {
+	resumed := resumedTests{
+		retries: map[string][]checkpointEntry{},
+	}
+	results := map[string]checkpointEntry{}
+	for _, entry := range entries {
+		if entry.Retry {
+			resumed.retries[entry.Name] = append(resumed.retries[entry.Name], entry)
+			continue
+		}
+		results[entry.Name] = entry
+	}
+
+	remaining := []*testCase{}
+	for _, test := range tests {
+		entry, ok := results[test.name]
+		if !ok {
+			remaining = append(remaining, test)
+			continue
+		}
+		delete(results, test.name)
+		mutateTestCaseWithResults(test, &testRunResultHandle{testRunResult: entry.testRunResult()})
+		resumed.completed = append(resumed.completed, test)
+	}
+	return remaining, resumed
+}
+
+func (e checkpointEntry) testRunResult() *testRunResult {
+	return &testRunResult{
+		name:            e.Name,
+		start:           e.Start,
+		end:             e.End,
+		testState:       e.TestState,
+		testOutputBytes: []byte(resumedTestOutput),
+	}
+}
+
+// recordResumedTestsInMonitor adds the start and finish intervals of the resumed tests, as they were recorded by the
+// interrupted run, so that the intervals used for the junits cover every test.
+func recordResumedTestsInMonitor(resumed resumedTests, monitorRecorder monitorapi.Recorder) {
+	for _, test := range resumed.completed {
+		result := &testRunResult{name: test.name, start: test.start, end: test.end, testState: testStateOf(test)}
+		monitorRecorder.AddIntervals(
+			newTestStartedInterval(test.name).Build(test.start, test.start),
+			newTestFinishedInterval(result).Build(test.end, test.end),
+		)
+	}
+}
+
+// earliestStart returns the start of the earliest resumed test, or the given time when it is earlier.
+func (r resumedTests) earliestStart(start time.Time) time.Time {
+	for _, test := range r.completed {
+		if !test.start.IsZero() && test.start.Before(start) {
+			start = test.start
+		}
+	}
+	return start
+}
+
+func testStateOf(test *testCase) TestState {
+	switch {
+	case test.flake:
+		return TestFlaked
+	case test.success:
+		return TestSucceeded
+	case test.skipped:
+		return TestSkipped
+	case test.timedOut:
+		return TestFailedTimeout
+	case test.failed:
+		return TestFailed
+	}
+	return TestUnknown
+}
@@ -0,0 +1,80 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckpoint_resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CheckpointFileName)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	checkpoint, err := newTestCheckpoint(path, "suite", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passed := &testCase{name: "passed"}
+	failed := &testCase{name: "failed"}
+	interrupted := &testCase{name: "interrupted"}
+	results := map[*testCase]*testRunResult{
+		passed:         {name: "passed", start: start, end: start.Add(time.Minute), testState: TestSucceeded},
+		failed:         {name: "failed", start: start, end: start.Add(2 * time.Minute), testState: TestFailed, testOutputBytes: []byte("boom")},
+		failed.Retry(): {name: "failed", start: start.Add(3 * time.Minute), end: start.Add(4 * time.Minute), testState: TestSucceeded},
+		interrupted:    {name: "interrupted", start: start, testState: TestUnknown},
+	}
+	for test, result := range results {
+		if err := checkpoint.Record(test, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := checkpoint.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a process killed while writing leaves a truncated line behind.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"Suite":"suite","Name":"pen`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	entries, err := readCheckpoint(path, "suite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherSuite, err := readCheckpoint(path, "other"); err != nil || len(otherSuite) != 0 {
+		t.Errorf("expected no results for another suite, got %v %v", otherSuite, err)
+	}
+
+	tests := []*testCase{{name: "passed"}, {name: "failed"}, {name: "interrupted"}, {name: "new"}}
+	remaining, resumed := resumeTests(tests, entries)
+
+	var remainingNames, completedNames []string
+	for _, test := range remaining {
+		remainingNames = append(remainingNames, test.name)
+	}
+	for _, test := range resumed.completed {
+		completedNames = append(completedNames, test.name)
+	}
+	if want := []string{"interrupted", "new"}; !reflect.DeepEqual(remainingNames, want) {
+		t.Errorf("expected %v to remain, got %v", want, remainingNames)
+	}
+	if want := []string{"passed", "failed"}; !reflect.DeepEqual(completedNames, want) {
+		t.Errorf("expected %v to be completed, got %v", want, completedNames)
+	}
+	if !tests[1].failed || string(tests[1].testOutputBytes) != resumedTestOutput || tests[1].duration != 2*time.Minute {
+		t.Errorf("expected the recorded failure to be resumed, got %#v", tests[1])
+	}
+	if retry, ok := resumed.retry("failed", 1); !ok || retry.TestState != TestSucceeded {
+		t.Errorf("expected the recorded retry to be resumed, got %v", resumed.retries)
+	}
+	if earliest := resumed.earliestStart(start.Add(time.Hour)); !earliest.Equal(start) {
+		t.Errorf("expected the earliest start to be %v, got %v", start, earliest)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
@@ -20,15 +21,22 @@ import (
 	"github.com/onsi/ginkgo/v2"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
 	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedalerts"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 	"github.com/openshift/origin/pkg/riskanalysis"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
@@ -69,6 +77,54 @@ type GinkgoRunSuiteOptions struct {
 
 	ExactMonitorTests   []string
 	DisableMonitorTests []string
+	// MonitorPhaseTimeouts are parsed by monitortestframework.ParsePhaseTimeouts.
+	MonitorPhaseTimeouts []string
+
+	// IntervalStorageDir, if set, stores monitor intervals in a disk-backed segment store under this directory
+	// instead of in memory.
+	IntervalStorageDir string
+
+	// Shard, of the form N/M, restricts the run to the Nth of M disjoint parts of the suite.
+	Shard string
+	// TestDurations are historical test duration tables and junit files of previous runs, see LoadTestDurations,
+	// used to balance the shards by test duration, to start the longest tests first and to predict how long the run
+	// takes.
+	TestDurations []string
+
+	// Checkpoint records the result of every test in --junit-dir as it completes, so that an interrupted run can be
+	// resumed.
+	Checkpoint bool
+	// Resume skips the tests whose result was recorded in the checkpoint of --junit-dir by an interrupted run and
+	// reports their recorded results instead.  The resumed run keeps checkpointing.
+	Resume bool
+
+	// Retries, when positive, replaces the RetryPolicy of the suite by a BackoffRetryPolicy of that many retries,
+	// configured by RetryBackoff, RetryTransientFailuresOnly and RetrySerially.
+	Retries                    int
+	RetryBackoff               time.Duration
+	RetryTransientFailuresOnly bool
+	RetrySerially              bool
+
+	// QuarantineFile is a QuarantineRegistry whose matching failures do not fail the run.
+	QuarantineFile string
+
+	// ExternalBinariesFile is an ExternalBinaryRegistry of test binaries to run in addition to DefaultExternalBinaries.
+	ExternalBinariesFile string
+
+	// ClusterState, if set, is the snapshot of the cluster a dry run selects the tests for, without reaching the
+	// cluster.
+	ClusterState *clusterdiscovery.ClusterState
+
+	// HistoricalDataFallback, when set, is the fallback chain of the historical disruption and alert data matchers of
+	// the monitor tests instead of historicaldata.DefaultFallbackChain, see historicaldata.ParseFallbackChain.
+	HistoricalDataFallback []string
+
+	// WriteParquet writes the monitor intervals, resources and junits as parquet files in addition to JSON and xml.
+	WriteParquet bool
+
+	// ClusterFailures groups similar test failures and correlates them with the Error intervals of the monitor in
+	// failure-clusters.json and failure-clusters.html in --junit-dir.
+	ClusterFailures bool
 }
 
 func NewGinkgoRunSuiteOptions(streams genericclioptions.IOStreams) *GinkgoRunSuiteOptions {
@@ -93,14 +149,75 @@ func (o *GinkgoRunSuiteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests,
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringSliceVar(&o.MonitorPhaseTimeouts, "monitor-phase-timeout", o.MonitorPhaseTimeouts, monitortestframework.PhaseTimeoutsFlagUsage)
+	flags.StringSliceVar(&o.HistoricalDataFallback, "historical-data-fallback", o.HistoricalDataFallback, "The relaxations of the job type tried, in order and cumulatively, when the historical disruption and alert data has nothing for the job type of the cluster, instead of only the previous release. Relaxations are previous-release, amd64, ha-to-single, ovn and minor-platforms.")
+	flags.BoolVar(&o.WriteParquet, "write-parquet", o.WriteParquet, "Also write the monitor intervals, disruption samples, tracked resources and monitor test results as parquet files in --junit-dir, for analysis tools.")
+	flags.StringVar(&o.IntervalStorageDir, "interval-storage-dir", o.IntervalStorageDir, "If set, monitor intervals are kept in an append-only segment store under this directory instead of in memory. Useful for long runs that record many intervals.")
+	flags.StringVar(&o.Shard, "shard", o.Shard, "Run only the Nth of M parts of the suite, as N/M. Every shard must be given the same suite and --test-durations. [Serial] tests are spread over the shards and run one at a time at the end of their shard. A shard without tests succeeds without running anything. Combine the results of the shards with merge-results.")
+	flags.BoolVar(&o.Checkpoint, "checkpoint", o.Checkpoint, fmt.Sprintf("Record the result, without the output, of every test in %s in --junit-dir as it completes, so that an interrupted run can be resumed with --resume.", CheckpointFileName))
+	flags.BoolVar(&o.Resume, "resume", o.Resume, fmt.Sprintf("Resume an interrupted run of the suite: tests whose result is recorded in %s in --junit-dir are not run again and their recorded results are reported. The monitor intervals the interrupted run wrote in --junit-dir are merged with the new ones. Implies --checkpoint.", CheckpointFileName))
+	flags.IntVar(&o.Retries, "retries", o.Retries, "How many times a failing test runs again to tell flakes from failures, when no more tests failed than the suite allows flakes. 0 keeps the retry policy of the suite, which retries every failure once.")
+	flags.DurationVar(&o.RetryBackoff, "retry-backoff", o.RetryBackoff, "How long to wait before the first retries of --retries, doubled before every further attempt.")
+	flags.BoolVar(&o.RetryTransientFailuresOnly, "retry-transient-failures-only", o.RetryTransientFailuresOnly, "Only retry, with --retries, the tests failing with a transient error such as connection refused or etcdserver: leader changed.")
+	flags.BoolVar(&o.RetrySerially, "retry-serially", o.RetrySerially, "Run the retries of --retries one at a time.")
+	flags.StringVar(&o.QuarantineFile, "quarantine-file", o.QuarantineFile, "A YAML registry of known flakes, by test name regex, release, platform and topology, with an expiry date and an owning jira. Failures of the tests matching an unexpired entry are reported in the junit, named by a QuarantinedTest suite property, but do not fail the run. Check the registry with quarantine lint.")
+	flags.StringVar(&o.ExternalBinariesFile, "external-binaries", o.ExternalBinariesFile, "A YAML list of test binaries to extract from the images of the release payload, by image tag and path, whose tests are added to the suites in addition to the k8s-tests. Ignored when OPENSHIFT_SKIP_EXTERNAL_TESTS is set.")
+	flags.BoolVar(&o.ClusterFailures, "cluster-failures", o.ClusterFailures, "Group the failed tests by the similarity of their failure output, once UIDs, timestamps and pod suffixes are removed, and correlate every group with the overlapping Error intervals of the monitor. Written to failure-clusters.json and failure-clusters.html in --junit-dir.")
+	flags.StringSliceVar(&o.TestDurations, "test-durations", o.TestDurations, "Historical test durations: .json files listing TestName, JobRuns and P50 in seconds, and junit files of previous runs, for instance junit_e2e_*.xml, whose durations override the .json ones. Longer tests are started first to shorten the run, the predicted and actual run durations are printed, and --shard is balanced by test duration instead of test count.")
+}
+
+// retryPolicy is the BackoffRetryPolicy configured by --retries and the flags refining it.
+func (o *GinkgoRunSuiteOptions) retryPolicy() RetryPolicy {
+	policy := &BackoffRetryPolicy{
+		MaxRetries:     o.Retries,
+		InitialBackoff: o.RetryBackoff,
+		RunSerially:    o.RetrySerially,
+	}
+	if o.RetryTransientFailuresOnly {
+		policy.RetryableFailures = TransientFailures
+	}
+	return policy
 }
 
 func (o *GinkgoRunSuiteOptions) Validate() error {
+	if o.Retries < 0 {
+		return fmt.Errorf("--retries must not be negative, got %d", o.Retries)
+	}
+	if o.Retries == 0 && (o.RetryBackoff != 0 || o.RetryTransientFailuresOnly || o.RetrySerially) {
+		return fmt.Errorf("--retry-backoff, --retry-transient-failures-only and --retry-serially require --retries")
+	}
 	switch o.ClusterStabilityDuringTest {
 	case "", string(Stable), string(Disruptive):
 	default:
 		return fmt.Errorf("unknown --cluster-stability, %q, expected Stable or Disruptive", o.ClusterStabilityDuringTest)
 	}
+	if _, err := monitortestframework.ParsePhaseTimeouts(o.MonitorPhaseTimeouts); err != nil {
+		return fmt.Errorf("invalid --monitor-phase-timeout: %w", err)
+	}
+	if len(o.ExternalBinariesFile) > 0 {
+		if _, err := LoadExternalBinaries(o.ExternalBinariesFile); err != nil {
+			return fmt.Errorf("invalid --external-binaries: %w", err)
+		}
+	}
+	if len(o.QuarantineFile) > 0 {
+		if _, err := LoadQuarantineRegistry(o.QuarantineFile); err != nil {
+			return fmt.Errorf("invalid --quarantine-file: %w", err)
+		}
+	}
+	if len(o.Shard) > 0 {
+		if _, err := ParseShard(o.Shard); err != nil {
+			return fmt.Errorf("invalid --shard: %w", err)
+		}
+	}
+	if _, err := historicaldata.ParseFallbackChain(o.HistoricalDataFallback); err != nil {
+		return fmt.Errorf("invalid --historical-data-fallback: %w", err)
+	}
+	if o.Resume && len(o.JUnitDir) == 0 {
+		return fmt.Errorf("--resume requires --junit-dir")
+	}
+	if o.Checkpoint && len(o.JUnitDir) == 0 {
+		return fmt.Errorf("--checkpoint requires --junit-dir")
+	}
 	return nil
 }
 
@@ -125,6 +242,16 @@ func max(a, b int) int {
 func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, monitorTestInfo monitortestframework.MonitorTestInitializationInfo, upgrade bool) error {
 	ctx := context.Background()
 
+	if len(o.HistoricalDataFallback) > 0 {
+		chain, err := historicaldata.ParseFallbackChain(o.HistoricalDataFallback)
+		if err != nil {
+			return fmt.Errorf("invalid --historical-data-fallback: %w", err)
+		}
+		// only the matchers of the monitor tests use the chain, historicaldata.DefaultFallbackChain is left alone
+		allowedbackenddisruption.GetCurrentResults().FallbackChain = chain
+		allowedalerts.GetHistoricalData().FallbackChain = chain
+	}
+
 	tests, err := testsForSuite()
 	if err != nil {
 		return fmt.Errorf("failed reading origin test suites: %w", err)
@@ -133,26 +260,30 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	fmt.Fprintf(o.Out, "found %d tests for suite\n", len(tests))
 
 	var fallbackSyntheticTestResult []*junitapi.JUnitTestCase
-	if len(os.Getenv("OPENSHIFT_SKIP_EXTERNAL_TESTS")) == 0 {
+	if o.ClusterState != nil {
+		// the external binaries are extracted with the pull secret of the cluster, snapshot-cluster records their tests
+		externalTests := externalTestsFromClusterState(o.ClusterState)
+		if len(externalTests) == 0 {
+			fmt.Fprintf(o.ErrOut, "warning: The cluster state records no tests of external binaries, the tests they provide, for instance the k8s tests of %s, are missing from the selection\n", DefaultExternalBinaries[0])
+		}
+		tests = append(withoutReplacedTests(tests, externalTests), externalTests...)
+	} else if len(os.Getenv("OPENSHIFT_SKIP_EXTERNAL_TESTS")) == 0 {
 		buf := &bytes.Buffer{}
-		fmt.Fprintf(buf, "Attempting to pull tests from external binary...\n")
-		externalTests, err := externalTestsForSuite(ctx)
-		if err == nil {
-			filteredTests := []*testCase{}
-			for _, test := range tests {
-				// tests contains all the tests "registered" in openshif-tests binary,
-				// this also includes vendored k8s tests, since this path assumes we're
-				// using external binary to run these tests we need to remove them
-				// from the final lists, which contains:
-				// 1. origin tests, only
-				// 2. k8s tests, coming from external binary
-				if !strings.Contains(test.name, "[Suite:k8s]") {
-					filteredTests = append(filteredTests, test)
-				}
-			}
-			tests = append(filteredTests, externalTests...)
-			fmt.Fprintf(buf, "Got %d tests from external binary\n", len(externalTests))
-		} else {
+		fmt.Fprintf(buf, "Attempting to pull tests from external binaries...\n")
+		externalBinaries, err := externalBinariesWith(o.ExternalBinariesFile)
+		if err != nil {
+			return fmt.Errorf("failed reading --external-binaries: %w", err)
+		}
+		externalTests, err := externalTestsForSuite(ctx, externalBinaries)
+		// tests contains all the tests "registered" in openshif-tests binary,
+		// this also includes vendored tests, for instance the k8s tests. The
+		// vendored tests replaced by the tests of an external binary are removed
+		// from the final lists, which contains:
+		// 1. origin tests, and the vendored tests of the binaries that failed
+		// 2. the tests coming from the external binaries
+		tests = append(withoutReplacedTests(tests, externalTests), externalTests...)
+		fmt.Fprintf(buf, "Got %d tests from external binaries\n", len(externalTests))
+		if err != nil {
 			fmt.Fprintf(buf, "Falling back to built-in suite, failed reading external test suites: %v\n", err)
 			// adding this test twice (one failure here, and success below) will
 			// ensure it gets picked as flake further down in synthetic tests processing
@@ -181,13 +312,38 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	r := rand.New(rand.NewSource(suiteConfig.RandomSeed))
 	r.Shuffle(len(tests), func(i, j int) { tests[i], tests[j] = tests[j], tests[i] })
 
+	candidates := tests
 	tests = suite.Filter(tests)
+	if o.DryRun && len(suite.Selector) > 0 {
+		writeSelectionExplanation(o.ErrOut, suite, candidates)
+	}
 	if len(tests) == 0 {
 		return fmt.Errorf("suite %q does not contain any tests", suite.Name)
 	}
 
 	fmt.Fprintf(o.Out, "found %d filtered tests\n", len(tests))
 
+	testDurations, err := LoadTestDurations(o.TestDurations)
+	if err != nil {
+		return fmt.Errorf("failed reading --test-durations: %w", err)
+	}
+
+	var shard *Shard
+	if len(o.Shard) > 0 {
+		parsedShard, err := ParseShard(o.Shard)
+		if err != nil {
+			return fmt.Errorf("invalid --shard: %w", err)
+		}
+		shard = &parsedShard
+		tests = shardTests(tests, parsedShard, testDurations)
+		if len(tests) == 0 {
+			// more shards than tests, the other shards run them all.
+			fmt.Fprintf(o.Out, "shard %s of suite %q does not contain any tests\n", parsedShard, suite.Name)
+			return nil
+		}
+		fmt.Fprintf(o.Out, "found %d tests for shard %s\n", len(tests), parsedShard)
+	}
+
 	count := o.Count
 	if count == 0 {
 		count = suite.Count
@@ -209,10 +365,13 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	testRunnerContext := newCommandContext(o.AsEnv(), timeout)
 
 	if o.PrintCommands {
-		newParallelTestQueue(testRunnerContext).OutputCommands(ctx, tests, o.Out)
+		newParallelTestQueue(testRunnerContext, testDurations).OutputCommands(ctx, tests, o.Out)
 		return nil
 	}
 	if o.DryRun {
+		if o.ClusterState != nil && o.ClusterState.ServerVersion != nil {
+			tests = withoutRebaseTests(o.ErrOut, o.ClusterState.ServerVersion, tests)
+		}
 		for _, test := range sortedTests(tests) {
 			fmt.Fprintf(o.Out, "%q\n", test.name)
 		}
@@ -230,6 +389,21 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		return err
 	}
 
+	var quarantines *QuarantineRegistry
+	var quarantineCluster platformidentification.JobType
+	if len(o.QuarantineFile) > 0 {
+		quarantines, err = LoadQuarantineRegistry(o.QuarantineFile)
+		if err != nil {
+			return fmt.Errorf("failed reading --quarantine-file: %w", err)
+		}
+		jobType, err := platformidentification.GetJobType(ctx, restConfig)
+		if err != nil {
+			fmt.Fprintf(o.ErrOut, "warning: Unable to identify the cluster, only the quarantines not restricted to releases, platforms or topologies apply: %v\n", err)
+		} else {
+			quarantineCluster = *jobType
+		}
+	}
+
 	if len(o.JUnitDir) > 0 {
 		if _, err := os.Stat(o.JUnitDir); err != nil {
 			if !os.IsNotExist(err) {
@@ -241,6 +415,38 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
+	var resumed resumedTests
+	var resumedIntervals monitorapi.Intervals
+	var checkpoint *testCheckpoint
+	if o.Checkpoint || o.Resume {
+		checkpointPath := filepath.Join(o.JUnitDir, CheckpointFileName)
+		if o.Resume {
+			if count != 1 {
+				return fmt.Errorf("--resume is only supported when every test runs once, the count is %d", count)
+			}
+			entries, err := readCheckpoint(checkpointPath, junitSuiteName)
+			if err != nil {
+				return fmt.Errorf("could not resume: %w", err)
+			}
+			tests, resumed = resumeTests(tests, entries)
+			fmt.Fprintf(o.Out, "resuming: %d tests already have a result, %d tests remain\n", len(resumed.completed), len(tests))
+			if len(resumed.completed) > 0 {
+				resumedIntervals, err = monitor.LoadInterruptedRunIntervals(o.JUnitDir, resumed.earliestStart(time.Now()))
+				if err != nil {
+					return fmt.Errorf("could not resume the monitor intervals: %w", err)
+				}
+				if len(resumedIntervals) == 0 {
+					fmt.Fprintf(o.ErrOut, "warning: The interrupted run wrote no monitor intervals in --junit-dir, only the intervals of its tests are kept\n")
+				}
+			}
+		}
+		checkpoint, err = newTestCheckpoint(checkpointPath, junitSuiteName, o.Resume)
+		if err != nil {
+			return fmt.Errorf("could not create the checkpoint: %w", err)
+		}
+		defer checkpoint.Close()
+	}
+
 	parallelism := o.Parallelism
 	if parallelism == 0 {
 		parallelism = suite.Parallelism
@@ -272,13 +478,30 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		logrus.Errorf("Error getting monitor tests: %v", err)
 	}
 
-	monitorEventRecorder := monitor.NewRecorder()
-	m := monitor.NewMonitor(
-		monitorEventRecorder,
-		restConfig,
-		o.JUnitDir,
-		monitorTests,
-	)
+	monitorEventRecorder, closeRecorderFn, err := monitor.NewRecorderForStorage(o.IntervalStorageDir)
+	if err != nil {
+		return err
+	}
+	defer closeRecorderFn()
+	var m monitor.Interface
+	if len(resumedIntervals) > 0 {
+		m = monitor.NewResumedMonitor(
+			monitorEventRecorder,
+			restConfig,
+			o.JUnitDir,
+			monitorTests,
+			monitor.Options{WriteParquet: o.WriteParquet},
+			resumedIntervals,
+		)
+	} else {
+		m = monitor.NewMonitor(
+			monitorEventRecorder,
+			restConfig,
+			o.JUnitDir,
+			monitorTests,
+			monitor.Options{WriteParquet: o.WriteParquet},
+		)
+	}
 	if err := m.Start(ctx); err != nil {
 		return err
 	}
@@ -298,22 +521,19 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}
 	testOutputLock := &sync.Mutex{}
 	testOutputConfig := newTestOutputConfig(testOutputLock, o.Out, monitorEventRecorder, includeSuccess)
+	testOutputConfig.checkpoint = checkpoint
+	if len(resumedIntervals) == 0 {
+		// the intervals of the interrupted run already have the intervals of its tests.
+		recordResumedTestsInMonitor(resumed, monitorEventRecorder)
+	}
 
-	early, notEarly := splitTests(tests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Early]")
-	})
+	early, notEarly := splitTests(tests, earlyTestsSelector.matchesTest)
 
-	late, primaryTests := splitTests(notEarly, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Late]")
-	})
+	late, primaryTests := splitTests(notEarly, lateTestsSelector.matchesTest)
 
-	kubeTests, openshiftTests := splitTests(primaryTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[Suite:k8s]")
-	})
+	kubeTests, openshiftTests := splitTests(primaryTests, kubeTestsSelector.matchesTest)
 
-	storageTests, kubeTests := splitTests(kubeTests, func(t *testCase) bool {
-		return strings.Contains(t.name, "[sig-storage]")
-	})
+	storageTests, kubeTests := splitTests(kubeTests, storageTestsSelector.matchesTest)
 
 	mustGatherTests, openshiftTests := splitTests(openshiftTests, func(t *testCase) bool {
 		return strings.Contains(t.name, "[sig-cli] oc adm must-gather")
@@ -345,7 +565,7 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	tests = nil
 
 	// run our Early tests
-	q := newParallelTestQueue(testRunnerContext)
+	q := newParallelTestQueue(testRunnerContext, testDurations)
 	q.Execute(testCtx, early, parallelism, testOutputConfig, abortFn)
 	tests = append(tests, early...)
 
@@ -398,6 +618,9 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
+	// the tests completed by the interrupted run count as if they had run now, including for the retries below.
+	tests = append(tests, resumed.completed...)
+
 	// calculate the effective test set we ran, excluding any incompletes
 	tests, _ = splitTests(tests, func(t *testCase) bool { return t.success || t.flake || t.failed || t.skipped })
 
@@ -409,67 +632,47 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	pass, fail, skip, failing := summarizeTests(tests)
 
+	if quarantined := quarantineFailures(tests, quarantines, quarantineCluster, time.Now()); len(quarantined) > 0 {
+		pass, fail, skip, failing = summarizeTests(tests)
+		names := sets.NewString(testNames(quarantined)...).List()
+		fmt.Fprintf(o.Out, "Quarantined tests, their failures do not fail the run:\n\n%s\n\n", strings.Join(names, "\n"))
+	}
+
 	// attempt to retry failures to do flake detection
 	if fail > 0 && fail <= suite.MaximumAllowedFlakes {
-		var retries []*testCase
-
-		// Make a copy of the all failing tests (subject to the max allowed flakes) so we can have
-		// a list of tests to retry.
-		for _, test := range failing {
-			retry := test.Retry()
-			retries = append(retries, retry)
-			if len(retries) > suite.MaximumAllowedFlakes {
-				break
-			}
+		retryPolicy := suite.retryPolicy()
+		if o.Retries > 0 {
+			retryPolicy = o.retryPolicy()
 		}
-
-		fmt.Fprintf(o.Out, "Retry count: %d\n", len(retries))
-
-		// Run the tests in the retries list.
-		q := newParallelTestQueue(testRunnerContext)
-		q.Execute(testCtx, retries, parallelism, testOutputConfig, abortFn)
-
-		var flaky, skipped []string
-		var repeatFailures []*testCase
-		for _, test := range retries {
-			if test.success {
-				flaky = append(flaky, test.name)
-			} else if test.skipped {
-				skipped = append(skipped, test.name)
-			} else {
-				repeatFailures = append(repeatFailures, test)
-			}
+		retryParallelism := parallelism
+		if retryPolicy.Serial() {
+			retryParallelism = 1
 		}
 
-		// Add the list of retries into the list of all tests.
-		for _, retry := range retries {
-			if retry.flake {
-				// Retry tests that flaked are omitted so that the original test is counted as a failure.
-				fmt.Fprintf(o.Out, "Ignoring retry that returned a flake, original failure is authoritative for test: %s\n", retry.name)
-				continue
-			}
-			tests = append(tests, retry)
+		execute := func(ctx context.Context, retries []*testCase, parallelism int) {
+			q := newParallelTestQueue(testRunnerContext, testDurations)
+			q.Execute(ctx, retries, parallelism, testOutputConfig, abortFn)
 		}
+		retries, flaky, skipped, repeatFailures := retryFailingTests(testCtx, o.Out, retryPolicy, failing, resumed, retryParallelism, execute)
+		tests = append(tests, retries...)
+
+		failing = repeatFailures
 		if len(flaky) > 0 {
-			failing = repeatFailures
 			sort.Strings(flaky)
 			fmt.Fprintf(o.Out, "Flaky tests:\n\n%s\n\n", strings.Join(flaky, "\n"))
 		}
 		if len(skipped) > 0 {
 			// If a retry test got skipped, it means we very likely failed a precondition in the first failure, so
 			// we need to remove the failure case.
+			skippedNames := sets.NewString(skipped...)
 			var withoutPreconditionFailures []*testCase
-		testLoop:
 			for _, t := range tests {
-				for _, st := range skipped {
-					if t.name == st && t.failed {
-						continue testLoop
-					}
-					withoutPreconditionFailures = append(withoutPreconditionFailures, t)
+				if skippedNames.Has(t.name) && t.failed {
+					continue
 				}
+				withoutPreconditionFailures = append(withoutPreconditionFailures, t)
 			}
 			tests = withoutPreconditionFailures
-			failing = repeatFailures
 			sort.Strings(skipped)
 			fmt.Fprintf(o.Out, "Skipped tests that failed a precondition:\n\n%s\n\n", strings.Join(skipped, "\n"))
 
@@ -493,7 +696,8 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	// default is empty string as that is what entries prior to adding this will have
 	wasMasterNodeUpdated := ""
-	if events := monitorEventRecorder.Intervals(start, end); len(events) > 0 {
+	events := monitorEventRecorder.Intervals(resumed.earliestStart(start), end)
+	if len(events) > 0 {
 		buf := &bytes.Buffer{}
 		if !upgrade {
 			// the current mechanism for external binaries does not support upgrade
@@ -552,6 +756,20 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 
 	if len(o.JUnitDir) > 0 {
 		finalSuiteResults := generateJUnitTestSuiteResults(junitSuiteName, duration, tests, syntheticTestResults...)
+		finalSuiteResults.Properties = append(finalSuiteResults.Properties, externalBinaryProperties(tests)...)
+		finalSuiteResults.Properties = append(finalSuiteResults.Properties, quarantinedTestProperties(tests)...)
+		if shard != nil {
+			finalSuiteResults.Properties = append(finalSuiteResults.Properties, &junitapi.TestSuiteProperty{
+				Name:  ShardProperty,
+				Value: shard.String(),
+			})
+		}
+		testContexts := BuildTestContexts(finalSuiteResults, events)
+		attachTestContexts(finalSuiteResults, testContexts)
+		if err := WriteTestContexts(o.JUnitDir, testContexts); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write test contexts: %v\n", err)
+		}
+
 		if err := writeJUnitReport(finalSuiteResults, "junit_e2e", timeSuffix, o.JUnitDir, o.ErrOut); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e JUnit xml results: %v", err)
 		}
@@ -559,6 +777,13 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		if err := riskanalysis.WriteJobRunTestFailureSummary(o.JUnitDir, timeSuffix, finalSuiteResults, wasMasterNodeUpdated, ""); err != nil {
 			fmt.Fprintf(o.Out, "error: Unable to write e2e job run failures summary: %v", err)
 		}
+
+		if o.ClusterFailures {
+			clusters := ClusterFailures([]*junitapi.JUnitTestSuite{finalSuiteResults}, events)
+			if err := WriteFailureClusters(o.JUnitDir, clusters); err != nil {
+				fmt.Fprintf(o.ErrOut, "error: Unable to write failure clusters: %v\n", err)
+			}
+		}
 	}
 
 	if fail > 0 {
@@ -588,26 +813,59 @@ func (o *GinkgoRunSuiteOptions) filterOutRebaseTests(restConfig *rest.Config, te
 	if err != nil {
 		return nil, err
 	}
+	return withoutRebaseTests(o.Out, serverVersion, tests), nil
+}
+
+func withoutRebaseTests(out io.Writer, serverVersion *version.Info, tests []*testCase) []*testCase {
+	matches := make([]*testCase, 0, len(tests))
+	for _, test := range tests {
+		if len(RebaseExclusion(serverVersion, test.name)) > 0 {
+			fmt.Fprintf(out, "Skipping %q due to rebase in-progress\n", test.name)
+			continue
+		}
+		matches = append(matches, test)
+	}
+	return matches
+}
+
+// rebaseExclusions are the tests skipped while a k8s rebase is in progress.
+// Below list should only be filled in when we're trying to land k8s rebase.
+// Don't pile them up!
+var rebaseExclusions = []string{}
+
+// RebaseExclusion returns the entry of the rebase exclusions skipping the test on a server of the version, or an
+// empty string when the test runs.
+func RebaseExclusion(serverVersion *version.Info, name string) string {
 	// TODO: this version along with below exclusions lists needs to be updated
 	// for the rebase in-progress.
 	if !strings.HasPrefix(serverVersion.Minor, "30") {
-		return tests, nil
+		return ""
 	}
+	for _, excl := range rebaseExclusions {
+		if strings.Contains(name, excl) {
+			return excl
+		}
+	}
+	return ""
+}
 
-	// Below list should only be filled in when we're trying to land k8s rebase.
-	// Don't pile them up!
-	exclusions := []string{}
-
-	matches := make([]*testCase, 0, len(tests))
-outerLoop:
-	for _, test := range tests {
-		for _, excl := range exclusions {
-			if strings.Contains(test.name, excl) {
-				fmt.Fprintf(o.Out, "Skipping %q due to rebase in-progress\n", test.name)
-				continue outerLoop
-			}
+// writeSelectionExplanation prints how many of the tests matching the label selector of the suite run, and why the
+// others are excluded.
+func writeSelectionExplanation(out io.Writer, suite *TestSuite, tests []*testCase) {
+	selected := 0
+	var exclusions []string
+	for _, test := range sortedTests(tests) {
+		if !suite.Selector.Matches(test.Labels()) {
+			continue
 		}
-		matches = append(matches, test)
+		if exclusion := suite.Exclusion(test.name); len(exclusion) > 0 {
+			exclusions = append(exclusions, fmt.Sprintf("excluded %q: %s", test.name, exclusion))
+			continue
+		}
+		selected++
+	}
+	fmt.Fprintf(out, "%d of the %d tests matching %q are selected\n", selected, selected+len(exclusions), suite.Selector)
+	for _, exclusion := range exclusions {
+		fmt.Fprintln(out, exclusion)
 	}
-	return matches, nil
 }
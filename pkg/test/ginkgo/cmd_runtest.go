@@ -104,6 +104,7 @@ func (o *TestOptions) Run(args []string) error {
 			restConfig,
 			"",
 			monitorTests,
+			monitor.Options{},
 		)
 		if err := m.Start(ctx); err != nil {
 			return err
@@ -0,0 +1,149 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TestDuration is a row of a historical test duration table.  Like the query_results.json files of the monitor
+// tests, the table is the JSON output of a BigQuery query and durations are strings holding seconds.
+type TestDuration struct {
+	TestName string
+	Release  string
+	JobRuns  int64
+	P50      string
+	P95      string
+}
+
+// LoadTestDurations reads the duration of the tests from historical duration tables, the .json files, and the junit
+// files of previous runs.  The durations measured by the junit files override the historical ones.
+func LoadTestDurations(paths []string) (map[string]time.Duration, error) {
+	ret := map[string]time.Duration{}
+	junitFiles := []string{}
+	for _, path := range paths {
+		if filepath.Ext(path) != ".json" {
+			junitFiles = append(junitFiles, path)
+			continue
+		}
+		tableDurations, err := loadTestDurationTable(path)
+		if err != nil {
+			return nil, err
+		}
+		for name, duration := range tableDurations {
+			ret[name] = duration
+		}
+	}
+	junitDurations, err := loadJUnitTestDurations(junitFiles)
+	if err != nil {
+		return nil, err
+	}
+	for name, duration := range junitDurations {
+		ret[name] = duration
+	}
+	return ret, nil
+}
+
+// loadTestDurationTable reads a JSON list of TestDuration and returns the median duration of every test.  When a
+// test has several rows, for instance one per release, the row with the most job runs wins.
+func loadTestDurationTable(path string) (map[string]time.Duration, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rows := []TestDuration{}
+	if err := json.Unmarshal(content, &rows); err != nil {
+		return nil, fmt.Errorf("failed to read test durations from %q: %w", path, err)
+	}
+
+	ret := map[string]time.Duration{}
+	jobRuns := map[string]int64{}
+	for _, row := range rows {
+		if len(row.TestName) == 0 {
+			continue
+		}
+		if existing, ok := jobRuns[row.TestName]; ok && existing >= row.JobRuns {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(row.P50, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read test durations from %q: test %q has an invalid P50: %w", path, row.TestName, err)
+		}
+		ret[row.TestName] = time.Duration(seconds * float64(time.Second))
+		jobRuns[row.TestName] = row.JobRuns
+	}
+	return ret, nil
+}
+
+// estimateDurations returns the duration of a test: its known duration, or the average known duration of the tests,
+// a second when none is known.  It also returns the number of tests with a known duration.
+func estimateDurations(tests []*testCase, durations map[string]time.Duration) (func(test *testCase) time.Duration, int) {
+	var known time.Duration
+	knownCount := 0
+	for _, test := range tests {
+		if duration, ok := durations[test.name]; ok {
+			known += duration
+			knownCount++
+		}
+	}
+	defaultDuration := time.Second
+	if knownCount > 0 && known > 0 {
+		defaultDuration = known / time.Duration(knownCount)
+	}
+	return func(test *testCase) time.Duration {
+		if duration, ok := durations[test.name]; ok {
+			return duration
+		}
+		return defaultDuration
+	}, knownCount
+}
+
+// orderByDuration returns the tests longest first, which keeps a few long tests from starting last and stretching
+// the run.  Tests without a known duration are assumed to take the average known duration.  The sort is stable, so
+// tests of equal duration keep the shuffled order of the suite and a run stays reproducible from its random seed.
+func orderByDuration(tests []*testCase, durations map[string]time.Duration) []*testCase {
+	if len(durations) == 0 {
+		return tests
+	}
+	durationOf, _ := estimateDurations(tests, durations)
+
+	ordered := make([]*testCase, len(tests))
+	copy(ordered, tests)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return durationOf(ordered[i]) > durationOf(ordered[j])
+	})
+	return ordered
+}
+
+// predictDuration simulates execute: the parallel tests, in the order they are queued, each go to the worker that
+// frees up first, then the serial tests run one after the other.  It returns the predicted duration and the number
+// of tests with a known duration, the others are assumed to take the average known duration.
+func predictDuration(tests []*testCase, durations map[string]time.Duration, parallelism int) (time.Duration, int) {
+	durationOf, knownCount := estimateDurations(tests, durations)
+
+	serial, parallel := splitTests(tests, isSerialTest)
+	workers := make([]time.Duration, max(1, parallelism))
+	for _, test := range orderByDuration(parallel, durations) {
+		next := 0
+		for i := range workers {
+			if workers[i] < workers[next] {
+				next = i
+			}
+		}
+		workers[next] += durationOf(test)
+	}
+	var predicted time.Duration
+	for _, worker := range workers {
+		if worker > predicted {
+			predicted = worker
+		}
+	}
+	for _, test := range serial {
+		predicted += durationOf(test)
+	}
+	return predicted, knownCount
+}
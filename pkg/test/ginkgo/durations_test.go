@@ -0,0 +1,102 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadTestDurations_table(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "durations.json")
+	content := `[
+  {"TestName": "a", "Release": "4.16", "JobRuns": 10, "P50": "30.5", "P95": "60.0"},
+  {"TestName": "a", "Release": "4.15", "JobRuns": 400, "P50": "20.0", "P95": "50.0"},
+  {"TestName": "b", "Release": "4.16", "JobRuns": 4, "P50": "600", "P95": "900"}
+]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadTestDurations([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Duration{
+		"a": 20 * time.Second,
+		"b": 10 * time.Minute,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLoadTestDurations_junitOverridesTable(t *testing.T) {
+	dir := t.TempDir()
+	table := filepath.Join(dir, "durations.json")
+	if err := os.WriteFile(table, []byte(`[{"TestName": "a", "JobRuns": 10, "P50": "30"}, {"TestName": "b", "JobRuns": 10, "P50": "60"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	junit := filepath.Join(dir, "junit_e2e.xml")
+	if err := os.WriteFile(junit, []byte(`<testsuite name="openshift-tests"><testcase name="a" time="5"></testcase></testsuite>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadTestDurations([]string{junit, table})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]time.Duration{
+		"a": 5 * time.Second,
+		"b": time.Minute,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func Test_orderByDuration(t *testing.T) {
+	tests := []*testCase{{name: "short"}, {name: "unknown"}, {name: "long"}, {name: "also-short"}}
+	durations := map[string]time.Duration{
+		"short":      time.Minute,
+		"long":       10 * time.Minute,
+		"also-short": time.Minute,
+	}
+
+	var got []string
+	for _, test := range orderByDuration(tests, durations) {
+		got = append(got, test.name)
+	}
+	// unknown takes the average of 4m, ties keep the given order.
+	want := []string{"long", "unknown", "short", "also-short"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if tests[0].name != "short" {
+		t.Errorf("expected the given tests to be left alone")
+	}
+}
+
+func Test_predictDuration(t *testing.T) {
+	tests := []*testCase{
+		{name: "a"}, {name: "b"}, {name: "c"}, {name: "d"},
+		{name: "serial [Serial]"},
+	}
+	durations := map[string]time.Duration{
+		"a":               time.Minute,
+		"b":               time.Minute,
+		"c":               2 * time.Minute,
+		"d":               4 * time.Minute,
+		"serial [Serial]": 3 * time.Minute,
+	}
+
+	// d on one worker, c, a and b on the other, then the serial test.
+	predicted, knownCount := predictDuration(tests, durations, 2)
+	if predicted != 7*time.Minute {
+		t.Errorf("expected 7m, got %v", predicted)
+	}
+	if knownCount != 5 {
+		t.Errorf("expected 5 known durations, got %d", knownCount)
+	}
+}
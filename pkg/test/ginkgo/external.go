@@ -14,8 +14,13 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
 
 	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 	"github.com/openshift/origin/test/extended/util"
 )
 
@@ -24,22 +29,176 @@ type serializedTest struct {
 	Labels string
 }
 
-// externalTestsForSuite reads tests from external binary, currently only
-// k8s-tests is supported
-func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
+// ExternalBinary is a test binary shipped in an image of the release payload, whose tests run under the scheduling
+// and monitoring of openshift-tests.  The binary must:
+//   - print its tests when called with ListArgs, as lines holding a JSON list of {"Name", "Labels"} objects, the
+//     test is named Name followed by Labels in the suites.
+//   - run a single test, named Name, when called with RunArgs followed by the name, and exit with 0 when the test
+//     passed, 1 when it failed, 2 when it timed out, 3 when it was skipped and 4 when it flaked.
+type ExternalBinary struct {
+	// ImageTag is the tag of the image in the release payload.
+	ImageTag string `json:"imageTag"`
+	// Path is the path of the binary in the image.
+	Path string `json:"path"`
+	// ListArgs default to list.
+	ListArgs []string `json:"listArgs,omitempty"`
+	// RunArgs default to run-test.
+	RunArgs []string `json:"runArgs,omitempty"`
+	// ReplacesBuiltInTests, if set, drops the built-in tests whose name contains it when the binary provides its
+	// tests, for binaries running tests also vendored in openshift-tests.
+	ReplacesBuiltInTests string `json:"replacesBuiltInTests,omitempty"`
+}
+
+// ExternalBinaryRegistry is a YAML or JSON file listing additional external binaries:
+//
+//	binaries:
+//	- imageTag: cluster-foo-operator
+//	  path: /usr/bin/foo-operator-tests-ext
+type ExternalBinaryRegistry struct {
+	Binaries []ExternalBinary `json:"binaries"`
+}
+
+// DefaultExternalBinaries are always run.
+var DefaultExternalBinaries = []ExternalBinary{
+	{
+		ImageTag:             "hyperkube",
+		Path:                 "/usr/bin/k8s-tests",
+		ReplacesBuiltInTests: "[Suite:k8s]",
+	},
+}
+
+// ExternalBinaryProperty is the junit suite property naming, as <image tag>:<path>, an external binary whose tests
+// are in the junit.
+const ExternalBinaryProperty = "ExternalBinary"
+
+func (b ExternalBinary) String() string {
+	return b.ImageTag + ":" + b.Path
+}
+
+func (b ExternalBinary) listArgs() []string {
+	if len(b.ListArgs) == 0 {
+		return []string{"list"}
+	}
+	return b.ListArgs
+}
+
+func (b ExternalBinary) runArgs() []string {
+	if len(b.RunArgs) == 0 {
+		return []string{"run-test"}
+	}
+	return b.RunArgs
+}
+
+// LoadExternalBinaries reads the binaries of an ExternalBinaryRegistry.
+func LoadExternalBinaries(path string) ([]ExternalBinary, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	registry := &ExternalBinaryRegistry{}
+	if err := yaml.UnmarshalStrict(content, registry); err != nil {
+		return nil, fmt.Errorf("failed to read external binaries from %q: %w", path, err)
+	}
+	for i, binary := range registry.Binaries {
+		if len(binary.ImageTag) == 0 || len(binary.Path) == 0 {
+			return nil, fmt.Errorf("external binary %d of %q must have an imageTag and a path", i, path)
+		}
+	}
+	return registry.Binaries, nil
+}
+
+// externalBinariesWith returns DefaultExternalBinaries and the binaries of the ExternalBinaryRegistry at
+// externalBinariesFile, if set.
+func externalBinariesWith(externalBinariesFile string) ([]ExternalBinary, error) {
+	if len(externalBinariesFile) == 0 {
+		return DefaultExternalBinaries, nil
+	}
+	additionalBinaries, err := LoadExternalBinaries(externalBinariesFile)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]ExternalBinary{}, DefaultExternalBinaries...), additionalBinaries...), nil
+}
+
+// SnapshotExternalTests reads the tests of DefaultExternalBinaries and of the ExternalBinaryRegistry at
+// externalBinariesFile, if set, for a cluster state to select them without extracting the binaries.
+func SnapshotExternalTests(ctx context.Context, externalBinariesFile string) ([]clusterdiscovery.ExternalTest, error) {
+	binaries, err := externalBinariesWith(externalBinariesFile)
+	if err != nil {
+		return nil, err
+	}
+	tests, err := externalTestsForSuite(ctx, binaries)
+	if err != nil {
+		return nil, err
+	}
+	ret := []clusterdiscovery.ExternalTest{}
+	for _, test := range tests {
+		ret = append(ret, clusterdiscovery.ExternalTest{
+			Name:                 test.name,
+			ImageTag:             test.externalBinary.ImageTag,
+			Path:                 test.externalBinary.Path,
+			ReplacesBuiltInTests: test.externalBinary.ReplacesBuiltInTests,
+		})
+	}
+	return ret, nil
+}
+
+// externalTestsFromClusterState returns the external tests recorded in a cluster state.  They can be selected but
+// not run, their binaries were not extracted.
+func externalTestsFromClusterState(state *clusterdiscovery.ClusterState) []*testCase {
 	var tests []*testCase
+	for _, test := range state.ExternalTests {
+		tests = append(tests, &testCase{
+			name:   test.Name,
+			labels: ParseTestLabels(test.Name),
+			externalBinary: &ExternalBinary{
+				ImageTag:             test.ImageTag,
+				Path:                 test.Path,
+				ReplacesBuiltInTests: test.ReplacesBuiltInTests,
+			},
+		})
+	}
+	return tests
+}
 
-	// TODO: add support for binaries from other images
-	testBinary, err := extractBinaryFromReleaseImage("hyperkube", "/usr/bin/k8s-tests")
+// externalTestsForSuite reads the tests of the external binaries.  The tests of the binaries that could be read are
+// returned along with an error for the others.
+func externalTestsForSuite(ctx context.Context, binaries []ExternalBinary) ([]*testCase, error) {
+	extractor, err := newReleaseImageExtractor()
 	if err != nil {
-		return nil, fmt.Errorf("unable to extract k8s-tests binary: %w", err)
+		return nil, err
 	}
 
-	command := exec.Command(testBinary, "list")
+	var tests []*testCase
+	var errs []error
+	for _, binary := range binaries {
+		binaryTests, err := externalBinaryTests(ctx, extractor, binary)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to read the tests of %s: %w", binary, err))
+			continue
+		}
+		tests = append(tests, binaryTests...)
+	}
+	return tests, utilerrors.NewAggregate(errs)
+}
+
+func externalBinaryTests(ctx context.Context, extractor *releaseImageExtractor, binary ExternalBinary) ([]*testCase, error) {
+	testBinary, err := extractor.extract(binary.ImageTag, binary.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	command := exec.Command(testBinary, binary.listArgs()...)
 	testList, err := runWithTimeout(ctx, command, 1*time.Minute)
 	if err != nil {
-		return nil, fmt.Errorf("failed running '%s list': %w", testBinary, err)
+		return nil, fmt.Errorf("failed running '%s %s': %w", testBinary, strings.Join(binary.listArgs(), " "), err)
 	}
+	return parseExternalTests(testList, testBinary, binary)
+}
+
+func parseExternalTests(testList []byte, testBinary string, binary ExternalBinary) ([]*testCase, error) {
+	var tests []*testCase
+	externalBinary := binary
 	buf := bytes.NewBuffer(testList)
 	for {
 		line, err := buf.ReadString('\n')
@@ -56,59 +215,127 @@ func externalTestsForSuite(ctx context.Context) ([]*testCase, error) {
 		}
 		for _, test := range serializedTests {
 			tests = append(tests, &testCase{
-				name:       test.Name + test.Labels,
-				rawName:    test.Name,
-				binaryName: testBinary,
+				name:           test.Name + test.Labels,
+				labels:         ParseTestLabels(test.Name + test.Labels),
+				rawName:        test.Name,
+				binaryName:     testBinary,
+				externalBinary: &externalBinary,
 			})
 		}
 	}
 	return tests, nil
 }
 
-// extractBinaryFromReleaseImage is responsible for resolving the tag from
-// release image and extracting binary, returns path to the binary or error
-func extractBinaryFromReleaseImage(tag, binary string) (string, error) {
+// withoutReplacedTests drops the built-in tests replaced by the binaries of the external tests.
+func withoutReplacedTests(tests, externalTests []*testCase) []*testCase {
+	replaced := sets.NewString()
+	for _, test := range externalTests {
+		if test.externalBinary != nil && len(test.externalBinary.ReplacesBuiltInTests) > 0 {
+			replaced.Insert(test.externalBinary.ReplacesBuiltInTests)
+		}
+	}
+	if replaced.Len() == 0 {
+		return tests
+	}
+
+	filteredTests := []*testCase{}
+	for _, test := range tests {
+		isReplaced := false
+		for _, substring := range replaced.List() {
+			if strings.Contains(test.name, substring) {
+				isReplaced = true
+				break
+			}
+		}
+		if !isReplaced {
+			filteredTests = append(filteredTests, test)
+		}
+	}
+	return filteredTests
+}
+
+// ReplacingExternalBinary returns the binary of the external tests recorded in the cluster state that replaces the
+// built-in test, the way the run drops it, or nil when the test is kept.
+func ReplacingExternalBinary(name string, state *clusterdiscovery.ClusterState) *ExternalBinary {
+	externalTests := externalTestsFromClusterState(state)
+	for _, test := range externalTests {
+		if test.name == name {
+			return nil
+		}
+	}
+	for _, test := range externalTests {
+		if len(withoutReplacedTests([]*testCase{{name: name}}, []*testCase{test})) == 0 {
+			return test.externalBinary
+		}
+	}
+	return nil
+}
+
+// externalBinaryProperties returns a junit suite property for every external binary of the tests.
+func externalBinaryProperties(tests []*testCase) []*junitapi.TestSuiteProperty {
+	binaries := sets.NewString()
+	for _, test := range tests {
+		if test.externalBinary != nil {
+			binaries.Insert(test.externalBinary.String())
+		}
+	}
+	var properties []*junitapi.TestSuiteProperty
+	for _, binary := range binaries.List() {
+		properties = append(properties, &junitapi.TestSuiteProperty{
+			Name:  ExternalBinaryProperty,
+			Value: binary,
+		})
+	}
+	return properties
+}
+
+// releaseImageExtractor extracts binaries from the images of the release payload of the cluster.
+type releaseImageExtractor struct {
+	tmpDir               string
+	images               map[string]string
+	dockerConfigJsonPath string
+}
+
+// newReleaseImageExtractor is responsible for resolving the tags of the release image.
+func newReleaseImageExtractor() (*releaseImageExtractor, error) {
 	tmpDir, err := os.MkdirTemp("", "release")
 	if err != nil {
-		return "", fmt.Errorf("cannot create temporary directory for extracted binary: %w", err)
+		return nil, fmt.Errorf("cannot create temporary directory for extracted binary: %w", err)
 	}
 
 	oc := util.NewCLIWithoutNamespace("default")
 	cv, err := oc.AdminConfigClient().ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed reading ClusterVersion/version: %w", err)
+		return nil, fmt.Errorf("failed reading ClusterVersion/version: %w", err)
 	}
 	releaseImage := cv.Status.Desired.Image
 	if len(releaseImage) == 0 {
-		return "", fmt.Errorf("cannot determine release image from ClusterVersion resource")
+		return nil, fmt.Errorf("cannot determine release image from ClusterVersion resource")
 	}
 
 	if err := runImageExtract(releaseImage, "/release-manifests/image-references", tmpDir, ""); err != nil {
-		return "", fmt.Errorf("failed extracting image-references: %w", err)
+		return nil, fmt.Errorf("failed extracting image-references: %w", err)
 	}
 	jsonFile, err := os.Open(filepath.Join(tmpDir, "image-references"))
 	if err != nil {
-		return "", fmt.Errorf("failed reading image-references: %w", err)
+		return nil, fmt.Errorf("failed reading image-references: %w", err)
 	}
 	defer jsonFile.Close()
 	data, err := ioutil.ReadAll(jsonFile)
 	if err != nil {
-		return "", fmt.Errorf("unable to load release image-references: %w", err)
+		return nil, fmt.Errorf("unable to load release image-references: %w", err)
 	}
 	is := &imagev1.ImageStream{}
 	if err := json.Unmarshal(data, &is); err != nil {
-		return "", fmt.Errorf("unable to load release image-references: %w", err)
+		return nil, fmt.Errorf("unable to load release image-references: %w", err)
 	}
 	if is.Kind != "ImageStream" || is.APIVersion != "image.openshift.io/v1" {
-		return "", fmt.Errorf("unrecognized image-references in release payload")
+		return nil, fmt.Errorf("unrecognized image-references in release payload")
 	}
 
-	image := ""
+	images := map[string]string{}
 	for _, t := range is.Spec.Tags {
-		if t.Name == tag {
-			image = t.From.Name
-			break
-		}
+		images[t.Name] = t.From.Name
 	}
 
 	// The preceding runImageExtract was against a release payload that was created in the local
@@ -120,24 +347,39 @@ func extractBinaryFromReleaseImage(tag, binary string) (string, error) {
 	// from images referenced by the release payload.
 	clusterPullSecret, err := oc.AdminKubeClient().CoreV1().Secrets("openshift-config").Get(context.Background(), "pull-secret", metav1.GetOptions{})
 	if err != nil {
-		return "", fmt.Errorf("unable to read ephemeral cluster pull secret: %v", err)
+		return nil, fmt.Errorf("unable to read ephemeral cluster pull secret: %v", err)
 	}
 
 	clusterDockerConfig := clusterPullSecret.Data[".dockerconfigjson"]
 	dockerConfigJsonPath := filepath.Join(tmpDir, ".dockerconfigjson")
 	err = os.WriteFile(dockerConfigJsonPath, clusterDockerConfig, 0644)
 	if err != nil {
-		return "", fmt.Errorf("unable to serialize ephemeral cluster pull secret locally: %v", err)
+		return nil, fmt.Errorf("unable to serialize ephemeral cluster pull secret locally: %v", err)
 	}
 
+	return &releaseImageExtractor{
+		tmpDir:               tmpDir,
+		images:               images,
+		dockerConfigJsonPath: dockerConfigJsonPath,
+	}, nil
+}
+
+// extract extracts the binary from the image of the release with the tag, returns path to the binary or error
+func (e *releaseImageExtractor) extract(tag, binary string) (string, error) {
+	image := e.images[tag]
 	if len(image) == 0 {
 		return "", fmt.Errorf("%s not found", tag)
 	}
-	if err := runImageExtract(image, binary, tmpDir, dockerConfigJsonPath); err != nil {
+	// binaries of different images may have the same name.
+	binaryDir := filepath.Join(e.tmpDir, tag)
+	if err := os.MkdirAll(binaryDir, 0755); err != nil {
+		return "", fmt.Errorf("cannot create temporary directory for extracted binary: %w", err)
+	}
+	if err := runImageExtract(image, binary, binaryDir, e.dockerConfigJsonPath); err != nil {
 		return "", fmt.Errorf("failed extracting %q from %q: %w", binary, image, err)
 	}
 
-	extractedBinary := filepath.Join(tmpDir, filepath.Base(binary))
+	extractedBinary := filepath.Join(binaryDir, filepath.Base(binary))
 	if err := os.Chmod(extractedBinary, 0755); err != nil {
 		return "", fmt.Errorf("failed making the extracted binary executable: %w", err)
 	}
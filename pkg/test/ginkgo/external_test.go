@@ -0,0 +1,107 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterdiscovery"
+)
+
+func TestParseExternalTests(t *testing.T) {
+	binary := ExternalBinary{ImageTag: "cluster-foo-operator", Path: "/usr/bin/foo-tests", RunArgs: []string{"run", "--"}}
+	testList := []byte("some logging\n" +
+		`[{"Name":"[sig-foo] works","Labels":" [Suite:openshift/conformance/parallel]"}]` + "\n" +
+		`[{"Name":"[sig-foo] also works","Labels":""}]` + "\n")
+	tests, err := parseExternalTests(testList, "/tmp/release/cluster-foo-operator/foo-tests", binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := testNames(tests), []string{"[sig-foo] works [Suite:openshift/conformance/parallel]", "[sig-foo] also works"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected tests %v, got %v", want, got)
+	}
+
+	c := &commandContext{}
+	testBinary, runArgs, testName := c.extractCommands(tests[0].Retry())
+	if testBinary != "/tmp/release/cluster-foo-operator/foo-tests" || !reflect.DeepEqual(runArgs, []string{"run", "--"}) || testName != "[sig-foo] works" {
+		t.Errorf("unexpected command for the retry of an external test: %s %v %q", testBinary, runArgs, testName)
+	}
+	if _, runArgs, _ := c.extractCommands(&testCase{name: "[sig-origin] test"}); !reflect.DeepEqual(runArgs, []string{"run-test"}) {
+		t.Errorf("expected built-in tests to use run-test, got %v", runArgs)
+	}
+
+	properties := externalBinaryProperties(append(tests, &testCase{name: "[sig-origin] test"}))
+	if len(properties) != 1 || properties[0].Name != ExternalBinaryProperty || properties[0].Value != "cluster-foo-operator:/usr/bin/foo-tests" {
+		t.Errorf("unexpected junit properties %v", properties)
+	}
+}
+
+func TestWithoutReplacedTests(t *testing.T) {
+	builtIn := []*testCase{
+		{name: "[sig-node] pods should run [Suite:k8s]"},
+		{name: "[sig-origin] builds should build [Suite:openshift/conformance/parallel]"},
+	}
+	k8sTests := []*testCase{{name: "[sig-node] pods should run [Suite:k8s]", externalBinary: &DefaultExternalBinaries[0]}}
+	fooTests := []*testCase{{name: "[sig-foo] works", externalBinary: &ExternalBinary{ImageTag: "cluster-foo-operator", Path: "/usr/bin/foo-tests"}}}
+
+	if got := testNames(withoutReplacedTests(builtIn, append(k8sTests, fooTests...))); !reflect.DeepEqual(got, []string{"[sig-origin] builds should build [Suite:openshift/conformance/parallel]"}) {
+		t.Errorf("expected the vendored k8s tests to be replaced, got %v", got)
+	}
+	if got := withoutReplacedTests(builtIn, fooTests); len(got) != 2 {
+		t.Errorf("expected the built-in tests to be kept when k8s-tests could not be read, got %v", testNames(got))
+	}
+}
+
+func TestExternalTestsFromClusterState(t *testing.T) {
+	state := &clusterdiscovery.ClusterState{
+		ExternalTests: []clusterdiscovery.ExternalTest{
+			{Name: "[sig-node] pods should run [Suite:k8s]", ImageTag: "hyperkube", Path: "/usr/bin/k8s-tests", ReplacesBuiltInTests: "[Suite:k8s]"},
+		},
+	}
+	builtIn := []*testCase{
+		{name: "[sig-node] pods should run [Suite:k8s]"},
+		{name: "[sig-origin] builds should build [Suite:openshift/conformance/parallel]"},
+	}
+
+	externalTests := externalTestsFromClusterState(state)
+	if len(externalTests) != 1 || externalTests[0].externalBinary.String() != "hyperkube:/usr/bin/k8s-tests" || !externalTests[0].Labels().HasValue("Suite", "k8s") {
+		t.Fatalf("unexpected external tests %#v", externalTests)
+	}
+	tests := append(withoutReplacedTests(builtIn, externalTests), externalTests...)
+	if len(tests) != 2 || tests[1].externalBinary == nil {
+		t.Errorf("expected the recorded k8s tests to replace the vendored ones, got %v", testNames(tests))
+	}
+
+	if binary := ReplacingExternalBinary("[sig-node] pods should stop [Suite:k8s]", state); binary == nil || binary.Path != "/usr/bin/k8s-tests" {
+		t.Errorf("expected a vendored k8s test to be replaced by the k8s-tests binary, got %v", binary)
+	}
+	for _, name := range []string{"[sig-node] pods should run [Suite:k8s]", "[sig-origin] builds should build [Suite:openshift/conformance/parallel]"} {
+		if binary := ReplacingExternalBinary(name, state); binary != nil {
+			t.Errorf("expected %q not to be replaced, got %v", name, binary)
+		}
+	}
+}
+
+func TestLoadExternalBinaries(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	if err := os.WriteFile(valid, []byte("binaries:\n- imageTag: cluster-foo-operator\n  path: /usr/bin/foo-tests\n  listArgs: [list, tests]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	binaries, err := LoadExternalBinaries(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(binaries) != 1 || !reflect.DeepEqual(binaries[0].listArgs(), []string{"list", "tests"}) || !reflect.DeepEqual(binaries[0].runArgs(), []string{"run-test"}) {
+		t.Errorf("unexpected binaries %#v", binaries)
+	}
+
+	missingPath := filepath.Join(dir, "missing-path.yaml")
+	if err := os.WriteFile(missingPath, []byte("binaries:\n- imageTag: cluster-foo-operator\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadExternalBinaries(missingPath); err == nil {
+		t.Errorf("expected an error for a binary without a path")
+	}
+}
@@ -0,0 +1,312 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	FailureClustersFileName     = "failure-clusters.json"
+	FailureClustersHTMLFileName = "failure-clusters.html"
+
+	// failureClusterSimilarity is the minimum Jaccard similarity between the words of a normalized failure output
+	// and the words of the first failure of a cluster for the failure to join the cluster.
+	failureClusterSimilarity = 0.7
+	// maxNormalizedFailureLength bounds the part of a failure output used for clustering, stack traces and
+	// captured logs past this point tell failures apart more than they bring them together.
+	maxNormalizedFailureLength = 4096
+	// maxCorrelatedIntervals bounds the intervals reported for a cluster.
+	maxCorrelatedIntervals = 25
+)
+
+// FailureClusters groups the failures of a run that look alike once the details varying between runs are removed,
+// most likely because they share a root cause.
+type FailureClusters struct {
+	Failures int               `json:"failures"`
+	Clusters []*FailureCluster `json:"clusters"`
+}
+
+type FailureCluster struct {
+	ID int `json:"id"`
+	// Signature is the normalized failure output of the first failure of the cluster.
+	Signature string `json:"signature"`
+	// Example is the failure output of the first failure of the cluster, as reported.
+	Example string `json:"example"`
+	// Failures counts the failures in the cluster, a test failing several times is counted every time.
+	Failures int      `json:"failures"`
+	Tests    []string `json:"tests"`
+	// CorrelatedIntervals are the Error intervals of the monitor overlapping the failed tests of the cluster, the
+	// intervals overlapping the most tests first.
+	CorrelatedIntervals []CorrelatedInterval `json:"correlatedIntervals,omitempty"`
+}
+
+type CorrelatedInterval struct {
+	Source  string    `json:"source"`
+	Locator string    `json:"locator"`
+	Message string    `json:"message"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	// OverlappingTests counts the tests of the cluster running while the interval was.
+	OverlappingTests int `json:"overlappingTests"`
+}
+
+var failureOutputNormalizers = []struct {
+	regex       *regexp.Regexp
+	replacement string
+}{
+	{regex: regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), replacement: "<uid>"},
+	{regex: regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?( [A-Z]{3,4})?`), replacement: "<time>"},
+	{regex: regexp.MustCompile(`\b[IWEF]\d{4} \d{2}:\d{2}:\d{2}\.\d+`), replacement: "<time>"},
+	{regex: regexp.MustCompile(`\b[A-Z][a-z]{2} +\d{1,2} \d{2}:\d{2}:\d{2}(\.\d+)?`), replacement: "<time>"},
+	{regex: regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(\.\d+)?`), replacement: "<time>"},
+	{regex: regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), replacement: "<ip>"},
+	{regex: regexp.MustCompile(`\be2e-[a-z0-9-]*[a-z0-9]`), replacement: "e2e-<namespace>"},
+	// pods of deployments end with a pod template hash and a random suffix, the other pods with a random suffix.
+	// Random suffixes are drawn from an alphabet without vowels nor easily confused digits.
+	{regex: regexp.MustCompile(`-[0-9a-f]{8,10}-[bcdfghjklmnpqrstvwxz2456789]{5}\b`), replacement: "-<pod>"},
+	{regex: regexp.MustCompile(`-[bcdfghjklmnpqrstvwxz2456789]{5}\b`), replacement: "-<pod>"},
+	{regex: regexp.MustCompile(`\b\d+(\.\d+)?\b`), replacement: "<n>"},
+}
+
+// NormalizeFailureOutput removes from a failure output the details varying between occurrences of the same failure:
+// UIDs, timestamps, IPs, e2e namespaces, pod name suffixes and numbers.
+func NormalizeFailureOutput(output string) string {
+	if len(output) > maxNormalizedFailureLength {
+		output = output[:maxNormalizedFailureLength]
+	}
+	for _, normalizer := range failureOutputNormalizers {
+		output = normalizer.regex.ReplaceAllString(output, normalizer.replacement)
+	}
+	return strings.Join(strings.Fields(output), " ")
+}
+
+var failureWordSeparator = regexp.MustCompile(`[^a-zA-Z0-9<>_-]+`)
+
+func failureWords(normalized string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range failureWordSeparator.Split(normalized, -1) {
+		if len(word) > 0 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+func jaccardSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	intersection := 0
+	for word := range a {
+		if b[word] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// ClusterFailures clusters the failed test cases of the suites by the similarity of their normalized failure output
+// and correlates every cluster with the Error intervals overlapping its tests.  The time a test ran is read from its
+// E2ETestStarted and E2ETestFinished intervals, tests without them are not correlated.
+func ClusterFailures(suites []*junitapi.JUnitTestSuite, intervals monitorapi.Intervals) *FailureClusters {
+	failures := []*junitapi.JUnitTestCase{}
+	var addSuite func(suite *junitapi.JUnitTestSuite)
+	addSuite = func(suite *junitapi.JUnitTestSuite) {
+		for _, testCase := range suite.TestCases {
+			if testCase.FailureOutput != nil {
+				failures = append(failures, testCase)
+			}
+		}
+		for _, child := range suite.Children {
+			addSuite(child)
+		}
+	}
+	for _, suite := range suites {
+		addSuite(suite)
+	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].Name < failures[j].Name
+	})
+
+	type clusterWords struct {
+		cluster *FailureCluster
+		words   map[string]bool
+		tests   map[string]bool
+	}
+	clusters := []*clusterWords{}
+	for _, failure := range failures {
+		output := failureOutputText(failure.FailureOutput)
+		signature := NormalizeFailureOutput(output)
+		words := failureWords(signature)
+
+		var match *clusterWords
+		bestSimilarity := 0.0
+		for _, curr := range clusters {
+			if curr.cluster.Signature == signature {
+				match = curr
+				break
+			}
+			if similarity := jaccardSimilarity(curr.words, words); similarity >= failureClusterSimilarity && similarity > bestSimilarity {
+				match, bestSimilarity = curr, similarity
+			}
+		}
+		if match == nil {
+			match = &clusterWords{
+				cluster: &FailureCluster{Signature: signature, Example: output},
+				words:   words,
+				tests:   map[string]bool{},
+			}
+			clusters = append(clusters, match)
+		}
+		match.cluster.Failures++
+		if !match.tests[failure.Name] {
+			match.tests[failure.Name] = true
+			match.cluster.Tests = append(match.cluster.Tests, failure.Name)
+		}
+	}
+
+	testRuns := e2eTestRuns(intervals)
+	ret := &FailureClusters{Failures: len(failures)}
+	for _, curr := range clusters {
+		curr.cluster.CorrelatedIntervals = correlateIntervals(curr.cluster.Tests, testRuns, intervals)
+		ret.Clusters = append(ret.Clusters, curr.cluster)
+	}
+	sort.SliceStable(ret.Clusters, func(i, j int) bool {
+		return ret.Clusters[i].Failures > ret.Clusters[j].Failures
+	})
+	for i, cluster := range ret.Clusters {
+		cluster.ID = i + 1
+	}
+	return ret
+}
+
+func failureOutputText(failureOutput *junitapi.FailureOutput) string {
+	switch {
+	case len(failureOutput.Output) == 0:
+		return failureOutput.Message
+	case len(failureOutput.Message) == 0 || strings.Contains(failureOutput.Output, failureOutput.Message):
+		return failureOutput.Output
+	default:
+		return failureOutput.Message + "\n" + failureOutput.Output
+	}
+}
+
+func correlateIntervals(tests []string, testRuns map[string][]e2eTestRun, intervals monitorapi.Intervals) []CorrelatedInterval {
+	ret := []CorrelatedInterval{}
+	for _, interval := range intervals {
+		if interval.Level != monitorapi.Error || interval.Source == monitorapi.SourceE2ETest {
+			continue
+		}
+		overlappingTests := 0
+		for _, test := range tests {
+			for _, run := range testRuns[test] {
+				if intervalOverlaps(interval, run.from, run.to) {
+					overlappingTests++
+					break
+				}
+			}
+		}
+		if overlappingTests == 0 {
+			continue
+		}
+		ret = append(ret, CorrelatedInterval{
+			Source:           string(interval.Source),
+			Locator:          interval.Locator.OldLocator(),
+			Message:          interval.Message.OldMessage(),
+			From:             interval.From,
+			To:               interval.To,
+			OverlappingTests: overlappingTests,
+		})
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].OverlappingTests != ret[j].OverlappingTests {
+			return ret[i].OverlappingTests > ret[j].OverlappingTests
+		}
+		return ret[i].From.Before(ret[j].From)
+	})
+	if len(ret) > maxCorrelatedIntervals {
+		ret = ret[:maxCorrelatedIntervals]
+	}
+	return ret
+}
+
+// intervalOverlaps treats an interval without an end as still running.
+func intervalOverlaps(interval monitorapi.Interval, from, to time.Time) bool {
+	return !interval.From.After(to) && (interval.To.IsZero() || !interval.To.Before(from))
+}
+
+// WriteFailureClusters writes the clusters to failure-clusters.json and failure-clusters.html in dir.
+func WriteFailureClusters(dir string, clusters *FailureClusters) error {
+	content, err := json.MarshalIndent(clusters, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, FailureClustersFileName), content, 0644); err != nil {
+		return err
+	}
+
+	htmlFile, err := os.Create(filepath.Join(dir, FailureClustersHTMLFileName))
+	if err != nil {
+		return err
+	}
+	defer htmlFile.Close()
+	if err := failureClustersTemplate.Execute(htmlFile, clusters); err != nil {
+		return fmt.Errorf("failed to render %s: %w", FailureClustersHTMLFileName, err)
+	}
+	return htmlFile.Close()
+}
+
+var failureClustersTemplate = template.Must(template.New(FailureClustersHTMLFileName).Funcs(template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Failure clusters</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; }
+pre { background: #f4f4f4; padding: 0.5em; max-height: 20em; overflow: auto; white-space: pre-wrap; }
+table { border-collapse: collapse; font-size: 0.9em; }
+td, th { border: 1px solid #ccc; padding: 0.2em 0.5em; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>{{ .Failures }} failures in {{ len .Clusters }} clusters</h1>
+{{ range .Clusters }}
+<h2>Cluster {{ .ID }}: {{ .Failures }} failures of {{ len .Tests }} tests</h2>
+<details>
+<summary>Tests</summary>
+<ul>
+{{ range .Tests }}<li>{{ . }}</li>
+{{ end }}</ul>
+</details>
+<h3>Example failure</h3>
+<pre>{{ .Example }}</pre>
+<h3>Signature</h3>
+<pre>{{ .Signature }}</pre>
+{{ if .CorrelatedIntervals }}
+<h3>Overlapping Error intervals</h3>
+<table>
+<tr><th>Tests</th><th>From</th><th>To</th><th>Source</th><th>Locator</th><th>Message</th></tr>
+{{ range .CorrelatedIntervals }}<tr><td>{{ .OverlappingTests }}</td><td>{{ formatTime .From }}</td><td>{{ formatTime .To }}</td><td>{{ .Source }}</td><td>{{ .Locator }}</td><td>{{ .Message }}</td></tr>
+{{ end }}</table>
+{{ end }}
+{{ end }}
+</body>
+</html>
+`))
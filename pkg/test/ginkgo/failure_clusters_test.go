@@ -0,0 +1,129 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestNormalizeFailureOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "uid and timestamp",
+			output: "pod 0f8e2c4e-5d8b-4a51-9d4b-7f8a6b0c2e11 failed at 2024-05-01T10:11:12.123Z",
+			want:   "pod <uid> failed at <time>",
+		},
+		{
+			name:   "klog timestamp",
+			output: "I0501 10:11:12.123456 request timed out",
+			want:   "<time> request timed out",
+		},
+		{
+			name:   "pod suffixes and namespace",
+			output: `pod "router-default-5c9b7d6f8d-x2lqz" in namespace "e2e-test-router-k8r2b" and pod "dns-default-7zt4m"`,
+			want:   `pod "router-default-<pod>" in namespace "e2e-<namespace>" and pod "dns-default-<pod>"`,
+		},
+		{
+			name:   "ips and numbers",
+			output: "dial tcp 172.30.0.1:443: i/o timeout after 30 retries",
+			want:   "dial tcp <ip>: i/o timeout after <n> retries",
+		},
+		{
+			name:   "words are kept",
+			output: "image pulls are failing",
+			want:   "image pulls are failing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeFailureOutput(tt.output); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClusterFailures(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	failure := func(name, output string) *junitapi.JUnitTestCase {
+		return &junitapi.JUnitTestCase{Name: name, FailureOutput: &junitapi.FailureOutput{Output: output}}
+	}
+	suite := &junitapi.JUnitTestSuite{
+		Name: "openshift-tests",
+		TestCases: []*junitapi.JUnitTestCase{
+			failure("[sig-a] test a", `Get "https://172.30.0.1:443/api/v1/namespaces/e2e-test-a-x2lqz/pods": dial tcp 172.30.0.1:443: connect: connection refused`),
+			failure("[sig-b] test b", `Get "https://172.30.0.1:443/api/v1/namespaces/e2e-test-b-7zt4m/pods/web-0": dial tcp 172.30.0.1:443: connect: connection refused`),
+			failure("[sig-c] test c", `pod "registry-5c9b7d6f8d-x2lqz" failed to pull image: context deadline exceeded`),
+			{Name: "[sig-d] test d"},
+		},
+		Children: []*junitapi.JUnitTestSuite{
+			{TestCases: []*junitapi.JUnitTestCase{
+				failure("[sig-c] test c2", `pod "registry-5c9b7d6f8d-j8vbn" failed to pull image: context deadline exceeded`),
+			}},
+		},
+	}
+
+	errorInterval := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "", monitorapi.NewConnectionType)).
+		Message(monitorapi.NewMessage().HumanMessage("disruption")).
+		Build(start.Add(time.Minute), start.Add(2*time.Minute))
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, testRunIntervals("[sig-a] test a", TestFailed, start, start.Add(90*time.Second))...)
+	intervals = append(intervals, testRunIntervals("[sig-b] test b", TestFailed, start.Add(30*time.Second), start.Add(3*time.Minute))...)
+	intervals = append(intervals, testRunIntervals("[sig-c] test c", TestFailed, start.Add(5*time.Minute), start.Add(6*time.Minute))...)
+	intervals = append(intervals,
+		errorInterval,
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).Build(start, start.Add(10*time.Minute)),
+	)
+
+	clusters := ClusterFailures([]*junitapi.JUnitTestSuite{suite}, intervals)
+	if clusters.Failures != 4 || len(clusters.Clusters) != 2 {
+		t.Fatalf("expected 4 failures in 2 clusters, got %#v", clusters)
+	}
+	apiCluster, pullCluster := clusters.Clusters[0], clusters.Clusters[1]
+	if strings.Join(apiCluster.Tests, ",") != "[sig-a] test a,[sig-b] test b" {
+		t.Errorf("unexpected tests in the first cluster: %v", apiCluster.Tests)
+	}
+	if len(apiCluster.CorrelatedIntervals) != 1 || apiCluster.CorrelatedIntervals[0].OverlappingTests != 2 {
+		t.Errorf("expected the disruption to overlap both tests, got %#v", apiCluster.CorrelatedIntervals)
+	}
+	if strings.Join(pullCluster.Tests, ",") != "[sig-c] test c,[sig-c] test c2" {
+		t.Errorf("unexpected tests in the second cluster: %v", pullCluster.Tests)
+	}
+	if len(pullCluster.CorrelatedIntervals) != 0 {
+		t.Errorf("expected no correlated intervals, got %#v", pullCluster.CorrelatedIntervals)
+	}
+
+	dir := t.TempDir()
+	if err := WriteFailureClusters(dir, clusters); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, FailureClustersFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := &FailureClusters{}
+	if err := json.Unmarshal(content, read); err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Clusters) != 2 {
+		t.Errorf("expected 2 clusters in %s, got %d", FailureClustersFileName, len(read.Clusters))
+	}
+	html, err := os.ReadFile(filepath.Join(dir, FailureClustersHTMLFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "4 failures in 2 clusters") {
+		t.Errorf("unexpected html report:\n%s", html)
+	}
+}
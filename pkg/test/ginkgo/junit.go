@@ -55,6 +55,18 @@ func generateJUnitTestSuiteResults(
 					Output: lastLinesUntil(string(test.testOutputBytes), 100, "fail ["),
 				},
 			})
+		case test.quarantine != nil:
+			s.NumTests++
+			s.NumFailed++
+			s.TestCases = append(s.TestCases, &junitapi.JUnitTestCase{
+				Name:      test.name,
+				SystemOut: string(test.testOutputBytes),
+				Duration:  test.duration.Seconds(),
+				FailureOutput: &junitapi.FailureOutput{
+					Message: fmt.Sprintf("quarantined by %s", test.quarantine.Jira),
+					Output:  quarantineNote(test.quarantine) + "\n\n" + lastLinesUntil(string(test.testOutputBytes), 100, "fail ["),
+				},
+			})
 		case test.flake:
 			s.NumTests++
 			s.NumFailed++
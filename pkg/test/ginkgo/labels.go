@@ -0,0 +1,406 @@
+package ginkgo
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// The kinds of the labels used to select and schedule tests.
+const (
+	// LabelSuite lists the suites of the test, for instance [Suite:openshift/conformance/parallel].
+	LabelSuite = "Suite"
+	// LabelSig is the special interest group owning the test, for instance [sig-storage].
+	LabelSig = "sig"
+	// LabelAPIGroup lists the API groups the cluster must serve for the test to run, for instance
+	// [apigroup:route.openshift.io].
+	LabelAPIGroup = "apigroup"
+	// LabelFeatureGate lists the feature gates the cluster must enable for the test to run, for instance
+	// [OCPFeatureGate:AdminNetworkPolicy].
+	LabelFeatureGate = "OCPFeatureGate"
+	// LabelFeature is the feature exercised by the test, for instance [Feature:Builds].
+	LabelFeature = "Feature"
+	// LabelSerial marks the tests that must run alone.  [Serial:Self] only prevents running a test in parallel
+	// with copies of itself.
+	LabelSerial = "Serial"
+	// LabelEarly marks the tests running before every other test.
+	LabelEarly = "Early"
+	// LabelLate marks the tests running after every other test.
+	LabelLate = "Late"
+	// LabelDisabled marks the tests that never run, for instance [Disabled:Broken].
+	LabelDisabled = "Disabled"
+	// LabelSkippedUntil disables the test until a date, for instance [SkippedUntil:05092022:blocker-bz/123456].
+	LabelSkippedUntil = "SkippedUntil"
+)
+
+// labelRegex matches the bracketed labels of a test name.
+var labelRegex = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// labelKindRegex matches the kinds of labels, excluding bracketed text of test names which isn't a label.
+var labelKindRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// TestLabels are the values of the labels of a test by label kind.  Test names carry their labels in brackets:
+// [Kind:value] adds value to the labels of that kind, [sig-name] is the value sig-name of the sig labels, and
+// [Flag] sets a label without a value, recorded as the empty value.
+type TestLabels map[string]sets.String
+
+// ParseTestLabels returns the labels in a test name, which includes the labels added by the annotate rules.
+func ParseTestLabels(name string) TestLabels {
+	labels := TestLabels{}
+	for _, match := range labelRegex.FindAllStringSubmatch(name, -1) {
+		labels.insertLabel(match[1])
+	}
+	return labels
+}
+
+// insertLabel adds a label given as Kind:value, sig-name or Flag, which is both the syntax in test names and in
+// Ginkgo spec labels.
+func (l TestLabels) insertLabel(label string) {
+	kind, value, _ := strings.Cut(label, ":")
+	kind, value = strings.TrimSpace(kind), strings.TrimSpace(value)
+	if !labelKindRegex.MatchString(kind) {
+		return
+	}
+	if !strings.Contains(label, ":") && strings.HasPrefix(kind, LabelSig+"-") {
+		kind, value = LabelSig, kind
+	}
+	l.Insert(kind, value)
+}
+
+// Insert adds the values to the labels of the kind, or sets the label when no values are given.
+func (l TestLabels) Insert(kind string, values ...string) {
+	if len(values) == 0 {
+		values = []string{""}
+	}
+	if _, ok := l[kind]; !ok {
+		l[kind] = sets.NewString()
+	}
+	l[kind].Insert(values...)
+}
+
+// Has returns true if the test has a label of the kind, with or without a value.
+func (l TestLabels) Has(kind string) bool {
+	return l[kind].Len() > 0
+}
+
+// Flag returns true if the test has the label of the kind without a value, for instance [Serial] but not
+// [Serial:Self].
+func (l TestLabels) Flag(kind string) bool {
+	return l[kind].Has("")
+}
+
+// HasValue returns true if the test has a label of the kind matching the value.  Values are hierarchical: a
+// value matches itself and the values nested under it, so openshift/conformance matches the label
+// [Suite:openshift/conformance/parallel/minimal].
+func (l TestLabels) HasValue(kind, value string) bool {
+	for _, curr := range l[kind].UnsortedList() {
+		if labelValueMatches(curr, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func labelValueMatches(labelValue, value string) bool {
+	return labelValue == value || (len(value) > 0 && strings.HasPrefix(labelValue, strings.TrimSuffix(value, "/")+"/"))
+}
+
+// Values returns the sorted values of the labels of the kind, excluding the flag.
+func (l TestLabels) Values(kind string) []string {
+	return l[kind].Difference(sets.NewString("")).List()
+}
+
+// Kinds returns the sorted kinds of the labels of the test.
+func (l TestLabels) Kinds() []string {
+	kinds := []string{}
+	for kind := range l {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// skippedUntilRegex matches the value of the SkippedUntil label of a test.
+var skippedUntilRegex = regexp.MustCompile(`^(\d{8}):blocker-bz\/([a-zA-Z0-9]+)$`)
+
+// Disabled returns true if the test must not run: it is labeled [Disabled:reason], or skipped until a date after
+// now.
+func (l TestLabels) Disabled(now time.Time) bool {
+	return l.Has(LabelDisabled) || l.SkippedUntil(now)
+}
+
+// SkippedUntil allows a test to be skipped with a time limit.
+// the test should be annotated with the 'SkippedUntil' tag, as shown below.
+//
+//	[SkippedUntil:05092022:blocker-bz/123456]
+//
+// - the specified date should conform to the 'MMDDYYYY' format.
+// - a valid blocker BZ must be specified
+// if the specified date in the tag has not passed yet, the test
+// will be skipped by the runner.
+func (l TestLabels) SkippedUntil(now time.Time) bool {
+	for _, value := range l.Values(LabelSkippedUntil) {
+		matches := skippedUntilRegex.FindStringSubmatch(value)
+		if len(matches) != 3 {
+			continue
+		}
+
+		skipUntil, err := time.Parse("01022006", matches[1])
+		if err != nil {
+			continue
+		}
+
+		if skipUntil.After(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l TestLabels) String() string {
+	labels := []string{}
+	for _, kind := range l.Kinds() {
+		if l.Flag(kind) {
+			labels = append(labels, kind)
+		}
+		for _, value := range l.Values(kind) {
+			labels = append(labels, fmt.Sprintf("%s:%s", kind, value))
+		}
+	}
+	return strings.Join(labels, ",")
+}
+
+// specLabels are the labels of the Ginkgo specs by test name.  They are not part of the test name, so they are
+// recorded when the specs are walked for TestLabelsOf to find them.
+var (
+	specLabelsLock sync.RWMutex
+	specLabels     = map[string][]string{}
+)
+
+// TestLabelsOf returns the labels of a test: the labels in its name and the labels of its Ginkgo spec.  Suites,
+// selectors and queues all select tests by these labels.
+func TestLabelsOf(name string) TestLabels {
+	labels := ParseTestLabels(name)
+	specLabelsLock.RLock()
+	defer specLabelsLock.RUnlock()
+	for _, label := range specLabels[name] {
+		labels.insertLabel(label)
+	}
+	return labels
+}
+
+func recordSpecLabels(name string, labels []string) {
+	specLabelsLock.Lock()
+	defer specLabelsLock.Unlock()
+	specLabels[name] = labels
+}
+
+// ginkgoSpecLabels returns the labels of the nodes of a Ginkgo spec, from the outermost container to the It.  The
+// spec type is internal to Ginkgo and has no accessor for the labels, so its Nodes are read by reflection.
+func ginkgoSpecLabels(spec interface{}) []string {
+	value := reflect.Indirect(reflect.ValueOf(spec))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	nodes := value.FieldByName("Nodes")
+	if nodes.Kind() != reflect.Slice {
+		return nil
+	}
+	labels := []string{}
+	for i := 0; i < nodes.Len(); i++ {
+		nodeLabels := reflect.Indirect(nodes.Index(i)).FieldByName("Labels")
+		if nodeLabels.Kind() != reflect.Slice {
+			continue
+		}
+		for j := 0; j < nodeLabels.Len(); j++ {
+			labels = append(labels, nodeLabels.Index(j).String())
+		}
+	}
+	return labels
+}
+
+// LabelOperator is how a LabelRequirement compares the labels of a test.
+type LabelOperator string
+
+const (
+	// LabelExists requires a label of the kind, with or without a value.
+	LabelExists LabelOperator = "exists"
+	// LabelDoesNotExist requires no label of the kind.
+	LabelDoesNotExist LabelOperator = "!"
+	// LabelIn requires a label of the kind matching one of the values.
+	LabelIn LabelOperator = "in"
+	// LabelNotIn requires no label of the kind matching any of the values.
+	LabelNotIn LabelOperator = "notin"
+)
+
+// LabelRequirement is a condition on the labels of a test.
+type LabelRequirement struct {
+	Kind     string
+	Operator LabelOperator
+	Values   []string
+}
+
+// Matches returns true if the labels meet the requirement.
+func (r LabelRequirement) Matches(labels TestLabels) bool {
+	switch r.Operator {
+	case LabelExists:
+		return labels.Has(r.Kind)
+	case LabelDoesNotExist:
+		return !labels.Has(r.Kind)
+	case LabelIn, LabelNotIn:
+		found := false
+		for _, value := range r.Values {
+			if labels.HasValue(r.Kind, value) {
+				found = true
+				break
+			}
+		}
+		return found == (r.Operator == LabelIn)
+	default:
+		return false
+	}
+}
+
+func (r LabelRequirement) String() string {
+	switch r.Operator {
+	case LabelExists:
+		return r.Kind
+	case LabelDoesNotExist:
+		return "!" + r.Kind
+	default:
+		return fmt.Sprintf("%s %s (%s)", r.Kind, r.Operator, strings.Join(r.Values, ","))
+	}
+}
+
+// LabelSelector selects the tests whose labels meet all of its requirements.  The empty selector selects every test.
+type LabelSelector []LabelRequirement
+
+// Matches returns true if the labels meet every requirement of the selector.
+func (s LabelSelector) Matches(labels TestLabels) bool {
+	return s.FirstUnmet(labels) == nil
+}
+
+// FirstUnmet returns the first requirement of the selector the labels don't meet, or nil.
+func (s LabelSelector) FirstUnmet(labels TestLabels) *LabelRequirement {
+	for i := range s {
+		if !s[i].Matches(labels) {
+			return &s[i]
+		}
+	}
+	return nil
+}
+
+// labelKindAliases are the alternative names of the label kinds accepted by ParseLabelSelector.
+var labelKindAliases = map[string]string{
+	"feature-gate": LabelFeatureGate,
+}
+
+// labelSetRequirementRegex matches the requirements of the form "kind in (value, ...)" and "kind notin (value, ...)".
+var labelSetRequirementRegex = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+
+// ParseLabelSelector parses comma separated requirements on the labels of tests, similar to Kubernetes label
+// selectors:
+//
+//	kind, !kind, kind=value, kind!=value, kind in (value, ...), kind notin (value, ...)
+//
+// For instance "sig in (sig-network,sig-node), !Slow, apigroup=route.openshift.io, feature-gate=AdminNetworkPolicy".
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var requirements []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	requirements = append(requirements, selector[start:])
+
+	ret := LabelSelector{}
+	for _, curr := range requirements {
+		curr = strings.TrimSpace(curr)
+		if len(curr) == 0 {
+			continue
+		}
+		requirement, err := parseLabelRequirement(curr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+		}
+		ret = append(ret, requirement)
+	}
+	return ret, nil
+}
+
+func parseLabelRequirement(requirement string) (LabelRequirement, error) {
+	var ret LabelRequirement
+	var values []string
+	if match := labelSetRequirementRegex.FindStringSubmatch(requirement); match != nil {
+		ret.Kind, ret.Operator = match[1], LabelOperator(match[2])
+		values = strings.Split(match[3], ",")
+	} else if kind, value, ok := strings.Cut(requirement, "!="); ok {
+		ret.Kind, ret.Operator = kind, LabelNotIn
+		values = []string{value}
+	} else if kind, value, ok := strings.Cut(requirement, "="); ok {
+		ret.Kind, ret.Operator = kind, LabelIn
+		values = []string{strings.TrimPrefix(value, "=")}
+	} else if kind, ok := strings.CutPrefix(requirement, "!"); ok {
+		ret.Kind, ret.Operator = kind, LabelDoesNotExist
+	} else {
+		ret.Kind, ret.Operator = requirement, LabelExists
+	}
+
+	ret.Kind = strings.TrimSpace(ret.Kind)
+	if alias, ok := labelKindAliases[ret.Kind]; ok {
+		ret.Kind = alias
+	}
+	if !labelKindRegex.MatchString(ret.Kind) {
+		return ret, fmt.Errorf("%q is not a valid label kind", ret.Kind)
+	}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 {
+			return ret, fmt.Errorf("%q has an empty value", requirement)
+		}
+		ret.Values = append(ret.Values, value)
+	}
+	return ret, nil
+}
+
+// MatchesName returns true if the labels of the test match the selector, which lets a selector be used as the
+// TestMatchFunc of a suite.
+func (s LabelSelector) MatchesName(name string) bool {
+	return s.Matches(TestLabelsOf(name))
+}
+
+func (s LabelSelector) String() string {
+	requirements := []string{}
+	for _, requirement := range s {
+		requirements = append(requirements, requirement.String())
+	}
+	return strings.Join(requirements, ", ")
+}
+
+// The selectors splitting the tests of a suite in the queues run one after the other.
+var (
+	earlyTestsSelector   = LabelSelector{{Kind: LabelEarly, Operator: LabelExists}}
+	lateTestsSelector    = LabelSelector{{Kind: LabelLate, Operator: LabelExists}}
+	kubeTestsSelector    = LabelSelector{{Kind: LabelSuite, Operator: LabelIn, Values: []string{"k8s"}}}
+	storageTestsSelector = LabelSelector{{Kind: LabelSig, Operator: LabelIn, Values: []string{"sig-storage"}}}
+)
+
+// matchesTest returns true if the labels of the test match the selector, for use with splitTests.
+func (s LabelSelector) matchesTest(test *testCase) bool {
+	return s.Matches(test.Labels())
+}
@@ -0,0 +1,223 @@
+package ginkgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+)
+
+var _ = g.Describe("[sig-testing] ginkgo labels", g.Label("Suite:ginkgo-labels"), func() {
+	g.It("are read from the nodes of the spec", g.Label("Serial"), func() {})
+})
+
+func TestParseTestLabels(t *testing.T) {
+	labels := ParseTestLabels(`[sig-network][Feature:Router] routes should serve "[0]" [Serial] [Serial:Self] [apigroup:route.openshift.io] [apigroup:config.openshift.io] [Driver: csi-hostpath] [Suite:openshift/conformance/serial/minimal] [Suite:k8s]`)
+
+	if got, want := labels.String(), "Driver:csi-hostpath,Feature:Router,Serial,Serial:Self,Suite:k8s,Suite:openshift/conformance/serial/minimal,apigroup:config.openshift.io,apigroup:route.openshift.io,sig:sig-network"; got != want {
+		t.Errorf("expected labels\n%s\ngot\n%s", want, got)
+	}
+	if !labels.Flag(LabelSerial) || !labels.HasValue(LabelSerial, "Self") {
+		t.Errorf("expected both the serial flag and value")
+	}
+	for _, value := range []string{"openshift", "openshift/conformance", "openshift/conformance/serial/", "k8s"} {
+		if !labels.HasValue(LabelSuite, value) {
+			t.Errorf("expected suite %q to match", value)
+		}
+	}
+	for _, value := range []string{"openshift/conformance/parallel", "openshift/conf", ""} {
+		if labels.HasValue(LabelSuite, value) {
+			t.Errorf("expected suite %q not to match", value)
+		}
+	}
+	if labels.Has(LabelEarly) || labels.Has("0") {
+		t.Errorf("expected no early label and no label for bracketed text, got %v", labels.Kinds())
+	}
+}
+
+func TestLabelSelector(t *testing.T) {
+	labels := ParseTestLabels("[sig-network] services should serve [Slow] [apigroup:route.openshift.io] [Suite:openshift/conformance/parallel]")
+
+	tests := []struct {
+		name      string
+		selector  LabelSelector
+		wantUnmet string
+	}{
+		{
+			name:     "empty",
+			selector: LabelSelector{},
+		},
+		{
+			name: "matching",
+			selector: LabelSelector{
+				{Kind: LabelSig, Operator: LabelIn, Values: []string{"sig-node", "sig-network"}},
+				{Kind: LabelAPIGroup, Operator: LabelExists},
+				{Kind: LabelSerial, Operator: LabelDoesNotExist},
+				{Kind: LabelSuite, Operator: LabelNotIn, Values: []string{"k8s"}},
+			},
+		},
+		{
+			name: "slow",
+			selector: LabelSelector{
+				{Kind: LabelSuite, Operator: LabelIn, Values: []string{"openshift/conformance"}},
+				{Kind: "Slow", Operator: LabelDoesNotExist},
+			},
+			wantUnmet: "!Slow",
+		},
+		{
+			name: "suite",
+			selector: LabelSelector{
+				{Kind: LabelSuite, Operator: LabelNotIn, Values: []string{"openshift/conformance"}},
+			},
+			wantUnmet: "Suite notin (openshift/conformance)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmet := tt.selector.FirstUnmet(labels)
+			switch {
+			case len(tt.wantUnmet) == 0 && unmet != nil:
+				t.Errorf("expected %v to match, got unmet %v", tt.selector, unmet)
+			case len(tt.wantUnmet) > 0 && (unmet == nil || unmet.String() != tt.wantUnmet):
+				t.Errorf("expected unmet %q, got %v", tt.wantUnmet, unmet)
+			}
+		})
+	}
+}
+
+// TestQueueSelectorsMatchNames ensures splitting the queues with labels selects the same tests as the bracketed
+// names did.
+func TestQueueSelectorsMatchNames(t *testing.T) {
+	selectors := map[string]LabelSelector{
+		"[Early]":       earlyTestsSelector,
+		"[Late]":        lateTestsSelector,
+		"[Suite:k8s]":   kubeTestsSelector,
+		"[sig-storage]": storageTestsSelector,
+	}
+	for _, test := range makeTestCases() {
+		for substring, selector := range selectors {
+			if want, got := strings.Contains(test.name, substring), selector.matchesTest(test); want != got {
+				t.Errorf("expected %v for %s on %q, got %v", want, selector, test.name, got)
+			}
+		}
+		if want, got := strings.Contains(test.name, "[Serial]"), isSerialTest(test); want != got {
+			t.Errorf("expected serial %v for %q, got %v", want, test.name, got)
+		}
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+		wantErr  string
+	}{
+		{
+			selector: "sig in (sig-network,sig-node), !Slow, apigroup=route.openshift.io, feature-gate=AdminNetworkPolicy",
+			want:     "sig in (sig-network,sig-node), !Slow, apigroup in (route.openshift.io), OCPFeatureGate in (AdminNetworkPolicy)",
+		},
+		{
+			selector: " Suite notin ( k8s ),Serial,Feature==Builds, sig!=sig-storage,",
+			want:     "Suite notin (k8s), Serial, Feature in (Builds), sig notin (sig-storage)",
+		},
+		{
+			selector: "",
+			want:     "",
+		},
+		{
+			selector: "sig in (sig-network,)",
+			wantErr:  "has an empty value",
+		},
+		{
+			selector: "not a kind",
+			wantErr:  `"not a kind" is not a valid label kind`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tt.selector)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := selector.String(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSuiteExclusion(t *testing.T) {
+	suite := &TestSuite{
+		Name: "openshift/conformance/parallel",
+		Matches: func(name string) bool {
+			labels := ParseTestLabels(name)
+			return !labels.Disabled(time.Now()) && labels.HasValue(LabelSuite, "openshift/conformance/parallel")
+		},
+		Selector: LabelSelector{{Kind: LabelSig, Operator: LabelIn, Values: []string{"sig-network"}}},
+	}
+	suite.AddRequiredFilter(TestFilter{
+		Name:    "missing API group",
+		Matches: LabelSelector{{Kind: LabelAPIGroup, Operator: LabelNotIn, Values: []string{"route.openshift.io"}}}.MatchesName,
+		Explain: func(name string) string { return "the cluster doesn't serve route.openshift.io" },
+	})
+	suite.AddRequiredFilter(TestFilter{Name: "platform skip", Matches: func(name string) bool { return !strings.Contains(name, "[Skipped:aws]") }})
+
+	tests := map[string]string{
+		"[sig-network] routes [apigroup:route.openshift.io] [Suite:openshift/conformance/parallel]": "missing API group: the cluster doesn't serve route.openshift.io",
+		"[sig-network] services [Skipped:aws] [Suite:openshift/conformance/parallel]":               "platform skip",
+		"[sig-network] services [Disabled:Broken] [Suite:openshift/conformance/parallel]":           "disabled: Broken",
+		"[sig-network] services [Suite:openshift/conformance/serial]":                               "not in suite openshift/conformance/parallel",
+		"[sig-network] services [Suite:openshift/conformance/parallel]":                             "",
+	}
+	var testCases []*testCase
+	for name, want := range tests {
+		if got := suite.Exclusion(name); got != want {
+			t.Errorf("expected exclusion %q for %q, got %q", want, name, got)
+		}
+		testCases = append(testCases, &testCase{name: name})
+	}
+	testCases = append(testCases, &testCase{name: "[sig-node] pods [Suite:openshift/conformance/parallel]"})
+	if filtered := suite.Filter(testCases); len(filtered) != 1 {
+		t.Errorf("expected only the enabled network test to be selected, got %v", testNames(filtered))
+	}
+}
+
+func TestGinkgoSpecLabels(t *testing.T) {
+	tests, err := testsForSuite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test *testCase
+	for i := range tests {
+		if tests[i].name == "[sig-testing] ginkgo labels are read from the nodes of the spec" {
+			test = tests[i]
+		}
+	}
+	if test == nil {
+		t.Fatalf("expected the spec among %v", testNames(tests))
+	}
+
+	if got, want := test.Labels().String(), "Serial,Suite:ginkgo-labels,sig:sig-testing"; got != want {
+		t.Errorf("expected labels\n%s\ngot\n%s", want, got)
+	}
+	if !isSerialTest(test) {
+		t.Errorf("expected the Serial label of the spec to make the test serial")
+	}
+
+	// the suites select by the same labels as the queues.
+	suite := &TestSuite{
+		Matches: func(name string) bool {
+			return TestLabelsOf(name).HasValue(LabelSuite, "ginkgo-labels")
+		},
+	}
+	if filtered := suite.Filter(tests); len(filtered) != 1 || filtered[0] != test {
+		t.Errorf("expected the suite to select the spec by its labels, got %v", testNames(filtered))
+	}
+}
@@ -0,0 +1,231 @@
+package ginkgo
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// QuarantineRegistryVersion is the only version of the quarantine registry format understood.
+	QuarantineRegistryVersion = 1
+
+	quarantineDateFormat = "2006-01-02"
+)
+
+// QuarantineRegistry lists the known flakes whose failures are reported as flakes instead of failures until they
+// expire.  It is read from a YAML or JSON file:
+//
+//	version: 1
+//	quarantines:
+//	- testNameRegex: '^\[sig-network\] Services should serve endpoints on same port and different protocols'
+//	  releases: ["4.16"]
+//	  platforms: ["metal"]
+//	  topologies: ["single"]
+//	  expires: "2024-07-01"
+//	  jira: OCPBUGS-12345
+//	  reason: the endpoints controller is slow to converge on single node clusters
+type QuarantineRegistry struct {
+	Version     int           `json:"version"`
+	Quarantines []*Quarantine `json:"quarantines"`
+}
+
+// Quarantine matches the tests whose name matches TestNameRegex, on the clusters matching the releases, platforms
+// and topologies.  Empty lists match every cluster.
+type Quarantine struct {
+	TestNameRegex string   `json:"testNameRegex"`
+	Releases      []string `json:"releases,omitempty"`
+	Platforms     []string `json:"platforms,omitempty"`
+	Topologies    []string `json:"topologies,omitempty"`
+	// Expires is the first day, as YYYY-MM-DD in UTC, the quarantine no longer applies.
+	Expires string `json:"expires"`
+	// Jira is the bug tracking the fix of the test, whose owner owns the quarantine.
+	Jira   string `json:"jira"`
+	Reason string `json:"reason,omitempty"`
+
+	testNameRegex *regexp.Regexp
+	expires       time.Time
+}
+
+// LoadQuarantineRegistry reads and validates a quarantine registry.
+func LoadQuarantineRegistry(path string) (*QuarantineRegistry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	registry := &QuarantineRegistry{}
+	if err := yaml.UnmarshalStrict(content, registry); err != nil {
+		return nil, fmt.Errorf("failed to read quarantine registry %q: %w", path, err)
+	}
+	if registry.Version != QuarantineRegistryVersion {
+		return nil, fmt.Errorf("quarantine registry %q has version %d, only version %d is supported", path, registry.Version, QuarantineRegistryVersion)
+	}
+	for i, quarantine := range registry.Quarantines {
+		if err := quarantine.complete(); err != nil {
+			return nil, fmt.Errorf("quarantine registry %q entry %d: %w", path, i, err)
+		}
+	}
+	return registry, nil
+}
+
+func (q *Quarantine) complete() error {
+	if len(q.TestNameRegex) == 0 {
+		return fmt.Errorf("missing testNameRegex")
+	}
+	testNameRegex, err := regexp.Compile(q.TestNameRegex)
+	if err != nil {
+		return fmt.Errorf("invalid testNameRegex: %w", err)
+	}
+	if len(q.Jira) == 0 {
+		return fmt.Errorf("%q is missing the jira owning the quarantine", q.TestNameRegex)
+	}
+	expires, err := time.Parse(quarantineDateFormat, q.Expires)
+	if err != nil {
+		return fmt.Errorf("%q has an invalid expires, must be YYYY-MM-DD: %w", q.TestNameRegex, err)
+	}
+	q.testNameRegex = testNameRegex
+	q.expires = expires
+	return nil
+}
+
+// Expired returns true once the quarantine no longer applies.
+func (q *Quarantine) Expired(now time.Time) bool {
+	return !now.Before(q.expires)
+}
+
+// Matches returns true if the quarantine applies to the test on the cluster.  Clusters whose release, platform or
+// topology is unknown only match the quarantines not restricted to some of them.
+func (q *Quarantine) Matches(testName string, cluster platformidentification.JobType, now time.Time) bool {
+	return !q.Expired(now) &&
+		quarantineValueMatches(q.Releases, cluster.Release) &&
+		quarantineValueMatches(q.Platforms, cluster.Platform) &&
+		quarantineValueMatches(q.Topologies, cluster.Topology) &&
+		q.MatchesName(testName)
+}
+
+// MatchesName returns true if the test name matches the quarantine, whatever the cluster and the date.
+func (q *Quarantine) MatchesName(testName string) bool {
+	return q.testNameRegex.MatchString(testName)
+}
+
+func quarantineValueMatches(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, curr := range values {
+		if curr == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Quarantine) String() string {
+	return fmt.Sprintf("quarantined by %s until %s: %s", q.Jira, q.Expires, q.TestNameRegex)
+}
+
+// Find returns the first quarantine applying to the test on the cluster, or nil.
+func (r *QuarantineRegistry) Find(testName string, cluster platformidentification.JobType, now time.Time) *Quarantine {
+	if r == nil {
+		return nil
+	}
+	for _, quarantine := range r.Quarantines {
+		if quarantine.Matches(testName, cluster, now) {
+			return quarantine
+		}
+	}
+	return nil
+}
+
+// QuarantinedTestProperty is the junit suite property naming, as <jira> <test name>, a test whose failure is
+// quarantined.  The failure is in the junit like any other, but does not fail the run.
+const QuarantinedTestProperty = "QuarantinedTest"
+
+// quarantineFailures marks the failed tests matching a quarantine as quarantined.  The junit still reports their
+// failure, without inventing a success, and names them in a QuarantinedTestProperty, but they do not fail the run.
+// It returns the quarantined tests.
+func quarantineFailures(tests []*testCase, registry *QuarantineRegistry, cluster platformidentification.JobType, now time.Time) []*testCase {
+	var quarantined []*testCase
+	for _, test := range tests {
+		if !test.failed {
+			continue
+		}
+		quarantine := registry.Find(test.name, cluster, now)
+		if quarantine == nil {
+			continue
+		}
+		test.failed = false
+		test.quarantine = quarantine
+		quarantined = append(quarantined, test)
+	}
+	return quarantined
+}
+
+// QuarantineProblem is an entry of a quarantine registry needing attention.
+type QuarantineProblem struct {
+	Quarantine *Quarantine
+	Problem    string
+}
+
+func (p QuarantineProblem) String() string {
+	return fmt.Sprintf("%s (%s): %s", p.Quarantine.TestNameRegex, p.Quarantine.Jira, p.Problem)
+}
+
+// LintQuarantineRegistry returns the expired quarantines and the quarantines matching none of the test names, which
+// most likely were renamed or removed.
+func LintQuarantineRegistry(registry *QuarantineRegistry, testNames []string, now time.Time) []QuarantineProblem {
+	var problems []QuarantineProblem
+	for _, quarantine := range registry.Quarantines {
+		if quarantine.Expired(now) {
+			problems = append(problems, QuarantineProblem{
+				Quarantine: quarantine,
+				Problem:    fmt.Sprintf("expired on %s, fix the test or extend the quarantine", quarantine.Expires),
+			})
+		}
+		matches := false
+		for _, testName := range testNames {
+			if quarantine.testNameRegex.MatchString(testName) {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			problems = append(problems, QuarantineProblem{
+				Quarantine: quarantine,
+				Problem:    "matches no test",
+			})
+		}
+	}
+	return problems
+}
+
+// quarantinedTestProperties returns a junit suite property for every quarantined test.
+func quarantinedTestProperties(tests []*testCase) []*junitapi.TestSuiteProperty {
+	var properties []*junitapi.TestSuiteProperty
+	for _, test := range tests {
+		if test.quarantine == nil {
+			continue
+		}
+		properties = append(properties, &junitapi.TestSuiteProperty{
+			Name:  QuarantinedTestProperty,
+			Value: fmt.Sprintf("%s %s", test.quarantine.Jira, test.name),
+		})
+	}
+	return properties
+}
+
+// quarantineNote describes the quarantine of a test in its junit.
+func quarantineNote(quarantine *Quarantine) string {
+	note := []string{fmt.Sprintf("This failure does not fail the run because the test is %s", quarantine)}
+	if len(quarantine.Reason) > 0 {
+		note = append(note, quarantine.Reason)
+	}
+	return strings.Join(note, "\n")
+}
@@ -0,0 +1,159 @@
+package ginkgo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const testQuarantineRegistry = `version: 1
+quarantines:
+- testNameRegex: '^\[sig-network\] services should'
+  releases: ["4.16"]
+  platforms: ["metal"]
+  expires: "2024-07-01"
+  jira: OCPBUGS-1
+  reason: slow endpoints
+- testNameRegex: 'storage'
+  topologies: ["single"]
+  expires: "2024-05-01"
+  jira: OCPBUGS-2
+- testNameRegex: 'renamed test'
+  expires: "2024-07-01"
+  jira: OCPBUGS-3
+`
+
+func writeQuarantineRegistry(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "quarantines.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadQuarantineRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: testQuarantineRegistry,
+		},
+		{
+			name:    "unknown version",
+			content: "version: 2\n",
+			wantErr: "only version 1 is supported",
+		},
+		{
+			name:    "missing jira",
+			content: "version: 1\nquarantines:\n- testNameRegex: a\n  expires: \"2024-07-01\"\n",
+			wantErr: "missing the jira",
+		},
+		{
+			name:    "invalid expires",
+			content: "version: 1\nquarantines:\n- testNameRegex: a\n  expires: tomorrow\n  jira: OCPBUGS-1\n",
+			wantErr: "invalid expires",
+		},
+		{
+			name:    "invalid regex",
+			content: "version: 1\nquarantines:\n- testNameRegex: '['\n  expires: \"2024-07-01\"\n  jira: OCPBUGS-1\n",
+			wantErr: "invalid testNameRegex",
+		},
+		{
+			name:    "unknown field",
+			content: "version: 1\nquarantines:\n- testName: a\n  expires: \"2024-07-01\"\n  jira: OCPBUGS-1\n",
+			wantErr: "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadQuarantineRegistry(writeQuarantineRegistry(t, tt.content))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestQuarantineFailures(t *testing.T) {
+	registry, err := LoadQuarantineRegistry(writeQuarantineRegistry(t, testQuarantineRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metal := platformidentification.JobType{Release: "4.16", Platform: "metal", Topology: "single"}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []*testCase{
+		{name: "[sig-network] services should serve", failed: true},
+		{name: "[sig-storage] storage should mount", failed: true},
+		{name: "[sig-network] services should route", success: true},
+		{name: "[sig-apps] deployments should roll", failed: true},
+	}
+	quarantined := quarantineFailures(tests, registry, metal, now)
+	if len(quarantined) != 1 || quarantined[0] != tests[0] {
+		t.Fatalf("expected only the network failure to be quarantined, got %v", testNames(quarantined))
+	}
+	if tests[0].flake || tests[0].failed || tests[0].quarantine != registry.Quarantines[0] {
+		t.Errorf("expected the failure to be quarantined, got %#v", tests[0])
+	}
+	if !tests[1].failed {
+		t.Errorf("expected the expired quarantine not to apply")
+	}
+
+	suite := generateJUnitTestSuiteResults("suite", time.Minute, tests)
+	if output := suite.TestCases[0].FailureOutput.Output; !strings.HasPrefix(output, "This failure does not fail the run because the test is quarantined by OCPBUGS-1 until 2024-07-01") {
+		t.Errorf("unexpected failure output %q", output)
+	}
+	if len(suite.TestCases) != 4 || suite.NumFailed != 3 {
+		t.Errorf("expected the quarantined failure to be reported once, as a failure, got %d test cases", len(suite.TestCases))
+	}
+	pass, fail, _, _ := summarizeTests(tests)
+	if pass != 1 || fail != 2 {
+		t.Errorf("expected the quarantined failure to count neither as a pass nor as a failure of the run, got %d pass and %d fail", pass, fail)
+	}
+	properties := quarantinedTestProperties(tests)
+	if len(properties) != 1 || properties[0].Name != QuarantinedTestProperty || properties[0].Value != "OCPBUGS-1 [sig-network] services should serve" {
+		t.Errorf("unexpected quarantine properties %v", properties)
+	}
+
+	aws := platformidentification.JobType{Release: "4.16", Platform: "aws"}
+	if quarantine := registry.Find("[sig-network] services should serve", aws, now); quarantine != nil {
+		t.Errorf("expected the metal quarantine not to apply to aws, got %v", quarantine)
+	}
+	if quarantine := registry.Find("[sig-network] services should serve", platformidentification.JobType{}, now); quarantine != nil {
+		t.Errorf("expected the restricted quarantine not to apply to an unknown cluster, got %v", quarantine)
+	}
+}
+
+func TestLintQuarantineRegistry(t *testing.T) {
+	registry, err := LoadQuarantineRegistry(writeQuarantineRegistry(t, testQuarantineRegistry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testNames := []string{"[sig-network] services should serve", "[sig-storage] storage should mount"}
+	problems := LintQuarantineRegistry(registry, testNames, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	got := []string{}
+	for _, problem := range problems {
+		got = append(got, problem.String())
+	}
+	want := []string{
+		"storage (OCPBUGS-2): expired on 2024-05-01, fix the test or extend the quarantine",
+		"renamed test (OCPBUGS-3): matches no test",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected problems\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
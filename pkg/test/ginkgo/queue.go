@@ -4,30 +4,34 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
 	"sync"
+	"time"
 )
 
 // parallelByFileTestQueue runs tests in parallel unless they have
 // the `[Serial]` tag on their name or if another test with the
 // testExclusion field is currently running. Serial tests are
-// defered until all other tests are completed.
+// defered until all other tests are completed.  When historical
+// durations are known, the longest parallel tests are queued first.
 type parallelByFileTestQueue struct {
 	commandContext *commandContext
+	// durations are the expected durations of tests, by test name.
+	durations map[string]time.Duration
 }
 
 type TestFunc func(ctx context.Context, test *testCase)
 
-func newParallelTestQueue(commandContext *commandContext) *parallelByFileTestQueue {
+func newParallelTestQueue(commandContext *commandContext, durations map[string]time.Duration) *parallelByFileTestQueue {
 	return &parallelByFileTestQueue{
 		commandContext: commandContext,
+		durations:      durations,
 	}
 }
 
 // OutputCommand prints to stdout what would have been executed.
 func (q *parallelByFileTestQueue) OutputCommands(ctx context.Context, tests []*testCase, out io.Writer) {
 	// for some reason we split the serial and parallel when printing the command
-	serial, parallel := splitTests(tests, func(t *testCase) bool { return strings.Contains(t.name, "[Serial]") })
+	serial, parallel := splitTests(tests, isSerialTest)
 
 	for _, curr := range parallel {
 		commandString := q.commandContext.commandString(curr)
@@ -96,16 +100,23 @@ func (q *parallelByFileTestQueue) Execute(ctx context.Context, tests []*testCase
 		maybeAbortOnFailureFn: maybeAbortOnFailureFn,
 	}
 
-	execute(ctx, testSuiteRunner, tests, parallelism)
+	if len(q.durations) > 0 && len(tests) > 0 {
+		predicted, knownCount := predictDuration(tests, q.durations, parallelism)
+		testSuiteProgress.LogPrediction(testOutput.out, predicted, knownCount)
+		defer testSuiteProgress.LogCompletion(testOutput.out)
+	}
+
+	execute(ctx, testSuiteRunner, tests, parallelism, q.durations)
 }
 
 // execute is a convenience for unit testing
-func execute(ctx context.Context, testSuiteRunner testSuiteRunner, tests []*testCase, parallelism int) {
+func execute(ctx context.Context, testSuiteRunner testSuiteRunner, tests []*testCase, parallelism int, durations map[string]time.Duration) {
 	if ctx.Err() != nil {
 		return
 	}
 
 	serial, parallel := splitTests(tests, isSerialTest)
+	parallel = orderByDuration(parallel, durations)
 
 	remainingParallelTests := make(chan *testCase, 100)
 	go queueAllTests(remainingParallelTests, parallel)
@@ -129,11 +140,7 @@ func execute(ctx context.Context, testSuiteRunner testSuiteRunner, tests []*test
 }
 
 func isSerialTest(test *testCase) bool {
-	if strings.Contains(test.name, "[Serial]") {
-		return true
-	}
-
-	return false
+	return test.Labels().Flag(LabelSerial)
 }
 
 func copyTests(tests []*testCase) []*testCase {
@@ -3,6 +3,7 @@ package ginkgo
 import (
 	"context"
 	"math/rand"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -57,10 +58,29 @@ func Test_execute(t *testing.T) {
 	tests := makeTestCases()
 	testSuiteRunner := &testingSuiteRunner{}
 	parallelism := 30
-	execute(context.TODO(), testSuiteRunner, tests, parallelism)
+	execute(context.TODO(), testSuiteRunner, tests, parallelism, nil)
 
 	testsCompleted := testSuiteRunner.getTestsRun()
 	if len(tests) != len(testsCompleted) {
 		t.Errorf("expected %v, got %v", len(tests), len(testsCompleted))
 	}
 }
+
+func Test_execute_longestFirst(t *testing.T) {
+	tests := []*testCase{{name: "short"}, {name: "serial [Serial]"}, {name: "long"}, {name: "medium"}}
+	durations := map[string]time.Duration{
+		"short":           time.Minute,
+		"medium":          5 * time.Minute,
+		"long":            10 * time.Minute,
+		"serial [Serial]": 20 * time.Minute,
+	}
+	testSuiteRunner := &testingSuiteRunner{}
+	// a single worker runs the tests in the order they are queued.
+	execute(context.TODO(), testSuiteRunner, tests, 1, durations)
+
+	// the serial tests still run after the parallel ones, whatever their duration.
+	want := []string{"long", "medium", "short", "serial [Serial]"}
+	if got := testSuiteRunner.getTestsRun(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,142 @@
+package ginkgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+// RetryPolicy decides which failing tests of a suite run again to tell flakes from failures.  Every attempt is
+// reported in the junit as a test case of the same name, so a test failing and then passing is a flake.
+type RetryPolicy interface {
+	// Retryable returns true if the test, which failed with failureOutput on each of its attempts so far, should
+	// run again.  attempt counts the retries, starting at 1.
+	Retryable(testName, failureOutput string, attempt int) bool
+	// Backoff is how long to wait before running the retries of the attempt.
+	Backoff(attempt int) time.Duration
+	// Serial returns true if the retries must run one at a time, isolated from each other.
+	Serial() bool
+}
+
+// TransientFailures match the failure outputs of errors that usually resolve on their own.
+var TransientFailures = []*regexp.Regexp{
+	regexp.MustCompile(`connection refused`),
+	regexp.MustCompile(`connection reset by peer`),
+	regexp.MustCompile(`i/o timeout`),
+	regexp.MustCompile(`TLS handshake timeout`),
+	regexp.MustCompile(`etcdserver: leader changed`),
+	regexp.MustCompile(`etcdserver: request timed out`),
+	regexp.MustCompile(`the server is currently unable to handle the request`),
+}
+
+// BackoffRetryPolicy retries failing tests up to MaxRetries times, waiting longer before every attempt.
+type BackoffRetryPolicy struct {
+	// MaxRetries is the number of times a failing test runs again.  A test is no longer retried once it passes.
+	MaxRetries int
+	// InitialBackoff is waited before the first retries, and doubled before every further attempt.
+	InitialBackoff time.Duration
+	// RetryableFailures restricts the retries to the tests whose failure output matches one of the regexes, for
+	// instance TransientFailures.  Every failure is retried when empty.
+	RetryableFailures []*regexp.Regexp
+	// RunSerially runs the retries one at a time.
+	RunSerially bool
+}
+
+// DefaultRetryPolicy retries every failing test once, in parallel and without waiting.
+func DefaultRetryPolicy() RetryPolicy {
+	return &BackoffRetryPolicy{MaxRetries: 1}
+}
+
+func (p *BackoffRetryPolicy) Retryable(testName, failureOutput string, attempt int) bool {
+	if attempt > p.MaxRetries {
+		return false
+	}
+	if len(p.RetryableFailures) == 0 {
+		return true
+	}
+	for _, retryableFailure := range p.RetryableFailures {
+		if retryableFailure.MatchString(failureOutput) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *BackoffRetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		return 0
+	}
+	return p.InitialBackoff << (attempt - 1)
+}
+
+func (p *BackoffRetryPolicy) Serial() bool {
+	return p.RunSerially
+}
+
+// retryFailingTests runs the failing tests again, as long as policy retries them, with execute.  Every attempt retries
+// the tests that failed all of their previous attempts, after the backoff of the policy.  Retries recorded by the
+// resumed run are reused instead of running again.  It returns the retries to report, the names of the tests that
+// passed a retry and of those skipped by a retry, and the tests that did not pass any attempt.
+func retryFailingTests(ctx context.Context, out io.Writer, policy RetryPolicy, failing []*testCase, resumed resumedTests, parallelism int, execute func(ctx context.Context, tests []*testCase, parallelism int)) ([]*testCase, []string, []string, []*testCase) {
+	var reported, repeatFailures []*testCase
+	var flaky, skipped []string
+	pending := failing
+	for attempt := 1; len(pending) > 0; attempt++ {
+		var retries, retriesToRun []*testCase
+		for _, test := range pending {
+			failureOutput := lastLinesUntil(string(test.testOutputBytes), 100, "fail [")
+			if ctx.Err() != nil || !policy.Retryable(test.name, failureOutput, attempt) {
+				repeatFailures = append(repeatFailures, test)
+				continue
+			}
+			retry := test.Retry()
+			retries = append(retries, retry)
+			// the interrupted run may already have retried the test.
+			if entry, ok := resumed.retry(retry.name, attempt); ok {
+				mutateTestCaseWithResults(retry, &testRunResultHandle{testRunResult: entry.testRunResult()})
+			} else {
+				retriesToRun = append(retriesToRun, retry)
+			}
+		}
+		if len(retries) == 0 {
+			break
+		}
+
+		fmt.Fprintf(out, "Retry attempt %d, retry count: %d\n", attempt, len(retries))
+		if backoff := policy.Backoff(attempt); backoff > 0 && len(retriesToRun) > 0 {
+			fmt.Fprintf(out, "Waiting %s before retrying\n", backoff)
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+		}
+
+		// Run the tests in the retries list.
+		execute(ctx, retriesToRun, parallelism)
+
+		pending = nil
+		for _, retry := range retries {
+			switch {
+			case retry.success:
+				flaky = append(flaky, retry.name)
+			case retry.skipped:
+				skipped = append(skipped, retry.name)
+			case retry.flake:
+				repeatFailures = append(repeatFailures, retry)
+			default:
+				pending = append(pending, retry)
+			}
+
+			// every attempt is reported.
+			if retry.flake {
+				// Retry tests that flaked are omitted so that the original test is counted as a failure.
+				fmt.Fprintf(out, "Ignoring retry that returned a flake, original failure is authoritative for test: %s\n", retry.name)
+				continue
+			}
+			reported = append(reported, retry)
+		}
+	}
+	return reported, flaky, skipped, repeatFailures
+}
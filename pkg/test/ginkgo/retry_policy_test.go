@@ -0,0 +1,96 @@
+package ginkgo
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	policy := &BackoffRetryPolicy{
+		MaxRetries:        3,
+		InitialBackoff:    10 * time.Second,
+		RetryableFailures: TransientFailures,
+	}
+
+	tests := []struct {
+		name          string
+		failureOutput string
+		attempt       int
+		want          bool
+	}{
+		{name: "connection refused", failureOutput: `fail [test.go:12]: dial tcp 172.30.0.1:443: connect: connection refused`, attempt: 1, want: true},
+		{name: "leader changed", failureOutput: `fail [test.go:12]: etcdserver: leader changed`, attempt: 3, want: true},
+		{name: "too many attempts", failureOutput: `fail [test.go:12]: etcdserver: leader changed`, attempt: 4, want: false},
+		{name: "not transient", failureOutput: `fail [test.go:12]: expected 3 replicas, got 2`, attempt: 1, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Retryable("[sig-test] test", tt.failureOutput, tt.attempt); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second} {
+		if got := policy.Backoff(attempt); got != want {
+			t.Errorf("expected a backoff of %v for attempt %d, got %v", want, attempt, got)
+		}
+	}
+
+	defaultPolicy := (&TestSuite{}).retryPolicy()
+	if !defaultPolicy.Retryable("[sig-test] test", "fail [test.go:12]: anything", 1) || defaultPolicy.Retryable("[sig-test] test", "fail [test.go:12]: anything", 2) {
+		t.Errorf("expected the default policy to retry every failure once")
+	}
+	if defaultPolicy.Backoff(1) != 0 || defaultPolicy.Serial() {
+		t.Errorf("expected the default policy to retry immediately and in parallel")
+	}
+}
+
+func TestRetryFailingTests(t *testing.T) {
+	policy := &BackoffRetryPolicy{
+		MaxRetries:        3,
+		RetryableFailures: TransientFailures,
+	}
+	failing := []*testCase{
+		{name: "[sig-test] passes on the second retry", failed: true, testOutputBytes: []byte("fail [test.go:12]: connection refused")},
+		{name: "[sig-test] always fails", failed: true, testOutputBytes: []byte("fail [test.go:12]: i/o timeout")},
+		{name: "[sig-test] not transient", failed: true, testOutputBytes: []byte("fail [test.go:12]: expected 3 replicas, got 2")},
+	}
+
+	var attempts []int
+	execute := func(ctx context.Context, tests []*testCase, parallelism int) {
+		attempts = append(attempts, len(tests))
+		for _, test := range tests {
+			if test.name == "[sig-test] passes on the second retry" && len(attempts) == 2 {
+				test.success = true
+				continue
+			}
+			test.failed = true
+			test.testOutputBytes = []byte("fail [test.go:12]: connection refused")
+		}
+	}
+	retries, flaky, skipped, repeatFailures := retryFailingTests(context.Background(), io.Discard, policy, failing, resumedTests{}, 1, execute)
+
+	if want := []int{2, 2, 1}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("expected attempts retrying %v tests, got %v", want, attempts)
+	}
+	if len(retries) != 5 {
+		t.Errorf("expected every attempt to be reported, got %d retries", len(retries))
+	}
+	if !reflect.DeepEqual(flaky, []string{"[sig-test] passes on the second retry"}) || len(skipped) != 0 {
+		t.Errorf("unexpected flaky tests %v and skipped tests %v", flaky, skipped)
+	}
+	var repeatFailureNames []string
+	for _, test := range repeatFailures {
+		repeatFailureNames = append(repeatFailureNames, test.name)
+	}
+	if want := []string{"[sig-test] not transient", "[sig-test] always fails"}; !reflect.DeepEqual(repeatFailureNames, want) {
+		t.Errorf("expected the repeat failures %v, got %v", want, repeatFailureNames)
+	}
+	if last := repeatFailures[1]; last.previous == nil || last.previous.previous == nil || last.previous.previous.previous == nil {
+		t.Errorf("expected the last attempt to be the third retry")
+	}
+}
@@ -0,0 +1,182 @@
+package ginkgo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// ShardProperty is the junit suite property recording the shard that produced the junit.
+const ShardProperty = "Shard"
+
+// Shard identifies one of several openshift-tests processes that split a suite between them.  Index is 1-based.
+type Shard struct {
+	Index int
+	Total int
+}
+
+// ParseShard parses N/M, the Nth of M shards.
+func ParseShard(value string) (Shard, error) {
+	indexString, totalString, ok := strings.Cut(value, "/")
+	if !ok {
+		return Shard{}, fmt.Errorf("%q must be of the form N/M", value)
+	}
+	index, err := strconv.Atoi(indexString)
+	if err != nil {
+		return Shard{}, fmt.Errorf("%q has an invalid shard index: %w", value, err)
+	}
+	total, err := strconv.Atoi(totalString)
+	if err != nil {
+		return Shard{}, fmt.Errorf("%q has an invalid shard count: %w", value, err)
+	}
+	if total < 1 || index < 1 || index > total {
+		return Shard{}, fmt.Errorf("%q must satisfy 1 <= N <= M", value)
+	}
+	return Shard{Index: index, Total: total}, nil
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Total)
+}
+
+// loadJUnitTestDurations reads the duration of every test case in the given junit files, typically the
+// junit_e2e_*.xml files of previous runs.  Tests found more than once get their average duration.
+func loadJUnitTestDurations(junitFiles []string) (map[string]time.Duration, error) {
+	totals := map[string]time.Duration{}
+	counts := map[string]int{}
+	var addSuite func(suite *junitapi.JUnitTestSuite)
+	addSuite = func(suite *junitapi.JUnitTestSuite) {
+		for _, testCase := range suite.TestCases {
+			totals[testCase.Name] += time.Duration(testCase.Duration * float64(time.Second))
+			counts[testCase.Name]++
+		}
+		for _, child := range suite.Children {
+			addSuite(child)
+		}
+	}
+
+	for _, junitFile := range junitFiles {
+		suites, err := ReadJUnitFile(junitFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, suite := range suites {
+			addSuite(suite)
+		}
+	}
+
+	ret := map[string]time.Duration{}
+	for name, total := range totals {
+		ret[name] = total / time.Duration(counts[name])
+	}
+	return ret, nil
+}
+
+// ReadJUnitFile reads the suites of a junit file holding either a testsuites or a single testsuite element.
+func ReadJUnitFile(junitFile string) ([]*junitapi.JUnitTestSuite, error) {
+	content, err := os.ReadFile(junitFile)
+	if err != nil {
+		return nil, err
+	}
+	suites := &junitapi.JUnitTestSuites{}
+	if err := xml.Unmarshal(content, suites); err == nil {
+		return suites.Suites, nil
+	}
+	suite := &junitapi.JUnitTestSuite{}
+	if err := xml.Unmarshal(content, suite); err != nil {
+		return nil, fmt.Errorf("failed to read junit from %q: %w", junitFile, err)
+	}
+	return []*junitapi.JUnitTestSuite{suite}, nil
+}
+
+// shardUnit is a group of tests that must run in the same shard.
+type shardUnit struct {
+	tests    []*testCase
+	duration time.Duration
+	// serial units run one at a time after the parallel tests of their shard.
+	serial bool
+}
+
+// shardTests returns the tests of the shard.  Every process sharding the same tests with the same durations gets
+// a disjoint part of them, whatever the order of the tests.  Tests sharing a testExclusion go to the same shard.
+// Shards are balanced by the known duration of their tests, tests without a known duration are assumed to take the
+// average known duration.  [Serial] tests run one at a time at the end of every shard, so they are balanced on their
+// own first, to end the shards at about the same time.
+func shardTests(tests []*testCase, shard Shard, durations map[string]time.Duration) []*testCase {
+	if shard.Total <= 1 {
+		return tests
+	}
+
+	durationOf, _ := estimateDurations(tests, durations)
+
+	units := []*shardUnit{}
+	unitsByKey := map[string]*shardUnit{}
+	for _, test := range sortedTests(tests) {
+		key := ""
+		if len(test.testExclusion) > 0 {
+			key = "exclusion:" + test.testExclusion
+		}
+		unit, ok := unitsByKey[key]
+		if !ok || len(key) == 0 {
+			unit = &shardUnit{serial: isSerialTest(test)}
+			units = append(units, unit)
+			if len(key) > 0 {
+				unitsByKey[key] = unit
+			}
+		}
+		unit.tests = append(unit.tests, test)
+		unit.duration += durationOf(test)
+	}
+
+	// longest processing time first: place the longest units first, each on the least loaded shard, the serial units
+	// on the shard with the least serial load.
+	sort.SliceStable(units, func(i, j int) bool {
+		if units[i].serial != units[j].serial {
+			return units[i].serial
+		}
+		return units[i].duration > units[j].duration
+	})
+	loads := make([]time.Duration, shard.Total)
+	serialLoads := make([]time.Duration, shard.Total)
+	ret := []*testCase{}
+	for _, unit := range units {
+		target := 0
+		for i := range loads {
+			if unit.serial && serialLoads[i] != serialLoads[target] {
+				if serialLoads[i] < serialLoads[target] {
+					target = i
+				}
+				continue
+			}
+			if loads[i] < loads[target] {
+				target = i
+			}
+		}
+		loads[target] += unit.duration
+		if unit.serial {
+			serialLoads[target] += unit.duration
+		}
+		if target == shard.Index-1 {
+			ret = append(ret, unit.tests...)
+		}
+	}
+
+	// keep the order the tests were given in.
+	inShard := map[*testCase]bool{}
+	for _, test := range ret {
+		inShard[test] = true
+	}
+	shardedTests := []*testCase{}
+	for _, test := range tests {
+		if inShard[test] {
+			shardedTests = append(shardedTests, test)
+		}
+	}
+	return shardedTests
+}
@@ -0,0 +1,113 @@
+package ginkgo
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseShard(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Shard
+		wantErr bool
+	}{
+		{value: "1/3", want: Shard{Index: 1, Total: 3}},
+		{value: "3/3", want: Shard{Index: 3, Total: 3}},
+		{value: "0/3", wantErr: true},
+		{value: "4/3", wantErr: true},
+		{value: "3", wantErr: true},
+		{value: "a/3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseShard(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_shardTests(t *testing.T) {
+	tests := []*testCase{}
+	durations := map[string]time.Duration{}
+	for i := 0; i < 30; i++ {
+		name := fmt.Sprintf("[sig-test] test %02d", i)
+		tests = append(tests, &testCase{name: name})
+		durations[name] = time.Duration(i+1) * time.Second
+	}
+	for i := 0; i < 5; i++ {
+		tests = append(tests, &testCase{name: fmt.Sprintf("[sig-test] serial %d [Serial]", i)})
+	}
+	tests = append(tests,
+		&testCase{name: "[sig-test] exclusive a", testExclusion: "exclusive"},
+		&testCase{name: "[sig-test] exclusive b", testExclusion: "exclusive"},
+	)
+
+	shardOf := map[string]int{}
+	loads := make([]time.Duration, 3)
+	for index := 1; index <= 3; index++ {
+		// every process shuffles the tests differently.
+		shuffled := copyTests(tests)
+		rand.New(rand.NewSource(int64(index))).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		for _, test := range shardTests(shuffled, Shard{Index: index, Total: 3}, durations) {
+			if previous, ok := shardOf[test.name]; ok {
+				t.Fatalf("%q is in shards %d and %d", test.name, previous, index)
+			}
+			shardOf[test.name] = index
+			duration, ok := durations[test.name]
+			if !ok {
+				duration = 15500 * time.Millisecond
+			}
+			loads[index-1] += duration
+		}
+	}
+	if len(shardOf) != len(tests) {
+		t.Fatalf("expected every test in a shard, got %d of %d", len(shardOf), len(tests))
+	}
+	serialTests := make([]int, 3)
+	for i := 0; i < 5; i++ {
+		serialTests[shardOf[fmt.Sprintf("[sig-test] serial %d [Serial]", i)]-1]++
+	}
+	for i := range serialTests {
+		if serialTests[i] < 1 || serialTests[i] > 2 {
+			t.Errorf("expected the serial tests spread over the shards, got %v", serialTests)
+		}
+	}
+	if shardOf["[sig-test] exclusive a"] != shardOf["[sig-test] exclusive b"] {
+		t.Errorf("expected tests sharing an exclusion in the same shard")
+	}
+	for i := range loads {
+		if loads[i] < loads[0]-20*time.Second || loads[i] > loads[0]+20*time.Second {
+			t.Errorf("expected balanced shards, got %v", loads)
+		}
+	}
+}
+
+func TestLoadTestDurations(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "junit_e2e_1.xml")
+	second := filepath.Join(dir, "junit_e2e_2.xml")
+	if err := os.WriteFile(first, []byte(`<testsuite name="openshift-tests"><testcase name="a" time="10"></testcase><testcase name="b" time="1"></testcase></testsuite>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(`<testsuites><testsuite name="openshift-tests"><testcase name="a" time="20"></testcase></testsuite></testsuites>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	durations, err := LoadTestDurations([]string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if durations["a"] != 15*time.Second || durations["b"] != time.Second {
+		t.Errorf("unexpected durations %v", durations)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"sort"
 	"sync"
+	"time"
 )
 
 type testSuiteProgress struct {
@@ -12,6 +13,10 @@ type testSuiteProgress struct {
 	failures int
 	index    int
 	total    int
+
+	// start and predicted are only set when the duration of the tests was predicted.
+	start     time.Time
+	predicted time.Duration
 }
 
 func newTestSuiteProgress(total int) *testSuiteProgress {
@@ -34,6 +39,37 @@ func (s *testSuiteProgress) LogTestStart(out io.Writer, testName string) {
 	fmt.Fprintf(out, "started: %d/%d/%d %q\n\n", s.failures, s.index, s.total, testName)
 }
 
+// LogPrediction records the predicted duration of the tests, knownCount is the number of tests with a historical
+// duration.
+func (s *testSuiteProgress) LogPrediction(out io.Writer, predicted time.Duration, knownCount int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.start = time.Now()
+	s.predicted = predicted
+	fmt.Fprintf(out, "predicted completion of %d tests in %s, %d of them have a historical duration\n\n", s.total, predicted.Round(time.Second), knownCount)
+}
+
+// LogCompletion compares how long the tests took with the predicted duration.
+func (s *testSuiteProgress) LogCompletion(out io.Writer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.start.IsZero() {
+		return
+	}
+	actual := time.Since(s.start)
+	fmt.Fprintf(out, "completed %d tests in %s, predicted %s (%+.0f%%)\n\n", s.index, actual.Round(time.Second), s.predicted.Round(time.Second), predictionError(s.predicted, actual))
+}
+
+// predictionError is how much longer, in percent, the tests took than predicted.
+func predictionError(predicted, actual time.Duration) float64 {
+	if predicted <= 0 {
+		return 0
+	}
+	return 100 * (actual.Seconds() - predicted.Seconds()) / predicted.Seconds()
+}
+
 func (s *testSuiteProgress) TestEnded(testName string, testRunResult *testRunResultHandle) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -0,0 +1,224 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	TestContextFileName = "test-context.json"
+
+	// maxTestContextSummaryIntervals bounds the intervals of a category listed in the junit of a test, the full
+	// list is in test-context.json.
+	maxTestContextSummaryIntervals = 5
+)
+
+// testContextCategory selects the intervals that commonly explain a test failure.
+type testContextCategory struct {
+	name    string
+	matches monitorapi.EventIntervalMatchesFunc
+}
+
+var testContextCategories = []testContextCategory{
+	{
+		name:    "Disruption",
+		matches: monitorapi.And(monitorapi.IsDisruptionEvent, monitorapi.IsErrorEvent),
+	},
+	{
+		name: "NodeNotReady",
+		matches: func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeNotReadyReason
+		},
+	},
+	{
+		name: "AlertFiring",
+		matches: monitorapi.And(
+			func(interval monitorapi.Interval) bool { return interval.Source == monitorapi.SourceAlert },
+			monitorapi.AlertFiring(),
+		),
+	},
+	{
+		name: "OperatorDegraded",
+		matches: func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceOperatorState &&
+				interval.Message.Annotations[monitorapi.AnnotationCondition] == "Degraded" &&
+				interval.Message.Annotations[monitorapi.AnnotationStatus] == "True"
+		},
+	},
+}
+
+// TestContext lists the intervals overlapping the runs of a failed or flaky test.
+type TestContext struct {
+	TestName string            `json:"testName"`
+	Runs     []*TestRunContext `json:"runs"`
+}
+
+type TestRunContext struct {
+	// Status is the status of the run, Failed or Flaked.
+	Status string    `json:"status"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	// Overlapping maps the category of intervals, Disruption, NodeNotReady, AlertFiring or OperatorDegraded, to the
+	// intervals of that category overlapping the run, cut to the run.
+	Overlapping map[string][]TestContextInterval `json:"overlapping,omitempty"`
+}
+
+type TestContextInterval struct {
+	Locator string    `json:"locator"`
+	Message string    `json:"message"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
+// e2eTestRun is one run of an e2e test, as recorded by the E2ETestStarted and E2ETestFinished intervals.
+type e2eTestRun struct {
+	name   string
+	status string
+	from   time.Time
+	to     time.Time
+}
+
+// e2eTestRuns pairs the E2ETestStarted and E2ETestFinished intervals of every test, runs that did not finish are
+// dropped.
+func e2eTestRuns(intervals monitorapi.Intervals) map[string][]e2eTestRun {
+	ret := map[string][]e2eTestRun{}
+	lastStart := map[string]time.Time{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceE2ETest {
+			continue
+		}
+		testName, ok := monitorapi.E2ETestFromLocator(interval.Locator)
+		if !ok {
+			continue
+		}
+		switch interval.Message.Reason {
+		case monitorapi.E2ETestStarted:
+			lastStart[testName] = interval.From
+		case monitorapi.E2ETestFinished:
+			from, ok := lastStart[testName]
+			if !ok {
+				continue
+			}
+			delete(lastStart, testName)
+			ret[testName] = append(ret[testName], e2eTestRun{
+				name:   testName,
+				status: interval.Message.Annotations[monitorapi.AnnotationStatus],
+				from:   from,
+				to:     interval.From,
+			})
+		}
+	}
+	return ret
+}
+
+// BuildTestContexts correlates the unsuccessful runs of every failed or flaky test of the suite with the intervals
+// overlapping them.
+func BuildTestContexts(suite *junitapi.JUnitTestSuite, intervals monitorapi.Intervals) []*TestContext {
+	runs := e2eTestRuns(intervals)
+	candidates := intervals.Filter(func(interval monitorapi.Interval) bool {
+		for _, category := range testContextCategories {
+			if category.matches(interval) {
+				return true
+			}
+		}
+		return false
+	})
+
+	ret := []*TestContext{}
+	seen := map[string]bool{}
+	for _, testCase := range suite.TestCases {
+		if testCase.FailureOutput == nil || seen[testCase.Name] {
+			continue
+		}
+		seen[testCase.Name] = true
+
+		testContext := &TestContext{TestName: testCase.Name}
+		for _, run := range runs[testCase.Name] {
+			if run.status == "Passed" || run.status == "Skipped" {
+				continue
+			}
+			runContext := &TestRunContext{
+				Status:      run.status,
+				From:        run.from,
+				To:          run.to,
+				Overlapping: map[string][]TestContextInterval{},
+			}
+			// Cut excludes the end of the run, which would drop what happened in the last instant of the run.
+			overlapping := candidates.Cut(run.from, run.to.Add(time.Nanosecond))
+			for _, category := range testContextCategories {
+				for _, interval := range overlapping.Filter(category.matches) {
+					runContext.Overlapping[category.name] = append(runContext.Overlapping[category.name], TestContextInterval{
+						Locator: interval.Locator.OldLocator(),
+						Message: interval.Message.OldMessage(),
+						From:    interval.From,
+						To:      interval.To,
+					})
+				}
+			}
+			testContext.Runs = append(testContext.Runs, runContext)
+		}
+		if len(testContext.Runs) > 0 {
+			ret = append(ret, testContext)
+		}
+	}
+	return ret
+}
+
+// Summary describes the intervals overlapping the runs of the test in a few lines.
+func (c *TestContext) Summary() string {
+	buf := &strings.Builder{}
+	for _, run := range c.Runs {
+		fmt.Fprintf(buf, "%s run from %s to %s overlapped:\n", run.Status, run.From.UTC().Format(time.RFC3339), run.To.UTC().Format(time.RFC3339))
+		if len(run.Overlapping) == 0 {
+			fmt.Fprintf(buf, "  no disruption, NotReady node, firing alert or Degraded operator\n")
+			continue
+		}
+		for _, category := range testContextCategories {
+			intervals := run.Overlapping[category.name]
+			if len(intervals) == 0 {
+				continue
+			}
+			fmt.Fprintf(buf, "  %d %s intervals\n", len(intervals), category.name)
+			for i, interval := range intervals {
+				if i == maxTestContextSummaryIntervals {
+					fmt.Fprintf(buf, "    ... and %d more in %s\n", len(intervals)-i, TestContextFileName)
+					break
+				}
+				fmt.Fprintf(buf, "    %s - %s %s %s\n", interval.From.UTC().Format(time.RFC3339), interval.To.UTC().Format(time.RFC3339), interval.Locator, interval.Message)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// attachTestContexts appends the summary of the context of every failed or flaky test to the SystemOut of its
+// failed junit test cases.
+func attachTestContexts(suite *junitapi.JUnitTestSuite, testContexts []*TestContext) {
+	byName := map[string]*TestContext{}
+	for _, testContext := range testContexts {
+		byName[testContext.TestName] = testContext
+	}
+	for _, testCase := range suite.TestCases {
+		testContext, ok := byName[testCase.Name]
+		if !ok || testCase.FailureOutput == nil {
+			continue
+		}
+		testCase.SystemOut = strings.TrimRight(testCase.SystemOut, "\n") + "\n\n" + testContext.Summary()
+	}
+}
+
+// WriteTestContexts writes the contexts to test-context.json in dir.
+func WriteTestContexts(dir string, testContexts []*TestContext) error {
+	content, err := json.MarshalIndent(testContexts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, TestContextFileName), content, 0644)
+}
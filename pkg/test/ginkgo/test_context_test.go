@@ -0,0 +1,112 @@
+package ginkgo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// testRunIntervals returns the intervals recorded for a run of the test.
+func testRunIntervals(name string, state TestState, from, to time.Time) monitorapi.Intervals {
+	return monitorapi.Intervals{
+		newTestStartedInterval(name).Build(from, from),
+		newTestFinishedInterval(&testRunResult{name: name, testState: state}).Build(to, to),
+	}
+}
+
+func TestBuildTestContexts(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, testRunIntervals("[sig-a] fails", TestFailed, start, start.Add(5*time.Minute))...)
+	intervals = append(intervals, testRunIntervals("[sig-b] flakes", TestFailed, start.Add(10*time.Minute), start.Add(11*time.Minute))...)
+	intervals = append(intervals, testRunIntervals("[sig-b] flakes", TestSucceeded, start.Add(12*time.Minute), start.Add(13*time.Minute))...)
+	intervals = append(intervals, testRunIntervals("[sig-c] passes", TestSucceeded, start, start.Add(5*time.Minute))...)
+	intervals = append(intervals,
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "", monitorapi.NewConnectionType)).
+			Message(monitorapi.NewMessage().HumanMessage("disruption")).
+			Build(start.Add(-time.Minute), start.Add(time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().NodeFromName("worker-a")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeNotReadyReason).HumanMessage("node is not ready")).
+			Build(start.Add(4*time.Minute), start.Add(12*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceOperatorState, monitorapi.Error).
+			Locator(monitorapi.NewLocator().ClusterOperator("ingress")).
+			Message(monitorapi.NewMessage().Reason("IngressDegraded").
+				WithAnnotation(monitorapi.AnnotationCondition, "Degraded").
+				WithAnnotation(monitorapi.AnnotationStatus, "True")).
+			Build(start.Add(10*time.Minute), start.Add(20*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Warning).
+			Locator(monitorapi.Locator{Type: monitorapi.LocatorTypeAlert, Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorAlertKey: "KubeNodeNotReady"}}).
+			Message(monitorapi.NewMessage().WithAnnotation(monitorapi.AnnotationAlertState, "pending")).
+			Build(start, start.Add(5*time.Minute)),
+		// a disruption after every test.
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "", monitorapi.NewConnectionType)).
+			Build(start.Add(30*time.Minute), start.Add(31*time.Minute)),
+	)
+
+	suite := &junitapi.JUnitTestSuite{
+		TestCases: []*junitapi.JUnitTestCase{
+			{Name: "[sig-a] fails", SystemOut: "output\n", FailureOutput: &junitapi.FailureOutput{Output: "fail [a]"}},
+			{Name: "[sig-b] flakes", FailureOutput: &junitapi.FailureOutput{Output: "flake: b"}},
+			{Name: "[sig-b] flakes"},
+			{Name: "[sig-c] passes"},
+		},
+	}
+	testContexts := BuildTestContexts(suite, intervals)
+	if len(testContexts) != 2 {
+		t.Fatalf("expected the contexts of 2 tests, got %d", len(testContexts))
+	}
+
+	fails := testContexts[0]
+	if fails.TestName != "[sig-a] fails" || len(fails.Runs) != 1 {
+		t.Fatalf("unexpected context %#v", fails)
+	}
+	overlapping := fails.Runs[0].Overlapping
+	if len(overlapping["Disruption"]) != 1 || len(overlapping["NodeNotReady"]) != 1 || len(overlapping["AlertFiring"]) != 0 {
+		t.Errorf("unexpected overlapping intervals %#v", overlapping)
+	}
+	if disruption := overlapping["Disruption"][0]; !disruption.From.Equal(start) {
+		t.Errorf("expected the disruption to be cut to the test run, got %v", disruption.From)
+	}
+
+	flakes := testContexts[1]
+	if flakes.TestName != "[sig-b] flakes" || len(flakes.Runs) != 1 || flakes.Runs[0].Status != "Failed" {
+		t.Fatalf("expected only the failed run of the flaky test, got %#v", flakes)
+	}
+	if overlapping := flakes.Runs[0].Overlapping; len(overlapping["NodeNotReady"]) != 1 || len(overlapping["OperatorDegraded"]) != 1 || len(overlapping["Disruption"]) != 0 {
+		t.Errorf("unexpected overlapping intervals %#v", overlapping)
+	}
+
+	attachTestContexts(suite, testContexts)
+	if systemOut := suite.TestCases[0].SystemOut; !strings.HasPrefix(systemOut, "output\n\nFailed run from 2024-05-01T10:00:00Z to 2024-05-01T10:05:00Z overlapped:\n") ||
+		!strings.Contains(systemOut, "1 Disruption intervals") {
+		t.Errorf("unexpected system out:\n%s", systemOut)
+	}
+	if systemOut := suite.TestCases[2].SystemOut; len(systemOut) > 0 {
+		t.Errorf("expected the successful run of the flaky test to be left alone, got:\n%s", systemOut)
+	}
+
+	dir := t.TempDir()
+	if err := WriteTestContexts(dir, testContexts); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, TestContextFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := []*TestContext{}
+	if err := json.Unmarshal(content, &read); err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 {
+		t.Errorf("expected 2 contexts in %s, got %d", TestContextFileName, len(read))
+	}
+}
@@ -44,9 +44,7 @@ func (r *testSuiteRunnerImpl) RunOneTest(ctx context.Context, test *testCase) {
 	defer r.maybeAbortOnFailureFn(testRunResult)
 
 	// record the test happening with the monitor
-	r.testOutput.monitorRecorder.AddIntervals(monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
-		Locator(monitorapi.NewLocator().E2ETest(test.name)).
-		Message(monitorapi.NewMessage().HumanMessage("started").Reason(monitorapi.E2ETestStarted)).BuildNow())
+	r.testOutput.monitorRecorder.AddIntervals(newTestStartedInterval(test.name).BuildNow())
 
 	defer recordTestResultInMonitor(testRunResult, r.testOutput.monitorRecorder)
 
@@ -57,6 +55,13 @@ func (r *testSuiteRunnerImpl) RunOneTest(ctx context.Context, test *testCase) {
 
 	testRunResult.testRunResult = r.commandContext.RunTestInNewProcess(ctx, test)
 	mutateTestCaseWithResults(test, testRunResult)
+
+	// a test interrupted by the cancellation of the run has no result worth resuming from.
+	if r.testOutput.checkpoint != nil && ctx.Err() == nil {
+		if err := r.testOutput.checkpoint.Record(test, testRunResult.testRunResult); err != nil {
+			fmt.Fprintf(r.testOutput.out, "error: Unable to checkpoint the result of %q: %v\n\n", test.name, err)
+		}
+	}
 }
 
 func mutateTestCaseWithResults(test *testCase, testRunResult *testRunResultHandle) {
@@ -125,6 +130,8 @@ type testOutputConfig struct {
 	testOutputLock  *sync.Mutex
 	out             io.Writer
 	monitorRecorder monitorapi.Recorder
+	// checkpoint, if set, records the result of every test so that an interrupted run can be resumed.
+	checkpoint *testCheckpoint
 
 	includeSuccessfulOutput bool
 }
@@ -197,21 +204,25 @@ func (c *commandContext) commandString(test *testCase) string {
 		parts := strings.SplitN(env, "=", 2)
 		fmt.Fprintf(buf, "%s=%q ", parts[0], parts[1])
 	}
-	testBinary, testName := c.extractCommands(test)
-	fmt.Fprintf(buf, "%s %s %q", testBinary, "run-test", testName)
+	testBinary, runArgs, testName := c.extractCommands(test)
+	fmt.Fprintf(buf, "%s %s %q", testBinary, strings.Join(runArgs, " "), testName)
 	return buf.String()
 }
 
-func (c *commandContext) extractCommands(test *testCase) (string, string) {
+func (c *commandContext) extractCommands(test *testCase) (string, []string, string) {
 	testBinary := test.binaryName
 	if len(testBinary) == 0 {
 		testBinary = os.Args[0]
 	}
+	runArgs := []string{"run-test"}
+	if test.externalBinary != nil {
+		runArgs = test.externalBinary.runArgs()
+	}
 	testName := test.rawName
 	if len(testName) == 0 {
 		testName = test.name
 	}
-	return testBinary, testName
+	return testBinary, runArgs, testName
 }
 
 func recordTestResultInLogWithoutOverlap(testRunResult *testRunResultHandle, testOutputLock *sync.Mutex, out io.Writer, includeSuccessfulOutput bool) {
@@ -258,6 +269,18 @@ func recordTestResultInLog(testRunResult *testRunResultHandle, out io.Writer, in
 }
 
 func recordTestResultInMonitor(testRunResult *testRunResultHandle, monitorRecorder monitorapi.Recorder) {
+	// Record an interval indicating that the test finished. Another interval will be created that
+	// links the start/stop intervals and has the duration for the test run in e2etest.go.
+	monitorRecorder.AddIntervals(newTestFinishedInterval(testRunResult.testRunResult).BuildNow())
+}
+
+func newTestStartedInterval(testName string) *monitorapi.IntervalBuilder {
+	return monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest(testName)).
+		Message(monitorapi.NewMessage().HumanMessage("started").Reason(monitorapi.E2ETestStarted))
+}
+
+func newTestFinishedInterval(testRunResult *testRunResult) *monitorapi.IntervalBuilder {
 	eventLevel := monitorapi.Warning
 
 	msg := monitorapi.NewMessage().HumanMessage("e2e test finished")
@@ -284,11 +307,9 @@ func recordTestResultInMonitor(testRunResult *testRunResultHandle, monitorRecord
 		msg = msg.WithAnnotation(monitorapi.AnnotationStatus, "Unknown")
 	}
 
-	// Record an interval indicating that the test finished. Another interval will be created that
-	// links the start/stop intervals and has the duration for the test run in e2etest.go.
-	monitorRecorder.AddIntervals(monitorapi.NewInterval(monitorapi.SourceE2ETest, eventLevel).
+	return monitorapi.NewInterval(monitorapi.SourceE2ETest, eventLevel).
 		Locator(monitorapi.NewLocator().E2ETest(testRunResult.name)).
-		Message(msg.HumanMessage("finished").Reason(monitorapi.E2ETestFinished)).BuildNow())
+		Message(msg.HumanMessage("finished").Reason(monitorapi.E2ETestFinished))
 }
 
 // RunTestInNewProcess runs a test case in a different process and returns a result
@@ -305,8 +326,8 @@ func (c *commandContext) RunTestInNewProcess(ctx context.Context, test *testCase
 	}
 
 	ret.start = time.Now()
-	testBinary, testName := c.extractCommands(test)
-	command := exec.Command(testBinary, "run-test", testName)
+	testBinary, runArgs, testName := c.extractCommands(test)
+	command := exec.Command(testBinary, append(append([]string{}, runArgs...), testName)...)
 	command.Env = append(os.Environ(), updateEnvVars(c.env)...)
 
 	timeout := c.timeout
@@ -1,7 +1,9 @@
 package ginkgo
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/onsi/ginkgo/v2"
@@ -45,12 +47,29 @@ func testsForSuite() ([]*testCase, error) {
 	return tests, nil
 }
 
+// TestNames returns the names of every test registered in openshift-tests, as they are reported.
+func TestNames() ([]string, error) {
+	tests, err := testsForSuite()
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, test := range tests {
+		names = append(names, test.name)
+	}
+	return names, nil
+}
+
 var re = regexp.MustCompile(`.*\[Timeout:(.[^\]]*)\]`)
 
 func newTestCaseFromGinkgoSpec(spec types.TestSpec) (*testCase, error) {
 	name := spec.Text()
+	if labels := ginkgoSpecLabels(spec); len(labels) > 0 {
+		recordSpecLabels(name, labels)
+	}
 	tc := &testCase{
 		name:      name,
+		labels:    TestLabelsOf(name),
 		locations: spec.CodeLocations(),
 		spec:      spec,
 	}
@@ -73,10 +92,14 @@ type testCase struct {
 	name string
 	// rawName is the name as reported by external binary
 	rawName string
-	// binaryName is the name of the external binary
+	// labels are parsed from the name, which includes the annotations, and the labels of the Ginkgo spec
+	labels TestLabels
+	// binaryName is the path of the extracted external binary
 	binaryName string
-	spec       types.TestSpec
-	locations  []types.CodeLocation
+	// externalBinary is the external binary providing the test
+	externalBinary *ExternalBinary
+	spec           types.TestSpec
+	locations      []types.CodeLocation
 
 	// identifies which tests can be run in parallel (ginkgo runs suites linearly)
 	testExclusion string
@@ -95,21 +118,37 @@ type testCase struct {
 	success  bool
 	timedOut bool
 
+	// quarantine is set when the failure of the test does not fail the run because of a known flake.
+	quarantine *Quarantine
+
 	previous *testCase
 }
 
 func (t *testCase) Retry() *testCase {
 	copied := &testCase{
 		name:          t.name,
+		labels:        t.labels,
 		spec:          t.spec,
 		locations:     t.locations,
 		testExclusion: t.testExclusion,
 
+		rawName:        t.rawName,
+		binaryName:     t.binaryName,
+		externalBinary: t.externalBinary,
+
 		previous: t,
 	}
 	return copied
 }
 
+// Labels returns the labels of the test, looked up by its name when the test wasn't built with them.
+func (t *testCase) Labels() TestLabels {
+	if t.labels == nil {
+		return TestLabelsOf(t.name)
+	}
+	return t.labels
+}
+
 type ClusterStabilityDuringTest string
 
 var (
@@ -127,6 +166,10 @@ type TestSuite struct {
 	Description string
 
 	Matches TestMatchFunc
+	// Selector, when set, is also required to match the labels of the tests.
+	Selector LabelSelector
+	// RequiredFilters are the named conditions added to Matches, which explain why the tests are excluded.
+	RequiredFilters []TestFilter
 
 	// The number of times to execute each test in this suite.
 	Count int
@@ -134,6 +177,9 @@ type TestSuite struct {
 	Parallelism int
 	// The number of flakes that may occur before this test is marked as a failure.
 	MaximumAllowedFlakes int
+	// RetryPolicy decides which failing tests run again to detect flakes, when no more than MaximumAllowedFlakes
+	// tests failed.  Defaults to DefaultRetryPolicy.
+	RetryPolicy RetryPolicy
 
 	ClusterStabilityDuringTest ClusterStabilityDuringTest
 
@@ -142,12 +188,31 @@ type TestSuite struct {
 
 type TestMatchFunc func(name string) bool
 
+// TestFilter is a named condition the tests of a suite must meet.
+type TestFilter struct {
+	// Name is the reason the tests not matching are excluded, for instance "missing API group".
+	Name    string
+	Matches TestMatchFunc
+	// Explain optionally details why a test doesn't match, for instance the API groups the cluster doesn't serve.
+	Explain func(name string) string
+}
+
+func (s *TestSuite) retryPolicy() RetryPolicy {
+	if s.RetryPolicy == nil {
+		return DefaultRetryPolicy()
+	}
+	return s.RetryPolicy
+}
+
 func (s *TestSuite) Filter(tests []*testCase) []*testCase {
 	matches := make([]*testCase, 0, len(tests))
 	for _, test := range tests {
 		if !s.Matches(test.name) {
 			continue
 		}
+		if !s.Selector.Matches(test.Labels()) {
+			continue
+		}
 		matches = append(matches, test)
 	}
 	return matches
@@ -168,6 +233,37 @@ func (s *TestSuite) AddRequiredMatchFunc(matchFn TestMatchFunc) {
 	}
 }
 
+// AddRequiredFilter requires the tests of the suite to match the filter, which Exclusion reports as the reason for
+// excluding the tests not matching.
+func (s *TestSuite) AddRequiredFilter(filter TestFilter) {
+	if filter.Matches == nil {
+		return
+	}
+	s.AddRequiredMatchFunc(filter.Matches)
+	s.RequiredFilters = append(s.RequiredFilters, filter)
+}
+
+// Exclusion explains why the suite excludes a test matching its selector, or returns an empty string when the suite
+// runs the test.
+func (s *TestSuite) Exclusion(name string) string {
+	for _, filter := range s.RequiredFilters {
+		if filter.Matches(name) {
+			continue
+		}
+		if filter.Explain == nil {
+			return filter.Name
+		}
+		return fmt.Sprintf("%s: %s", filter.Name, filter.Explain(name))
+	}
+	if s.Matches(name) {
+		return ""
+	}
+	if labels := TestLabelsOf(name); labels.Disabled(time.Now()) {
+		return fmt.Sprintf("disabled: %s", strings.Join(append(labels.Values(LabelDisabled), labels.Values(LabelSkippedUntil)...), ", "))
+	}
+	return fmt.Sprintf("not in suite %s", s.Name)
+}
+
 func testNames(tests []*testCase) []string {
 	var names []string
 	for _, t := range tests {
@@ -2,13 +2,15 @@ package testsuites
 
 import (
 	"strings"
+
+	"github.com/openshift/origin/pkg/test/ginkgo"
 )
 
 // Determines whether a test should be run for third-party network plugin conformance testing
-func inCNISuite(name string) bool {
-	if strings.Contains(name, "[Suite:k8s]") && strings.Contains(name, "[sig-network]") {
+func inCNISuite(name string, labels ginkgo.TestLabels) bool {
+	if labels.HasValue(ginkgo.LabelSuite, "k8s") && labels.HasValue(ginkgo.LabelSig, "sig-network") {
 		// Run all upstream sig-network conformance tests
-		if strings.Contains(name, "[Conformance]") {
+		if labels.Has("Conformance") {
 			return true
 		}
 
@@ -21,7 +23,7 @@ func inCNISuite(name string) bool {
 
 		// Include dual-stack tests in the test suite; they will automatically get
 		// filtered out if the cluster is single-stack.
-		if strings.Contains(name, "[Feature:IPv6DualStack]") {
+		if labels.HasValue(ginkgo.LabelFeature, "IPv6DualStack") {
 			return true
 		}
 	}
@@ -3,8 +3,6 @@ package testsuites
 import (
 	"bytes"
 	"fmt"
-	"regexp"
-	"strings"
 	"time"
 
 	"github.com/openshift/origin/pkg/test/ginkgo"
@@ -21,60 +19,24 @@ func SuitesString(suites []*ginkgo.TestSuite, prefix string) string {
 	return buf.String()
 }
 
-func isDisabled(name string) bool {
-	if strings.Contains(name, "[Disabled") {
-		return true
-	}
-
-	return shouldSkipUntil(name)
-}
-
-// shouldSkipUntil allows a test to be skipped with a time limit.
-// the test should be annotated with the 'SkippedUntil' tag, as shown below.
-//
-//	[SkippedUntil:05092022:blocker-bz/123456]
-//
-// - the specified date should conform to the 'MMDDYYYY' format.
-// - a valid blocker BZ must be specified
-// if the specified date in the tag has not passed yet, the test
-// will be skipped by the runner.
-func shouldSkipUntil(name string) bool {
-	re, err := regexp.Compile(`\[SkippedUntil:(\d{8}):blocker-bz\/([a-zA-Z0-9]+)\]`)
-	if err != nil {
-		// it should only happen with a programmer error and unit
-		// test will prevent that
-		return false
-	}
-	matches := re.FindStringSubmatch(name)
-	if len(matches) != 3 {
-		return false
-	}
-
-	skipUntil, err := time.Parse("01022006", matches[1])
-	if err != nil {
-		return false
-	}
-
-	if skipUntil.After(time.Now()) {
-		return true
-	}
-	return false
+func isDisabled(labels ginkgo.TestLabels) bool {
+	return labels.Disabled(time.Now())
 }
 
 // isStandardEarlyTest returns true if a test is considered part of the normal
 // pre or post condition tests.
-func isStandardEarlyTest(name string) bool {
-	if !strings.Contains(name, "[Early]") {
+func isStandardEarlyTest(labels ginkgo.TestLabels) bool {
+	if !labels.Has(ginkgo.LabelEarly) {
 		return false
 	}
-	return strings.Contains(name, "[Suite:openshift/conformance/parallel")
+	return labels.HasValue(ginkgo.LabelSuite, "openshift/conformance/parallel")
 }
 
 // isStandardEarlyOrLateTest returns true if a test is considered part of the normal
 // pre or post condition tests.
-func isStandardEarlyOrLateTest(name string) bool {
-	if !strings.Contains(name, "[Early]") && !strings.Contains(name, "[Late]") {
+func isStandardEarlyOrLateTest(labels ginkgo.TestLabels) bool {
+	if !labels.Has(ginkgo.LabelEarly) && !labels.Has(ginkgo.LabelLate) {
 		return false
 	}
-	return strings.Contains(name, "[Suite:openshift/conformance/parallel")
+	return labels.HasValue(ginkgo.LabelSuite, "openshift/conformance/parallel")
 }
@@ -35,10 +35,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that ensure an OpenShift cluster and components are working properly.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/conformance/")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/conformance")
 		},
 		Parallelism: 30,
 	},
@@ -48,10 +49,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Only the portion of the openshift/conformance test suite that run in parallel.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/conformance/parallel")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/conformance/parallel")
 		},
 		Parallelism:          30,
 		MaximumAllowedFlakes: 15,
@@ -62,10 +64,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Only the portion of the openshift/conformance test suite that run serially.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/conformance/serial") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/conformance/serial") || isStandardEarlyOrLateTest(labels)
 		},
 		TestTimeout: 40 * time.Minute,
 	},
@@ -76,14 +79,15 @@ var staticSuites = []ginkgo.TestSuite{
 		changing the global cluster configuration in a way that can affect other tests.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
 			// excluded due to stopped instance handling until https://bugzilla.redhat.com/show_bug.cgi?id=1905709 is fixed
 			if strings.Contains(name, "Cluster should survive master and worker failure and recover with machine health checks") {
 				return false
 			}
-			return strings.Contains(name, "[Feature:EtcdRecovery]") || strings.Contains(name, "[Feature:NodeRecovery]") || isStandardEarlyTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "EtcdRecovery") || labels.HasValue(ginkgo.LabelFeature, "NodeRecovery") || isStandardEarlyTest(labels)
 
 		},
 		// Duration of the quorum restore test exceeds 60 minutes.
@@ -96,10 +100,11 @@ var staticSuites = []ginkgo.TestSuite{
 		The default Kubernetes conformance suite.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:k8s]") && strings.Contains(name, "[Conformance]")
+			return labels.HasValue(ginkgo.LabelSuite, "k8s") && labels.Has("Conformance")
 		},
 		Parallelism: 30,
 	},
@@ -109,10 +114,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that exercise the OpenShift build functionality.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Feature:Builds]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "Builds") || isStandardEarlyOrLateTest(labels)
 		},
 		Parallelism: 7,
 		// TODO: Builds are really flaky right now, remove when we land perf updates and fix io on workers
@@ -126,10 +132,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that exercise the OpenShift template functionality.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Feature:Templates]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "Templates") || isStandardEarlyOrLateTest(labels)
 		},
 		Parallelism: 1,
 	},
@@ -139,10 +146,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that exercise the OpenShift image-registry functionality.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) || strings.Contains(name, "[Local]") {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) || labels.Has("Local") {
 				return false
 			}
-			return strings.Contains(name, "[sig-imageregistry]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelSig, "sig-imageregistry") || isStandardEarlyOrLateTest(labels)
 		},
 	},
 	{
@@ -151,10 +159,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that exercise language and tooling images shipped as part of OpenShift.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) || strings.Contains(name, "[Local]") {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) || labels.Has("Local") {
 				return false
 			}
-			return strings.Contains(name, "[Feature:ImageEcosystem]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "ImageEcosystem") || isStandardEarlyOrLateTest(labels)
 		},
 		Parallelism: 7,
 		TestTimeout: 20 * time.Minute,
@@ -165,10 +174,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that exercise the OpenShift / Jenkins integrations provided by the OpenShift Jenkins image/plugins and the Pipeline Build Strategy.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Feature:Jenkins]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "Jenkins") || isStandardEarlyOrLateTest(labels)
 		},
 		Parallelism: 4,
 		TestTimeout: 20 * time.Minute,
@@ -179,10 +189,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that exercise the OpenShift / Jenkins integrations provided by the OpenShift Jenkins image/plugins and the Pipeline Build Strategy.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Feature:JenkinsRHELImagesOnly]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "JenkinsRHELImagesOnly") || isStandardEarlyOrLateTest(labels)
 		},
 		Parallelism: 4,
 		TestTimeout: 20 * time.Minute,
@@ -193,10 +204,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Tests that verify the scalability characteristics of the cluster. Currently this is focused on core performance behaviors and preventing regressions.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/scalability]")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/scalability")
 		},
 		Parallelism: 1,
 		TestTimeout: 20 * time.Minute,
@@ -207,10 +219,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Run only tests that are excluded from conformance. Makes identifying omitted tests easier.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return !strings.Contains(name, "[Suite:openshift/conformance/")
+			return !labels.HasValue(ginkgo.LabelSuite, "openshift/conformance")
 		},
 	},
 	{
@@ -219,10 +232,11 @@ var staticSuites = []ginkgo.TestSuite{
 		Run only tests for test-cmd.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Feature:LegacyCommandTests]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelFeature, "LegacyCommandTests") || isStandardEarlyOrLateTest(labels)
 		},
 	},
 	{
@@ -233,10 +247,11 @@ var staticSuites = []ginkgo.TestSuite{
 		See https://github.com/kubernetes/kubernetes/blob/master/test/e2e/storage/external/README.md for required format of the file.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "External Storage [Driver:") && !strings.Contains(name, "[Disruptive]")
+			return strings.Contains(name, "External Storage") && labels.Has("Driver") && !labels.Has("Disruptive")
 		},
 	},
 	{
@@ -245,10 +260,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite performs IPsec e2e tests covering control plane and data plane for east west and north south traffic scenarios.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/network/ipsec")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/network/ipsec")
 		},
 		Parallelism: 1,
 		TestTimeout: 120 * time.Minute,
@@ -259,18 +275,19 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite repeatedly verifies the networking function of the cluster in parallel to find flakes.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
 			// Skip NetworkPolicy tests for https://bugzilla.redhat.com/show_bug.cgi?id=1980141
-			if strings.Contains(name, "[Feature:NetworkPolicy]") {
+			if labels.HasValue(ginkgo.LabelFeature, "NetworkPolicy") {
 				return false
 			}
 			// Serial:Self are tests that can't be run in parallel with a copy of itself
-			if strings.Contains(name, "[Serial:Self]") {
+			if labels.HasValue(ginkgo.LabelSerial, "Self") {
 				return false
 			}
-			return (strings.Contains(name, "[Suite:openshift/conformance/") && strings.Contains(name, "[sig-network]")) || isStandardEarlyOrLateTest(name)
+			return (labels.HasValue(ginkgo.LabelSuite, "openshift/conformance") && labels.HasValue(ginkgo.LabelSig, "sig-network")) || isStandardEarlyOrLateTest(labels)
 		},
 		Parallelism: 60,
 		Count:       12,
@@ -282,10 +299,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite performs CNI live migration either from SDN to OVN-Kubernetes or OVN-Kubernetes to SDN.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/network/live-migration")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/network/live-migration")
 		},
 		Count:                      1,
 		TestTimeout:                4 * time.Hour,
@@ -297,10 +315,11 @@ var staticSuites = []ginkgo.TestSuite{
 		The conformance testing suite for certified third-party CNI plugins.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return inCNISuite(name)
+			return inCNISuite(name, labels)
 		},
 	},
 	{
@@ -314,7 +333,8 @@ var staticSuites = []ginkgo.TestSuite{
 			if !exists {
 				return false
 			}
-			return !isDisabled(name) && strings.Contains(name, "[Suite:openshift/conformance/parallel")
+			labels := ginkgo.TestLabelsOf(name)
+			return !isDisabled(labels) && labels.HasValue(ginkgo.LabelSuite, "openshift/conformance/parallel")
 		},
 		Parallelism:          20,
 		MaximumAllowedFlakes: 15,
@@ -334,10 +354,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite runs vertical scaling tests to exercise the safe scale-up and scale-down of etcd members.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/etcd/scaling") || strings.Contains(name, "[Feature:EtcdVerticalScaling]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/etcd/scaling") || labels.HasValue(ginkgo.LabelFeature, "EtcdVerticalScaling") || isStandardEarlyOrLateTest(labels)
 		},
 		// etcd's vertical scaling test can take a while for apiserver rollouts to stabilize on the same revision
 		TestTimeout: 60 * time.Minute,
@@ -348,10 +369,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite runs etcd recovery tests to exercise the safe restore process of etcd members.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/etcd/recovery") || strings.Contains(name, "[Feature:EtcdRecovery]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/etcd/recovery") || labels.HasValue(ginkgo.LabelFeature, "EtcdRecovery") || isStandardEarlyOrLateTest(labels)
 		},
 		// etcd's restore test can take a while for apiserver rollouts to stabilize
 		Parallelism:                1,
@@ -364,10 +386,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite runs etcd cert rotation tests to exercise the the automatic and manual certificate rotation.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/etcd/certrotation") || strings.Contains(name, "[Feature:CertRotation]") || isStandardEarlyOrLateTest(name)
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/etcd/certrotation") || labels.HasValue(ginkgo.LabelFeature, "CertRotation") || isStandardEarlyOrLateTest(labels)
 		},
 		TestTimeout:                60 * time.Minute,
 		Parallelism:                1,
@@ -379,10 +402,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite runs tests to validate realtime functionality on nodes.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/nodes/realtime")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/nodes/realtime")
 		},
 		TestTimeout: 30 * time.Minute,
 	},
@@ -392,10 +416,11 @@ var staticSuites = []ginkgo.TestSuite{
 		This test suite runs tests to validate realtime latency on nodes.
 		`),
 		Matches: func(name string) bool {
-			if isDisabled(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isDisabled(labels) {
 				return false
 			}
-			return strings.Contains(name, "[Suite:openshift/nodes/realtime/latency")
+			return labels.HasValue(ginkgo.LabelSuite, "openshift/nodes/realtime/latency")
 		},
 		TestTimeout: 30 * time.Minute,
 	},
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo"
 )
 
 func TestSkippedUntil(t *testing.T) {
@@ -67,7 +69,7 @@ func TestSkippedUntil(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			t.Logf("test name: %s", test.testName)
-			got := shouldSkipUntil(test.testName)
+			got := ginkgo.ParseTestLabels(test.testName).SkippedUntil(time.Now())
 			if test.skipped != got {
 				t.Errorf("Expected: %v, but got: %v", test.skipped, got)
 			}
@@ -1,7 +1,6 @@
 package testsuites
 
 import (
-	"strings"
 	"time"
 
 	"github.com/openshift/origin/pkg/test/ginkgo"
@@ -25,10 +24,11 @@ var upgradeSuites = []ginkgo.TestSuite{
 		Run all tests.
 		`),
 		Matches: func(name string) bool {
-			if isStandardEarlyTest(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isStandardEarlyTest(labels) {
 				return true
 			}
-			return strings.Contains(name, "[Feature:ClusterUpgrade]") && !strings.Contains(name, "[Suite:k8s]")
+			return labels.HasValue(ginkgo.LabelFeature, "ClusterUpgrade") && !labels.HasValue(ginkgo.LabelSuite, "k8s")
 		},
 		TestTimeout: 240 * time.Minute,
 	},
@@ -38,10 +38,11 @@ var upgradeSuites = []ginkgo.TestSuite{
 		Run only the tests that verify the platform remains available.
 		`),
 		Matches: func(name string) bool {
-			if isStandardEarlyTest(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isStandardEarlyTest(labels) {
 				return true
 			}
-			return strings.Contains(name, "[Feature:ClusterUpgrade]") && !strings.Contains(name, "[Suite:k8s]")
+			return labels.HasValue(ginkgo.LabelFeature, "ClusterUpgrade") && !labels.HasValue(ginkgo.LabelSuite, "k8s")
 		},
 		TestTimeout: 240 * time.Minute,
 	},
@@ -51,10 +52,11 @@ var upgradeSuites = []ginkgo.TestSuite{
 	Don't run disruption tests.
 		`),
 		Matches: func(name string) bool {
-			if isStandardEarlyTest(name) {
+			labels := ginkgo.TestLabelsOf(name)
+			if isStandardEarlyTest(labels) {
 				return true
 			}
-			return strings.Contains(name, "[Feature:ClusterUpgrade]") && !strings.Contains(name, "[Suite:k8s]")
+			return labels.HasValue(ginkgo.LabelFeature, "ClusterUpgrade") && !labels.HasValue(ginkgo.LabelSuite, "k8s")
 		},
 		TestTimeout: 240 * time.Minute,
 	},
@@ -75,6 +75,7 @@ var (
 	upgradeTests               = []upgrades.Test{}
 	upgradeAbortAt             int
 	upgradeDisruptRebootPolicy string
+	upgradePausedPools         []string
 )
 
 // upgradeAbortAtRandom is a special value indicating the abort should happen at a random percentage
@@ -105,6 +106,16 @@ func SetUpgradeDisruptReboot(policy string) error {
 	}
 }
 
+// SetUpgradePausedPool adds a machine config pool to pause before the first upgrade and to unpause once the
+// cluster version reached the last upgrade, so that the nodes of the pool update once to the final version.
+func SetUpgradePausedPool(name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("pause-pool must be the name of a machine config pool")
+	}
+	upgradePausedPools = append(upgradePausedPools, name)
+	return nil
+}
+
 // SetUpgradeAbortAt defines abort behavior during an upgrade. Allowed values are:
 //
 // * empty string - do not abort
@@ -184,9 +195,10 @@ var _ = g.Describe("[sig-arch][Feature:ClusterUpgrade]", func() {
 			upgradeTests,
 			func() {
 				for i := 1; i < len(upgCtx.Versions); i++ {
+					hop := upgradeHop{number: i, count: len(upgCtx.Versions) - 1}
 					framework.ExpectNoError(
-						clusterUpgrade(f, client, dynamicClient, config, upgCtx.Versions[i]),
-						fmt.Sprintf("during upgrade to %s", upgCtx.Versions[i].NodeImage))
+						clusterUpgrade(f, client, dynamicClient, config, upgCtx.Versions[i], hop),
+						fmt.Sprintf("during %s to %s", hop, upgCtx.Versions[i].NodeImage))
 				}
 			},
 		)
@@ -314,14 +326,42 @@ func getUpgradeContext(c configv1client.Interface, upgradeImage string) (*upgrad
 
 var errControlledAbort = fmt.Errorf("beginning abort")
 
-func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, version upgrades.VersionContext) error {
+// upgradeHop is one of the sequential upgrades of a comma delimited list of images.
+type upgradeHop struct {
+	// number starts at 1.
+	number int
+	count  int
+}
+
+func (h upgradeHop) first() bool {
+	return h.number == 1
+}
+
+func (h upgradeHop) last() bool {
+	return h.number == h.count
+}
+
+func (h upgradeHop) String() string {
+	return fmt.Sprintf("upgrade %d of %d", h.number, h.count)
+}
+
+// testName suffixes the name of a test recorded during an intermediate hop with the hop. The last hop keeps the
+// unsuffixed name, so single hop upgrades and the tests of the final version keep their names and history.
+func (h upgradeHop) testName(name string) string {
+	if h.last() {
+		return name
+	}
+	return fmt.Sprintf("%s [hop:%d/%d]", name, h.number, h.count)
+}
+
+func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynamic.Interface, config *rest.Config, version upgrades.VersionContext, hop upgradeHop) error {
 	fmt.Fprintf(os.Stderr, "\n\n\n")
 	defer func() { fmt.Fprintf(os.Stderr, "\n\n\n") }()
 
 	// ignore the failure here, we don't want this to fail the upgrade, we want it to fail this particular test.
 	_ = disruption.RecordJUnit(
 		f,
-		"[bz-Routing] console is not available via ingress",
+		hop.testName("[bz-Routing] console is not available via ingress"),
 		func() (error, bool) {
 			pollErr := wait.PollImmediateWithContext(context.TODO(), 1*time.Second, 10*time.Minute, func(ctx context.Context) (bool, error) {
 				consoleSampler := disruptioningress.CreateConsoleRouteAvailableWithNewConnections(config)
@@ -415,9 +455,18 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	}
 	framework.Logf("Upgrade time limit set as %0.2f", upgradeDurationLimit.Minutes())
 
-	framework.Logf("Starting upgrade to version=%s image=%s attempt=%s", version.Version.String(), version.NodeImage, uid)
+	framework.Logf("Starting %s to version=%s image=%s attempt=%s", hop, version.Version.String(), version.NodeImage, uid)
 	recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeStartedReason, fmt.Sprintf("version/%s image/%s", version.Version.String(), version.NodeImage), false)
 
+	// the pools stay paused across the hops until the cluster version reached the last one
+	if hop.first() && len(upgradePausedPools) > 0 {
+		if err := setPoolsPaused(dc, upgradePausedPools, true); err != nil {
+			recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to pause pools: %v", err), true)
+			return err
+		}
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradePoolsPausedReason, fmt.Sprintf("pools/%s", strings.Join(upgradePausedPools, ",")), false)
+	}
+
 	// decide whether to abort at a percent
 	abortAt := upgradeAbortAt
 	switch abortAt {
@@ -446,12 +495,12 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	defer monitor.Describe(f)
 
 	//used below in separate paths
-	clusterCompletesUpgradeTestName := "[sig-cluster-lifecycle] Cluster completes upgrade"
+	clusterCompletesUpgradeTestName := hop.testName("[sig-cluster-lifecycle] Cluster completes upgrade")
 
 	// trigger the update and record verification as an independent step
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] Cluster version operator acknowledges upgrade",
+		hop.testName("[sig-cluster-lifecycle] Cluster version operator acknowledges upgrade"),
 		func() (error, bool) {
 			cv, err := c.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
 			if err != nil {
@@ -586,7 +635,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 			// record whether the cluster was fast or slow upgrading.  Don't fail the test, we still want signal on the actual tests themselves.
 			upgradeEnded := time.Now()
 			upgradeDuration := upgradeEnded.Sub(upgradeStarted)
-			testCaseName := fmt.Sprintf("[sig-cluster-lifecycle] cluster upgrade should complete in a reasonable time")
+			testCaseName := hop.testName("[sig-cluster-lifecycle] cluster upgrade should complete in a reasonable time")
 			failure := ""
 			if upgradeDuration > upgradeDurationLimit {
 				failure = fmt.Sprintf("%s to %s took too long: %0.2f minutes (for this platform/network, it should be less than %0.2f minutes)", action, versionString(desired), upgradeDuration.Minutes(), upgradeDurationLimit.Minutes())
@@ -600,10 +649,19 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 		return err
 	}
 
+	// the nodes of the paused pools update straight to the final version once unpaused
+	if hop.last() && len(upgradePausedPools) > 0 {
+		if err := setPoolsPaused(dc, upgradePausedPools, false); err != nil {
+			recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradeFailedReason, fmt.Sprintf("failed to unpause pools: %v", err), true)
+			return err
+		}
+		recordClusterEvent(kubeClient, uid, "Upgrade", monitorapi.UpgradePoolsUnpausedReason, fmt.Sprintf("pools/%s", strings.Join(upgradePausedPools, ",")), false)
+	}
+
 	var errMasterUpdating error
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-mco] Machine config pools complete upgrade",
+		hop.testName("[sig-mco] Machine config pools complete upgrade"),
 		func() (error, bool) {
 			framework.Logf("Waiting on pools to be upgraded")
 			if err := wait.PollImmediate(10*time.Second, 30*time.Minute, func() (bool, error) {
@@ -647,7 +705,7 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 
 	if err := disruption.RecordJUnit(
 		f,
-		"[sig-cluster-lifecycle] ClusterOperators are available and not degraded after upgrade",
+		hop.testName("[sig-cluster-lifecycle] ClusterOperators are available and not degraded after upgrade"),
 		func() (error, bool) {
 			if err := operator.WaitForOperatorsToSettle(context.TODO(), c); err != nil {
 				return err, false
@@ -663,6 +721,23 @@ func clusterUpgrade(f *framework.Framework, c configv1client.Interface, dc dynam
 	return nil
 }
 
+// setPoolsPaused pauses or unpauses the machine config pools.
+func setPoolsPaused(dc dynamic.Interface, names []string, paused bool) error {
+	mcps := dc.Resource(schema.GroupVersionResource{
+		Group:    "machineconfiguration.openshift.io",
+		Version:  "v1",
+		Resource: "machineconfigpools",
+	})
+	patch := []byte(fmt.Sprintf(`{"spec":{"paused":%t}}`, paused))
+	for _, name := range names {
+		framework.Logf("Setting paused=%t on machine config pool %s", paused, name)
+		if _, err := mcps.Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("unable to set paused=%t on machine config pool %s: %v", paused, name, err)
+		}
+	}
+	return nil
+}
+
 // recordClusterEvent attempts to record an event to the cluster to indicate actions taken during an
 // upgrade for timeline review.
 func recordClusterEvent(client kubernetes.Interface, uid, action string, reason monitorapi.IntervalReason, note string, warning bool) {
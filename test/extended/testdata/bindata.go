@@ -54748,7 +54748,7 @@ var _e2echartTestRiskAnalysisHtml = []byte(`<html lang="en">
           content="Risk analysis is performed by Sippy to attempt to determine if the failures in this job are abnormal when compared to results for similar jobs over the past week, and amidst on-going incidents in the CI infrastructure. Risk analysis API will not catch everything and is a relatively simple implementation today, please reach out to the Technical Release Team if you spot abnormalities or have suggestions.">
 </head>
 <body onLoad="buildTestCaseTable('#test_case_results'); buildDisruptionTable('#disruption_results')">
-<p>
+<p id="sippy_link">
     <a target="_blank" href="TEST_RISK_ANALYSIS_SIPPY_URL_GOES_HERE">Link to Sippy</a>
 </p>
 
@@ -54777,8 +54777,13 @@ alarming.<p/>
 <script>
     var testResult = TEST_RISK_ANALYSIS_JSON_GOES_HERE
     var disruptionResult = TEST_DISRUPTION_ANALYSIS_JSON_GOES_HERE
-    var testLinkPrefix = "https://sippy.dptools.openshift.org/sippy-ng/tests/"
+    // the sippy url is empty when the risk analysis was computed locally
+    var sippyURL = "TEST_RISK_ANALYSIS_SIPPY_URL_GOES_HERE"
+    var testLinkPrefix = sippyURL + "tests/"
     var testLinkSuffix = "/analysis?test="
+    if (sippyURL.length == 0) {
+        $('#sippy_link').remove()
+    }
 
     function buildOpenBugs(openBugs) {
         td$ = $('<td/>')
@@ -54844,8 +54849,12 @@ alarming.<p/>
         // Build rows for all tests
         for (var i = 0; i < testResult.Tests.length; i++) {
             var row$ = $('<tr/>');
-            testUrl = encodeURI(testLinkPrefix + testResult.CompareRelease + testLinkSuffix + testResult.Tests[i].Name)
-            row$.append($('<td/>').html("<a target=\"_blank\" href=" + testUrl + ">" + testResult.Tests[i].Name + "</a>"));
+            if (sippyURL.length > 0) {
+                testUrl = encodeURI(testLinkPrefix + testResult.CompareRelease + testLinkSuffix + testResult.Tests[i].Name)
+                row$.append($('<td/>').html("<a target=\"_blank\" href=" + testUrl + ">" + testResult.Tests[i].Name + "</a>"));
+            } else {
+                row$.append($('<td/>').text(testResult.Tests[i].Name));
+            }
             row$.append(buildRiskLevel(testResult.Tests[i].Risk.Level))
             row$.append(buildRiskReasons(testResult.Tests[i].Risk.Reasons))
             row$.append(buildOpenBugs(testResult.Tests[i].OpenBugs))
@@ -5,13 +5,15 @@ import (
 
 	"k8s.io/kubernetes/openshift-hack/e2e/annotate"
 
+	"github.com/openshift/origin/test/extended/util/annotate/rules"
+
 	// this ensures that all origin tests are picked by ginkgo as defined
 	// in test/extended/include.go
 	_ "github.com/openshift/origin/test/extended"
 )
 
 func main() {
-	annotate.Run(testMaps, func(name string) bool {
+	annotate.Run(rules.TestMaps, func(name string) bool {
 		return strings.Contains(name, "[Suite:k8s]")
 	})
 }
@@ -1,4 +1,6 @@
-package main
+// Package rules holds the annotate rules of the origin tests, the annotate command applies them to generate the
+// annotations and openshift-tests explains the labels they add.
+package rules
 
 // Rules defined here are additive to the rules already defined for
 // kube e2e tests in openshift/kubernetes. The kube rules are
@@ -14,7 +16,8 @@ package main
 // providers) should be added here.
 
 var (
-	testMaps = map[string][]string{
+	// TestMaps are the labels appended to the names of the tests matching any of their regexes.
+	TestMaps = map[string][]string{
 		// tests that require a local host
 		"[Local]": {
 			// Doesn't work on scaled up clusters